@@ -2,21 +2,22 @@
 package actorsfakes
 
 import (
-	"os"
 	"sync"
 
 	"github.com/cloudfoundry/cli/cf/actors"
 	"github.com/cloudfoundry/cli/cf/api/resources"
+	"github.com/cloudfoundry/cli/cf/appfiles"
 	"github.com/cloudfoundry/cli/cf/models"
 )
 
 type FakePushActor struct {
-	UploadAppStub        func(appGUID string, zipFile *os.File, presentFiles []resources.AppFileResource) error
+	UploadAppStub        func(appGUID string, uploadDir string, presentFiles []resources.AppFileResource, onRetry func(attempt int, maxAttempts int)) error
 	uploadAppMutex       sync.RWMutex
 	uploadAppArgsForCall []struct {
 		appGUID      string
-		zipFile      *os.File
+		uploadDir    string
 		presentFiles []resources.AppFileResource
+		onRetry      func(attempt int, maxAttempts int)
 	}
 	uploadAppReturns struct {
 		result1 error
@@ -30,12 +31,13 @@ type FakePushActor struct {
 	processPathReturns struct {
 		result1 error
 	}
-	GatherFilesStub        func(localFiles []models.AppFileFields, appDir string, uploadDir string) ([]resources.AppFileResource, bool, error)
+	GatherFilesStub        func(localFiles []models.AppFileFields, appDir string, uploadDir string, cache *appfiles.AppResourceCache) ([]resources.AppFileResource, bool, error)
 	gatherFilesMutex       sync.RWMutex
 	gatherFilesArgsForCall []struct {
 		localFiles []models.AppFileFields
 		appDir     string
 		uploadDir  string
+		cache      *appfiles.AppResourceCache
 	}
 	gatherFilesReturns struct {
 		result1 []resources.AppFileResource
@@ -44,16 +46,17 @@ type FakePushActor struct {
 	}
 }
 
-func (fake *FakePushActor) UploadApp(appGUID string, zipFile *os.File, presentFiles []resources.AppFileResource) error {
+func (fake *FakePushActor) UploadApp(appGUID string, uploadDir string, presentFiles []resources.AppFileResource, onRetry func(attempt int, maxAttempts int)) error {
 	fake.uploadAppMutex.Lock()
 	fake.uploadAppArgsForCall = append(fake.uploadAppArgsForCall, struct {
 		appGUID      string
-		zipFile      *os.File
+		uploadDir    string
 		presentFiles []resources.AppFileResource
-	}{appGUID, zipFile, presentFiles})
+		onRetry      func(attempt int, maxAttempts int)
+	}{appGUID, uploadDir, presentFiles, onRetry})
 	fake.uploadAppMutex.Unlock()
 	if fake.UploadAppStub != nil {
-		return fake.UploadAppStub(appGUID, zipFile, presentFiles)
+		return fake.UploadAppStub(appGUID, uploadDir, presentFiles, onRetry)
 	} else {
 		return fake.uploadAppReturns.result1
 	}
@@ -65,10 +68,10 @@ func (fake *FakePushActor) UploadAppCallCount() int {
 	return len(fake.uploadAppArgsForCall)
 }
 
-func (fake *FakePushActor) UploadAppArgsForCall(i int) (string, *os.File, []resources.AppFileResource) {
+func (fake *FakePushActor) UploadAppArgsForCall(i int) (string, string, []resources.AppFileResource, func(attempt int, maxAttempts int)) {
 	fake.uploadAppMutex.RLock()
 	defer fake.uploadAppMutex.RUnlock()
-	return fake.uploadAppArgsForCall[i].appGUID, fake.uploadAppArgsForCall[i].zipFile, fake.uploadAppArgsForCall[i].presentFiles
+	return fake.uploadAppArgsForCall[i].appGUID, fake.uploadAppArgsForCall[i].uploadDir, fake.uploadAppArgsForCall[i].presentFiles, fake.uploadAppArgsForCall[i].onRetry
 }
 
 func (fake *FakePushActor) UploadAppReturns(result1 error) {
@@ -111,16 +114,17 @@ func (fake *FakePushActor) ProcessPathReturns(result1 error) {
 	}{result1}
 }
 
-func (fake *FakePushActor) GatherFiles(localFiles []models.AppFileFields, appDir string, uploadDir string) ([]resources.AppFileResource, bool, error) {
+func (fake *FakePushActor) GatherFiles(localFiles []models.AppFileFields, appDir string, uploadDir string, cache *appfiles.AppResourceCache) ([]resources.AppFileResource, bool, error) {
 	fake.gatherFilesMutex.Lock()
 	fake.gatherFilesArgsForCall = append(fake.gatherFilesArgsForCall, struct {
 		localFiles []models.AppFileFields
 		appDir     string
 		uploadDir  string
-	}{localFiles, appDir, uploadDir})
+		cache      *appfiles.AppResourceCache
+	}{localFiles, appDir, uploadDir, cache})
 	fake.gatherFilesMutex.Unlock()
 	if fake.GatherFilesStub != nil {
-		return fake.GatherFilesStub(localFiles, appDir, uploadDir)
+		return fake.GatherFilesStub(localFiles, appDir, uploadDir, cache)
 	} else {
 		return fake.gatherFilesReturns.result1, fake.gatherFilesReturns.result2, fake.gatherFilesReturns.result3
 	}
@@ -132,10 +136,10 @@ func (fake *FakePushActor) GatherFilesCallCount() int {
 	return len(fake.gatherFilesArgsForCall)
 }
 
-func (fake *FakePushActor) GatherFilesArgsForCall(i int) ([]models.AppFileFields, string, string) {
+func (fake *FakePushActor) GatherFilesArgsForCall(i int) ([]models.AppFileFields, string, string, *appfiles.AppResourceCache) {
 	fake.gatherFilesMutex.RLock()
 	defer fake.gatherFilesMutex.RUnlock()
-	return fake.gatherFilesArgsForCall[i].localFiles, fake.gatherFilesArgsForCall[i].appDir, fake.gatherFilesArgsForCall[i].uploadDir
+	return fake.gatherFilesArgsForCall[i].localFiles, fake.gatherFilesArgsForCall[i].appDir, fake.gatherFilesArgsForCall[i].uploadDir, fake.gatherFilesArgsForCall[i].cache
 }
 
 func (fake *FakePushActor) GatherFilesReturns(result1 []resources.AppFileResource, result2 bool, result3 error) {
@@ -19,9 +19,9 @@ const windowsPathPrefix = `\\?\`
 //go:generate counterfeiter . PushActor
 
 type PushActor interface {
-	UploadApp(appGUID string, zipFile *os.File, presentFiles []resources.AppFileResource) error
+	UploadApp(appGUID string, uploadDir string, presentFiles []resources.AppFileResource, onRetry func(attempt int, maxAttempts int)) error
 	ProcessPath(dirOrZipFile string, f func(string)) error
-	GatherFiles(localFiles []models.AppFileFields, appDir string, uploadDir string) ([]resources.AppFileResource, bool, error)
+	GatherFiles(localFiles []models.AppFileFields, appDir string, uploadDir string, cache *appfiles.AppResourceCache) ([]resources.AppFileResource, bool, error)
 }
 
 type PushActorImpl struct {
@@ -85,19 +85,42 @@ func (actor PushActorImpl) ProcessPath(dirOrZipFile string, f func(string)) erro
 	return nil
 }
 
-func (actor PushActorImpl) GatherFiles(localFiles []models.AppFileFields, appDir string, uploadDir string) ([]resources.AppFileResource, bool, error) {
+// GatherFiles copies the local files the Cloud Controller does not already
+// have into uploadDir, and returns the ones it has. When a cache is given,
+// files whose SHA1 the Cloud Controller had on a previous push are not asked
+// about again, and the SHA1s it has now are added to the cache.
+func (actor PushActorImpl) GatherFiles(localFiles []models.AppFileFields, appDir string, uploadDir string, cache *appfiles.AppResourceCache) ([]resources.AppFileResource, bool, error) {
+	remoteFiles := []resources.AppFileResource{}
 	appFileResource := []resources.AppFileResource{}
 	for _, file := range localFiles {
-		appFileResource = append(appFileResource, resources.AppFileResource{
+		resource := resources.AppFileResource{
 			Path: file.Path,
 			Sha1: file.Sha1,
 			Size: file.Size,
-		})
+		}
+
+		if cache != nil && cache.IsMatched(file.Sha1) {
+			remoteFiles = append(remoteFiles, resource)
+		} else {
+			appFileResource = append(appFileResource, resource)
+		}
 	}
 
-	remoteFiles, err := actor.appBitsRepo.GetApplicationFiles(appFileResource)
-	if err != nil {
-		return []resources.AppFileResource{}, false, err
+	if len(appFileResource) > 0 {
+		matchedFiles, err := actor.appBitsRepo.GetApplicationFiles(appFileResource)
+		if err != nil {
+			return []resources.AppFileResource{}, false, err
+		}
+
+		if cache != nil {
+			matchedSha1s := make([]string, len(matchedFiles))
+			for i, file := range matchedFiles {
+				matchedSha1s[i] = file.Sha1
+			}
+			cache.SetMatched(matchedSha1s)
+		}
+
+		remoteFiles = append(remoteFiles, matchedFiles...)
 	}
 
 	filesToUpload := make([]models.AppFileFields, len(localFiles), len(localFiles))
@@ -111,7 +134,7 @@ func (actor PushActorImpl) GatherFiles(localFiles []models.AppFileFields, appDir
 		}
 	}
 
-	err = actor.appfiles.CopyFiles(filesToUpload, appDir, uploadDir)
+	err := actor.appfiles.CopyFiles(filesToUpload, appDir, uploadDir)
 	if err != nil {
 		return []resources.AppFileResource{}, false, err
 	}
@@ -149,6 +172,9 @@ func (actor PushActorImpl) GatherFiles(localFiles []models.AppFileFields, appDir
 	return remoteFiles, len(filesToUpload) > 0, nil
 }
 
-func (actor PushActorImpl) UploadApp(appGUID string, zipFile *os.File, presentFiles []resources.AppFileResource) error {
-	return actor.appBitsRepo.UploadBits(appGUID, zipFile, presentFiles)
+// UploadApp uploads the files in uploadDir, which is empty when all of the
+// app's files are in presentFiles. onRetry is called before each attempt
+// after a failed one.
+func (actor PushActorImpl) UploadApp(appGUID string, uploadDir string, presentFiles []resources.AppFileResource, onRetry func(attempt int, maxAttempts int)) error {
+	return actor.appBitsRepo.UploadBits(appGUID, uploadDir, presentFiles, onRetry)
 }
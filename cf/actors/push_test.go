@@ -75,7 +75,7 @@ var _ = Describe("Push Actor", func() {
 			})
 
 			It("returns an error if we cannot reach the cc", func() {
-				_, _, err := actor.GatherFiles(allFiles, appDir, tmpDir)
+				_, _, err := actor.GatherFiles(allFiles, appDir, tmpDir, nil)
 				Expect(err).To(HaveOccurred())
 				Expect(err).To(Equal(expectedErr))
 			})
@@ -90,7 +90,7 @@ var _ = Describe("Push Actor", func() {
 			})
 
 			It("returns an error", func() {
-				_, _, err := actor.GatherFiles(allFiles, appDir, tmpDir)
+				_, _, err := actor.GatherFiles(allFiles, appDir, tmpDir, nil)
 				Expect(err).To(HaveOccurred())
 				Expect(err).To(Equal(expectedErr))
 			})
@@ -104,7 +104,7 @@ var _ = Describe("Push Actor", func() {
 			})
 
 			It("copies the .cfignore file to the upload directory", func() {
-				_, _, err := actor.GatherFiles(allFiles, appDir, tmpDir)
+				_, _, err := actor.GatherFiles(allFiles, appDir, tmpDir, nil)
 				Expect(err).NotTo(HaveOccurred())
 
 				_, err = os.Stat(filepath.Join(tmpDir, ".cfignore"))
@@ -122,7 +122,7 @@ var _ = Describe("Push Actor", func() {
 
 			expectedFileMode := fmt.Sprintf("%#o", info.Mode())
 
-			actualFiles, _, err := actor.GatherFiles(allFiles, fixturesDir, tmpDir)
+			actualFiles, _, err := actor.GatherFiles(allFiles, fixturesDir, tmpDir, nil)
 			Expect(err).NotTo(HaveOccurred())
 
 			expectedFiles := []resources.AppFileResource{
@@ -145,7 +145,7 @@ var _ = Describe("Push Actor", func() {
 
 			expectedFileMode := fmt.Sprintf("%#o", info.Mode()|0700)
 
-			actualFiles, _, err := actor.GatherFiles(allFiles, fixturesDir, tmpDir)
+			actualFiles, _, err := actor.GatherFiles(allFiles, fixturesDir, tmpDir, nil)
 			Expect(err).NotTo(HaveOccurred())
 
 			expectedFiles := []resources.AppFileResource{
@@ -164,7 +164,7 @@ var _ = Describe("Push Actor", func() {
 			})
 
 			It("returns true for hasFileToUpload", func() {
-				_, hasFileToUpload, err := actor.GatherFiles(allFiles, fixturesDir, tmpDir)
+				_, hasFileToUpload, err := actor.GatherFiles(allFiles, fixturesDir, tmpDir, nil)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(hasFileToUpload).To(BeTrue())
 			})
@@ -179,7 +179,7 @@ var _ = Describe("Push Actor", func() {
 					{Path: "example-app/ignore-me"},
 					{Path: "example-app/manifest.yml"},
 				}
-				_, _, err := actor.GatherFiles(allFiles, fixturesDir, tmpDir)
+				_, _, err := actor.GatherFiles(allFiles, fixturesDir, tmpDir, nil)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(appFiles.CopyFilesCallCount()).To(Equal(1))
@@ -202,7 +202,7 @@ var _ = Describe("Push Actor", func() {
 			})
 
 			It("returns true for hasFileToUpload", func() {
-				_, hasFileToUpload, err := actor.GatherFiles(allFiles, fixturesDir, tmpDir)
+				_, hasFileToUpload, err := actor.GatherFiles(allFiles, fixturesDir, tmpDir, nil)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(hasFileToUpload).To(BeTrue())
 			})
@@ -216,7 +216,7 @@ var _ = Describe("Push Actor", func() {
 					{Path: "example-app/Gemfile.lock"},
 					{Path: "example-app/ignore-me"},
 				}
-				_, _, err := actor.GatherFiles(allFiles, fixturesDir, tmpDir)
+				_, _, err := actor.GatherFiles(allFiles, fixturesDir, tmpDir, nil)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(appFiles.CopyFilesCallCount()).To(Equal(1))
@@ -243,13 +243,13 @@ var _ = Describe("Push Actor", func() {
 			})
 
 			It("returns false for hasFileToUpload", func() {
-				_, hasFileToUpload, err := actor.GatherFiles(allFiles, fixturesDir, tmpDir)
+				_, hasFileToUpload, err := actor.GatherFiles(allFiles, fixturesDir, tmpDir, nil)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(hasFileToUpload).To(BeFalse())
 			})
 
 			It("copies nothing to the upload dir", func() {
-				_, _, err := actor.GatherFiles(allFiles, fixturesDir, tmpDir)
+				_, _, err := actor.GatherFiles(allFiles, fixturesDir, tmpDir, nil)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(appFiles.CopyFilesCallCount()).To(Equal(1))
@@ -259,6 +259,59 @@ var _ = Describe("Push Actor", func() {
 				Expect(uploadDir).To(Equal(tmpDir))
 			})
 		})
+		Context("when given a resource cache", func() {
+			var cache *appfiles.AppResourceCache
+
+			BeforeEach(func() {
+				for i := range allFiles {
+					allFiles[i].Sha1 = allFiles[i].Path + "-sha"
+				}
+
+				appBitsRepo.GetApplicationFilesReturns([]resources.AppFileResource{
+					{Path: "example-app/ignore-me", Sha1: "example-app/ignore-me-sha"},
+				}, nil)
+
+				cache = appfiles.NewResourceCache(filepath.Join(tmpDir, "resource_cache.json")).ForApp("app-guid")
+				cache.SetMatched([]string{"example-app/app.rb-sha"})
+			})
+
+			It("only asks the cloud controller about files that were not matched before", func() {
+				actualFiles, _, err := actor.GatherFiles(allFiles, fixturesDir, tmpDir, cache)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(appBitsRepo.GetApplicationFilesCallCount()).To(Equal(1))
+				checkedFiles := appBitsRepo.GetApplicationFilesArgsForCall(0)
+				Expect(checkedFiles).To(HaveLen(6))
+				for _, file := range checkedFiles {
+					Expect(file.Path).NotTo(Equal("example-app/app.rb"))
+				}
+
+				Expect(actualFiles).To(HaveLen(2))
+				Expect(actualFiles[0].Path).To(Equal("example-app/app.rb"))
+				Expect(actualFiles[1].Path).To(Equal("example-app/ignore-me"))
+			})
+
+			It("adds the files the cloud controller has to the cache", func() {
+				_, _, err := actor.GatherFiles(allFiles, fixturesDir, tmpDir, cache)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(cache.IsMatched("example-app/ignore-me-sha")).To(BeTrue())
+				Expect(cache.IsMatched("example-app/manifest.yml-sha")).To(BeFalse())
+			})
+
+			It("does not ask the cloud controller when every file was matched before", func() {
+				sha1s := []string{}
+				for _, file := range allFiles {
+					sha1s = append(sha1s, file.Sha1)
+				}
+				cache.SetMatched(sha1s)
+
+				_, hasFileToUpload, err := actor.GatherFiles(allFiles, fixturesDir, tmpDir, cache)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hasFileToUpload).To(BeFalse())
+				Expect(appBitsRepo.GetApplicationFilesCallCount()).To(Equal(0))
+			})
+		})
 	})
 
 	Describe(".UploadApp", func() {
@@ -5,82 +5,127 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"mime/multipart"
 	"net/textproto"
-	"os"
 	"time"
 
 	"github.com/cloudfoundry/cli/cf/api/resources"
+	"github.com/cloudfoundry/cli/cf/appfiles"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/errors"
 	. "github.com/cloudfoundry/cli/cf/i18n"
 	"github.com/cloudfoundry/cli/cf/net"
-	"github.com/cloudfoundry/gofileutils/fileutils"
 )
 
 const (
 	DefaultAppUploadBitsTimeout = 15 * time.Minute
+	DefaultMaxUploadAttempts    = 5
+	DefaultUploadRetryBackoff   = time.Second
 )
 
 //go:generate counterfeiter . ApplicationBitsRepository
 
 type ApplicationBitsRepository interface {
 	GetApplicationFiles(appFilesRequest []resources.AppFileResource) ([]resources.AppFileResource, error)
-	UploadBits(appGUID string, zipFile *os.File, presentFiles []resources.AppFileResource) (apiErr error)
+	UploadBits(appGUID string, dirOrZipFile string, presentFiles []resources.AppFileResource, onRetry func(attempt int, maxAttempts int)) (apiErr error)
 }
 
 type CloudControllerApplicationBitsRepository struct {
 	config  coreconfig.Reader
 	gateway net.Gateway
+	zipper  appfiles.Zipper
+
+	MaxUploadAttempts  int
+	UploadRetryBackoff time.Duration
 }
 
-func NewCloudControllerApplicationBitsRepository(config coreconfig.Reader, gateway net.Gateway) (repo CloudControllerApplicationBitsRepository) {
+func NewCloudControllerApplicationBitsRepository(config coreconfig.Reader, gateway net.Gateway, zipper appfiles.Zipper) (repo CloudControllerApplicationBitsRepository) {
 	repo.config = config
 	repo.gateway = gateway
+	repo.zipper = zipper
+	repo.MaxUploadAttempts = DefaultMaxUploadAttempts
+	repo.UploadRetryBackoff = DefaultUploadRetryBackoff
 	return
 }
 
-func (repo CloudControllerApplicationBitsRepository) UploadBits(appGUID string, zipFile *os.File, presentFiles []resources.AppFileResource) (apiErr error) {
+// UploadBits uploads the files in dirOrZipFile, or only the resources in
+// presentFiles when it is empty. The zip is written straight into the body
+// of the request as the request is sent, instead of to a file first.
+//
+// An upload that fails because of a network error or a 5xx response is sent
+// again from the start, after a delay that doubles with every attempt, up to
+// MaxUploadAttempts times in all. onRetry, if given, is called before each
+// attempt after the first.
+func (repo CloudControllerApplicationBitsRepository) UploadBits(appGUID string, dirOrZipFile string, presentFiles []resources.AppFileResource, onRetry func(attempt int, maxAttempts int)) error {
 	apiURL := fmt.Sprintf("/v2/apps/%s/bits", appGUID)
-	fileutils.TempFile("requests", func(requestFile *os.File, err error) {
-		if err != nil {
-			apiErr = fmt.Errorf("%s: %s", T("Error creating tmp file: {{.Err}}", map[string]interface{}{"Err": err}), err.Error())
-			return
-		}
 
-		// json.Marshal represents a nil value as "null" instead of an empty slice "[]"
-		if presentFiles == nil {
-			presentFiles = []resources.AppFileResource{}
-		}
+	// json.Marshal represents a nil value as "null" instead of an empty slice "[]"
+	if presentFiles == nil {
+		presentFiles = []resources.AppFileResource{}
+	}
 
-		presentFilesJSON, err := json.Marshal(presentFiles)
-		if err != nil {
-			apiErr = fmt.Errorf("%s: %s", T("Error marshaling JSON"), err.Error())
-			return
-		}
+	presentFilesJSON, err := json.Marshal(presentFiles)
+	if err != nil {
+		return fmt.Errorf("%s: %s", T("Error marshaling JSON"), err.Error())
+	}
 
-		boundary, err := repo.writeUploadBody(zipFile, requestFile, presentFilesJSON)
-		if err != nil {
-			apiErr = fmt.Errorf("%s: %s", T("Error writing to tmp file: {{.Err}}", map[string]interface{}{"Err": err}), err.Error())
-			return
+	boundary := multipart.NewWriter(ioutil.Discard).Boundary()
+	body := newUploadBody(func(writer io.Writer) error {
+		return repo.writeUploadBody(writer, boundary, dirOrZipFile, presentFilesJSON)
+	})
+	defer body.Close()
+
+	request, err := repo.gateway.NewRequestForStream("PUT", repo.config.APIEndpoint()+apiURL, repo.config.AccessToken(), body)
+	if err != nil {
+		return err
+	}
+
+	contentType := fmt.Sprintf("multipart/form-data; boundary=%s", boundary)
+	request.HTTPReq.Header.Set("Content-Type", contentType)
+	// the upload is retried below, with a backoff
+	request.SendOnce = true
+
+	backoff := repo.UploadRetryBackoff
+	for attempt := 1; ; attempt++ {
+		response := &resources.Resource{}
+		_, err = repo.gateway.PerformPollingRequestForJSONResponse(repo.config.APIEndpoint(), request, response, DefaultAppUploadBitsTimeout)
+
+		// an error writing the body makes the request fail with a less helpful
+		// error of its own
+		if writeErr := body.Err(); writeErr != nil {
+			if emptyDirErr, ok := writeErr.(*errors.EmptyDirError); ok {
+				return emptyDirErr
+			}
+			return fmt.Errorf("%s: %s", T("Error zipping application"), writeErr.Error())
 		}
 
-		var request *net.Request
-		request, apiErr = repo.gateway.NewRequestForFile("PUT", repo.config.APIEndpoint()+apiURL, repo.config.AccessToken(), requestFile)
-		if apiErr != nil {
-			return
+		if err == nil || !isTransientUploadError(err) || attempt >= repo.MaxUploadAttempts {
+			return err
 		}
 
-		contentType := fmt.Sprintf("multipart/form-data; boundary=%s", boundary)
-		request.HTTPReq.Header.Set("Content-Type", contentType)
+		time.Sleep(backoff)
+		backoff *= 2
 
-		response := &resources.Resource{}
-		_, apiErr = repo.gateway.PerformPollingRequestForJSONResponse(repo.config.APIEndpoint(), request, response, DefaultAppUploadBitsTimeout)
-		if apiErr != nil {
-			return
+		_, err = request.SeekableBody.Seek(0, 0)
+		if err != nil {
+			return err
 		}
-	})
 
-	return
+		if onRetry != nil {
+			onRetry(attempt+1, repo.MaxUploadAttempts)
+		}
+	}
+}
+
+func isTransientUploadError(err error) bool {
+	switch err := err.(type) {
+	case *errors.NetworkError:
+		return true
+	case errors.HTTPError:
+		return err.StatusCode() >= 500
+	}
+	return false
 }
 
 func (repo CloudControllerApplicationBitsRepository) GetApplicationFiles(appFilesToCheck []resources.AppFileResource) ([]resources.AppFileResource, error) {
@@ -133,51 +178,42 @@ func appFilesBySha(in []resources.AppFileResource) (out map[string]resources.App
 	return out
 }
 
-func (repo CloudControllerApplicationBitsRepository) writeUploadBody(zipFile *os.File, body *os.File, presentResourcesJSON []byte) (boundary string, err error) {
+func (repo CloudControllerApplicationBitsRepository) writeUploadBody(body io.Writer, boundary string, dirOrZipFile string, presentResourcesJSON []byte) error {
 	writer := multipart.NewWriter(body)
-	defer writer.Close()
-
-	boundary = writer.Boundary()
+	err := writer.SetBoundary(boundary)
+	if err != nil {
+		return err
+	}
 
 	part, err := writer.CreateFormField("resources")
 	if err != nil {
-		return
+		return err
 	}
 
-	_, err = io.Copy(part, bytes.NewBuffer(presentResourcesJSON))
+	_, err = part.Write(presentResourcesJSON)
 	if err != nil {
-		return
+		return err
 	}
 
-	if zipFile != nil {
-		zipStats, zipErr := zipFile.Stat()
-		if zipErr != nil {
-			return
-		}
-
-		if zipStats.Size() == 0 {
-			return
-		}
-
-		part, zipErr = createZipPartWriter(zipStats, writer)
-		if zipErr != nil {
-			return
+	if dirOrZipFile != "" {
+		part, err = createZipPartWriter(writer)
+		if err != nil {
+			return err
 		}
 
-		_, zipErr = io.Copy(part, zipFile)
-		if zipErr != nil {
-			return
+		err = repo.zipper.WriteZip(dirOrZipFile, part)
+		if err != nil {
+			return err
 		}
 	}
 
-	return
+	return writer.Close()
 }
 
-func createZipPartWriter(zipStats os.FileInfo, writer *multipart.Writer) (io.Writer, error) {
+func createZipPartWriter(writer *multipart.Writer) (io.Writer, error) {
 	h := make(textproto.MIMEHeader)
 	h.Set("Content-Disposition", `form-data; name="application"; filename="application.zip"`)
 	h.Set("Content-Type", "application/zip")
-	h.Set("Content-Length", fmt.Sprintf("%d", zipStats.Size()))
 	h.Set("Content-Transfer-Encoding", "binary")
 	return writer.CreatePart(h)
 }
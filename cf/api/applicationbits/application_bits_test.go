@@ -2,25 +2,28 @@ package applicationbits_test
 
 import (
 	"archive/zip"
+	"bytes"
 	"fmt"
-	"log"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"runtime"
-	"strconv"
 	"strings"
 	"time"
 
 	testapi "github.com/cloudfoundry/cli/cf/api/apifakes"
 	"github.com/cloudfoundry/cli/cf/api/resources"
+	"github.com/cloudfoundry/cli/cf/appfiles"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/net"
 	"github.com/cloudfoundry/cli/testhelpers/cloudcontrollergateway"
 	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
 	testnet "github.com/cloudfoundry/cli/testhelpers/net"
 
 	. "github.com/cloudfoundry/cli/cf/api/applicationbits"
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -34,7 +37,9 @@ var _ = Describe("CloudControllerApplicationBitsRepository", func() {
 		file3       resources.AppFileResource
 		file4       resources.AppFileResource
 		testServer  *httptest.Server
+		testHandler *testnet.TestHandler
 		configRepo  coreconfig.ReadWriter
+		gateway     net.Gateway
 	)
 
 	BeforeEach(func() {
@@ -44,10 +49,10 @@ var _ = Describe("CloudControllerApplicationBitsRepository", func() {
 
 		configRepo = testconfig.NewRepositoryWithDefaults()
 
-		gateway := cloudcontrollergateway.NewTestCloudControllerGateway(configRepo)
+		gateway = cloudcontrollergateway.NewTestCloudControllerGateway(configRepo)
 		gateway.PollingThrottle = time.Duration(0)
 
-		repo = NewCloudControllerApplicationBitsRepository(configRepo, gateway)
+		repo = NewCloudControllerApplicationBitsRepository(configRepo, gateway, appfiles.ApplicationZipper{})
 
 		file1 = resources.AppFileResource{Path: "app.rb", Sha1: "2474735f5163ba7612ef641f438f4b5bee00127b", Size: 51}
 		file2 = resources.AppFileResource{Path: "config.ru", Sha1: "f097424ce1fa66c6cb9f5e8a18c317376ec12e05", Size: 70}
@@ -56,19 +61,15 @@ var _ = Describe("CloudControllerApplicationBitsRepository", func() {
 	})
 
 	setupTestServer := func(reqs ...testnet.TestRequest) {
-		testServer, _ = testnet.NewServer(reqs)
+		testServer, testHandler = testnet.NewServer(reqs)
 		configRepo.SetAPIEndpoint(testServer.URL)
 	}
 
 	Describe(".UploadBits", func() {
-		var uploadFile *os.File
-		var err error
+		var uploadFile string
 
 		BeforeEach(func() {
-			uploadFile, err = os.Open(filepath.Join(fixturesDir, "ignored_and_resource_matched_example_app.zip"))
-			if err != nil {
-				log.Fatal(err)
-			}
+			uploadFile = filepath.Join(fixturesDir, "ignored_and_resource_matched_example_app.zip")
 		})
 
 		AfterEach(func() {
@@ -95,7 +96,7 @@ var _ = Describe("CloudControllerApplicationBitsRepository", func() {
 				createProgressEndpoint("finished"),
 			)
 
-			apiErr := repo.UploadBits("my-cool-app-guid", uploadFile, []resources.AppFileResource{file1, file2})
+			apiErr := repo.UploadBits("my-cool-app-guid", uploadFile, []resources.AppFileResource{file1, file2}, nil)
 			Expect(apiErr).NotTo(HaveOccurred())
 		})
 
@@ -118,9 +119,141 @@ var _ = Describe("CloudControllerApplicationBitsRepository", func() {
 				createProgressEndpoint("running"),
 				createProgressEndpoint("failed"),
 			)
-			apiErr := repo.UploadBits("my-cool-app-guid", uploadFile, []resources.AppFileResource{file1, file2})
+			apiErr := repo.UploadBits("my-cool-app-guid", uploadFile, []resources.AppFileResource{file1, file2}, nil)
+
+			Expect(apiErr).To(HaveOccurred())
+		})
+
+		It("zips the files of an app directory into the upload", func() {
+			appDir, err := ioutil.TempDir("", "upload-bits")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(appDir)
+
+			Expect(ioutil.WriteFile(filepath.Join(appDir, "app.rb"), []byte("puts 'hello'"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(appDir, "config.ru"), []byte("run App"), 0644)).To(Succeed())
+
+			setupTestServer(defaultRequests...)
+
+			apiErr := repo.UploadBits("my-cool-app-guid", appDir, []resources.AppFileResource{file1, file2}, nil)
+			Expect(apiErr).NotTo(HaveOccurred())
+		})
+
+		It("writes the body again when the upload is retried with a refreshed token", func() {
+			setupTestServer(
+				testapi.NewCloudControllerTestRequest(testnet.TestRequest{
+					Method: "PUT",
+					Path:   "/v2/apps/my-cool-app-guid/bits",
+					Response: testnet.TestResponse{
+						Status: http.StatusUnauthorized,
+						Body:   `{ "code": 1000, "description": "Auth token is invalid" }`,
+					},
+				}),
+				defaultRequests[0],
+				defaultRequests[1],
+				defaultRequests[2],
+			)
 
+			gateway.SetTokenRefresher(&fakeTokenRefresher{token: "BEARER my_access_token"})
+			repo = NewCloudControllerApplicationBitsRepository(configRepo, gateway, appfiles.ApplicationZipper{})
+
+			apiErr := repo.UploadBits("my-cool-app-guid", uploadFile, []resources.AppFileResource{file1, file2}, nil)
+			Expect(apiErr).NotTo(HaveOccurred())
+		})
+
+		It("returns an error when the app cannot be zipped", func() {
+			testServer = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				ioutil.ReadAll(request.Body)
+				writer.WriteHeader(http.StatusBadRequest)
+			}))
+			configRepo.SetAPIEndpoint(testServer.URL)
+
+			apiErr := repo.UploadBits("my-cool-app-guid", filepath.Join(fixturesDir, "does-not-exist"), []resources.AppFileResource{file1, file2}, nil)
 			Expect(apiErr).To(HaveOccurred())
+			Expect(apiErr.Error()).To(ContainSubstring("Error zipping application"))
+		})
+
+		Context("when the upload fails with a transient error", func() {
+			var (
+				attempts []int
+				onRetry  func(attempt int, maxAttempts int)
+			)
+
+			BeforeEach(func() {
+				bitsRepo := NewCloudControllerApplicationBitsRepository(configRepo, gateway, appfiles.ApplicationZipper{})
+				bitsRepo.UploadRetryBackoff = time.Millisecond
+				bitsRepo.MaxUploadAttempts = 3
+				repo = bitsRepo
+
+				attempts = []int{}
+				onRetry = func(attempt int, maxAttempts int) {
+					Expect(maxAttempts).To(Equal(3))
+					attempts = append(attempts, attempt)
+				}
+			})
+
+			serverError := testapi.NewCloudControllerTestRequest(testnet.TestRequest{
+				Method: "PUT",
+				Path:   "/v2/apps/my-cool-app-guid/bits",
+				Response: testnet.TestResponse{
+					Status: http.StatusBadGateway,
+					Body:   `{ "code": 10001, "description": "Bad gateway" }`,
+				},
+			})
+
+			It("uploads the whole body again", func() {
+				setupTestServer(serverError, defaultRequests[0], defaultRequests[1], defaultRequests[2])
+
+				apiErr := repo.UploadBits("my-cool-app-guid", uploadFile, []resources.AppFileResource{file1, file2}, onRetry)
+				Expect(apiErr).NotTo(HaveOccurred())
+				Expect(testHandler).To(HaveAllRequestsCalled())
+				Expect(attempts).To(Equal([]int{2}))
+			})
+
+			It("gives up after the maximum number of attempts", func() {
+				setupTestServer(serverError, serverError, serverError)
+
+				apiErr := repo.UploadBits("my-cool-app-guid", uploadFile, []resources.AppFileResource{file1, file2}, onRetry)
+				Expect(apiErr).To(HaveOccurred())
+				Expect(apiErr.Error()).To(ContainSubstring("Bad gateway"))
+				Expect(attempts).To(Equal([]int{2, 3}))
+			})
+
+			It("does not retry errors the server reports for the request itself", func() {
+				setupTestServer(testapi.NewCloudControllerTestRequest(testnet.TestRequest{
+					Method: "PUT",
+					Path:   "/v2/apps/my-cool-app-guid/bits",
+					Response: testnet.TestResponse{
+						Status: http.StatusBadRequest,
+						Body:   `{ "code": 160001, "description": "The app upload is invalid" }`,
+					},
+				}))
+
+				apiErr := repo.UploadBits("my-cool-app-guid", uploadFile, []resources.AppFileResource{file1, file2}, onRetry)
+				Expect(apiErr).To(HaveOccurred())
+				Expect(attempts).To(BeEmpty())
+			})
+
+			It("sends the whole body again when the connection is dropped", func() {
+				requests := 0
+				testServer = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+					requests++
+					if requests == 1 {
+						conn, _, err := writer.(http.Hijacker).Hijack()
+						Expect(err).NotTo(HaveOccurred())
+						conn.Close()
+						return
+					}
+
+					uploadBodyMatcher(defaultZipCheck)(request)
+					writer.WriteHeader(http.StatusCreated)
+					writer.Write([]byte("{}"))
+				}))
+				configRepo.SetAPIEndpoint(testServer.URL)
+
+				apiErr := repo.UploadBits("my-cool-app-guid", uploadFile, []resources.AppFileResource{file1, file2}, onRetry)
+				Expect(apiErr).NotTo(HaveOccurred())
+				Expect(requests).To(Equal(2))
+			})
 		})
 
 		Context("when there are no files to upload", func() {
@@ -156,7 +289,7 @@ var _ = Describe("CloudControllerApplicationBitsRepository", func() {
 					createProgressEndpoint("finished"),
 				)
 
-				apiErr := repo.UploadBits("my-cool-app-guid", nil, []resources.AppFileResource{})
+				apiErr := repo.UploadBits("my-cool-app-guid", "", []resources.AppFileResource{}, nil)
 				Expect(apiErr).NotTo(HaveOccurred())
 			})
 		})
@@ -193,7 +326,7 @@ var _ = Describe("CloudControllerApplicationBitsRepository", func() {
 				createProgressEndpoint("finished"),
 			)
 
-			apiErr := repo.UploadBits("my-cool-app-guid", nil, nil)
+			apiErr := repo.UploadBits("my-cool-app-guid", "", nil, nil)
 			Expect(apiErr).NotTo(HaveOccurred())
 		})
 	})
@@ -373,14 +506,14 @@ func uploadBodyMatcher(zipChecks func(zipReader *zip.Reader)) func(*http.Request
 			return
 		}
 
-		length, err := strconv.ParseInt(applicationFile.Header.Get("content-length"), 10, 64)
+		content, err := ioutil.ReadAll(file)
 		if err != nil {
-			Fail(fmt.Sprintf("Cannot convert content-length to int %v", err.Error()))
+			Fail(fmt.Sprintf("Cannot read multipart file %v", err.Error()))
 			return
 		}
 
 		if zipChecks != nil {
-			zipReader, err := zip.NewReader(file, length)
+			zipReader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
 			if err != nil {
 				Fail(fmt.Sprintf("Error reading zip content %v", err.Error()))
 				return
@@ -391,6 +524,14 @@ func uploadBodyMatcher(zipChecks func(zipReader *zip.Reader)) func(*http.Request
 	}
 }
 
+type fakeTokenRefresher struct {
+	token string
+}
+
+func (refresher *fakeTokenRefresher) RefreshAuthToken() (string, error) {
+	return refresher.token, nil
+}
+
 func createProgressEndpoint(status string) (req testnet.TestRequest) {
 	body := fmt.Sprintf(`
 	{
@@ -2,7 +2,6 @@
 package applicationbitsfakes
 
 import (
-	"os"
 	"sync"
 
 	"github.com/cloudfoundry/cli/cf/api/applicationbits"
@@ -19,12 +18,13 @@ type FakeApplicationBitsRepository struct {
 		result1 []resources.AppFileResource
 		result2 error
 	}
-	UploadBitsStub        func(appGUID string, zipFile *os.File, presentFiles []resources.AppFileResource) (apiErr error)
+	UploadBitsStub        func(appGUID string, dirOrZipFile string, presentFiles []resources.AppFileResource, onRetry func(attempt int, maxAttempts int)) (apiErr error)
 	uploadBitsMutex       sync.RWMutex
 	uploadBitsArgsForCall []struct {
 		appGUID      string
-		zipFile      *os.File
+		dirOrZipFile string
 		presentFiles []resources.AppFileResource
+		onRetry      func(attempt int, maxAttempts int)
 	}
 	uploadBitsReturns struct {
 		result1 error
@@ -64,16 +64,17 @@ func (fake *FakeApplicationBitsRepository) GetApplicationFilesReturns(result1 []
 	}{result1, result2}
 }
 
-func (fake *FakeApplicationBitsRepository) UploadBits(appGUID string, zipFile *os.File, presentFiles []resources.AppFileResource) (apiErr error) {
+func (fake *FakeApplicationBitsRepository) UploadBits(appGUID string, dirOrZipFile string, presentFiles []resources.AppFileResource, onRetry func(attempt int, maxAttempts int)) (apiErr error) {
 	fake.uploadBitsMutex.Lock()
 	fake.uploadBitsArgsForCall = append(fake.uploadBitsArgsForCall, struct {
 		appGUID      string
-		zipFile      *os.File
+		dirOrZipFile string
 		presentFiles []resources.AppFileResource
-	}{appGUID, zipFile, presentFiles})
+		onRetry      func(attempt int, maxAttempts int)
+	}{appGUID, dirOrZipFile, presentFiles, onRetry})
 	fake.uploadBitsMutex.Unlock()
 	if fake.UploadBitsStub != nil {
-		return fake.UploadBitsStub(appGUID, zipFile, presentFiles)
+		return fake.UploadBitsStub(appGUID, dirOrZipFile, presentFiles, onRetry)
 	} else {
 		return fake.uploadBitsReturns.result1
 	}
@@ -85,10 +86,10 @@ func (fake *FakeApplicationBitsRepository) UploadBitsCallCount() int {
 	return len(fake.uploadBitsArgsForCall)
 }
 
-func (fake *FakeApplicationBitsRepository) UploadBitsArgsForCall(i int) (string, *os.File, []resources.AppFileResource) {
+func (fake *FakeApplicationBitsRepository) UploadBitsArgsForCall(i int) (string, string, []resources.AppFileResource, func(attempt int, maxAttempts int)) {
 	fake.uploadBitsMutex.RLock()
 	defer fake.uploadBitsMutex.RUnlock()
-	return fake.uploadBitsArgsForCall[i].appGUID, fake.uploadBitsArgsForCall[i].zipFile, fake.uploadBitsArgsForCall[i].presentFiles
+	return fake.uploadBitsArgsForCall[i].appGUID, fake.uploadBitsArgsForCall[i].dirOrZipFile, fake.uploadBitsArgsForCall[i].presentFiles, fake.uploadBitsArgsForCall[i].onRetry
 }
 
 func (fake *FakeApplicationBitsRepository) UploadBitsReturns(result1 error) {
@@ -0,0 +1,84 @@
+package applicationbits
+
+import (
+	"errors"
+	"io"
+	"sync"
+
+	. "github.com/cloudfoundry/cli/cf/i18n"
+)
+
+// uploadBody is the body of an upload request. It is written by write through
+// a pipe while it is being read, so that it never has to be held in memory or
+// on disk as a whole. Seeking back to the start makes write begin again, so
+// that the request can be sent again when it has to be retried.
+type uploadBody struct {
+	write func(io.Writer) error
+
+	reader *io.PipeReader
+	err    error
+	mutex  sync.Mutex
+}
+
+func newUploadBody(write func(io.Writer) error) *uploadBody {
+	return &uploadBody{write: write}
+}
+
+func (body *uploadBody) Read(p []byte) (int, error) {
+	body.mutex.Lock()
+	if body.reader == nil {
+		body.start()
+	}
+	reader := body.reader
+	body.mutex.Unlock()
+
+	return reader.Read(p)
+}
+
+func (body *uploadBody) Seek(offset int64, whence int) (int64, error) {
+	if offset != 0 || whence != 0 {
+		return 0, errors.New(T("An upload can only be restarted from the beginning"))
+	}
+
+	body.Close()
+	return 0, nil
+}
+
+// Close stops the writing of the body, if it is still being written.
+func (body *uploadBody) Close() error {
+	body.mutex.Lock()
+	defer body.mutex.Unlock()
+
+	if body.reader != nil {
+		body.reader.Close()
+		body.reader = nil
+	}
+	body.err = nil
+	return nil
+}
+
+// Err returns the error write returned while the body was last read, if any.
+func (body *uploadBody) Err() error {
+	body.mutex.Lock()
+	defer body.mutex.Unlock()
+
+	return body.err
+}
+
+func (body *uploadBody) start() {
+	reader, writer := io.Pipe()
+	body.reader = reader
+
+	go func() {
+		err := body.write(writer)
+
+		body.mutex.Lock()
+		// errors caused by the reader having been closed are not interesting
+		if err != nil && body.reader == reader {
+			body.err = err
+		}
+		body.mutex.Unlock()
+
+		writer.CloseWithError(err)
+	}()
+}
@@ -28,6 +28,7 @@ type AuthenticationRepository interface {
 
 	RefreshAuthToken() (updatedToken string, apiErr error)
 	Authenticate(credentials map[string]string) (apiErr error)
+	AuthenticateClient(clientID string, clientSecret string) error
 	Authorize(token string) (string, error)
 	GetLoginPromptsAndSaveUAAServerURL() (map[string]coreconfig.AuthPrompt, error)
 }
@@ -119,24 +120,41 @@ func (uaa UAAAuthenticationRepository) Authenticate(credentials map[string]strin
 		data[key] = []string{val}
 	}
 
-	err := uaa.getAuthToken(data)
-	if err != nil {
-		httpError, ok := err.(errors.HTTPError)
-		if ok {
-			switch {
-			case httpError.StatusCode() == http.StatusUnauthorized:
-				return errors.New(T("Credentials were rejected, please try again."))
-			case httpError.StatusCode() >= http.StatusInternalServerError:
-				return errors.New(T("The targeted API endpoint could not be reached."))
-			}
-		}
+	return authenticationError(uaa.getAuthToken(data, "cf", ""))
+}
 
-		return err
+// AuthenticateClient authenticates as a UAA client with the
+// client_credentials grant, and keeps the client identity to get new tokens
+// with, as there is no refresh token.
+func (uaa UAAAuthenticationRepository) AuthenticateClient(clientID string, clientSecret string) error {
+	data := url.Values{
+		"grant_type": {coreconfig.ClientCredentialsGrantType},
 	}
 
+	err := uaa.getAuthToken(data, clientID, clientSecret)
+	if err != nil {
+		return authenticationError(err)
+	}
+
+	uaa.config.SetUAAGrantType(coreconfig.ClientCredentialsGrantType)
+	uaa.config.SetUAAOAuthClient(clientID)
+	uaa.config.SetUAAOAuthClientSecret(clientSecret)
 	return nil
 }
 
+func authenticationError(err error) error {
+	if httpError, ok := err.(errors.HTTPError); ok {
+		switch {
+		case httpError.StatusCode() == http.StatusUnauthorized:
+			return errors.New(T("Credentials were rejected, please try again."))
+		case httpError.StatusCode() >= http.StatusInternalServerError:
+			return errors.New(T("The targeted API endpoint could not be reached."))
+		}
+	}
+
+	return err
+}
+
 func (uaa UAAAuthenticationRepository) DumpRequest(req *http.Request) {
 	uaa.dumper.DumpRequest(req)
 }
@@ -181,19 +199,26 @@ func (uaa UAAAuthenticationRepository) GetLoginPromptsAndSaveUAAServerURL() (pro
 }
 
 func (uaa UAAAuthenticationRepository) RefreshAuthToken() (string, error) {
-	data := url.Values{
-		"refresh_token": {uaa.config.RefreshToken()},
-		"grant_type":    {"refresh_token"},
-		"scope":         {""},
+	var apiErr error
+	if uaa.config.UAAGrantType() == coreconfig.ClientCredentialsGrantType {
+		data := url.Values{
+			"grant_type": {coreconfig.ClientCredentialsGrantType},
+		}
+		apiErr = uaa.getAuthToken(data, uaa.config.UAAOAuthClient(), uaa.config.UAAOAuthClientSecret())
+	} else {
+		data := url.Values{
+			"refresh_token": {uaa.config.RefreshToken()},
+			"grant_type":    {"refresh_token"},
+			"scope":         {""},
+		}
+		apiErr = uaa.getAuthToken(data, "cf", "")
 	}
-
-	apiErr := uaa.getAuthToken(data)
 	updatedToken := uaa.config.AccessToken()
 
 	return updatedToken, apiErr
 }
 
-func (uaa UAAAuthenticationRepository) getAuthToken(data url.Values) error {
+func (uaa UAAAuthenticationRepository) getAuthToken(data url.Values, clientID string, clientSecret string) error {
 	type uaaErrorResponse struct {
 		Code        string `json:"error"`
 		Description string `json:"error_description"`
@@ -207,7 +232,7 @@ func (uaa UAAAuthenticationRepository) getAuthToken(data url.Values) error {
 	}
 
 	path := fmt.Sprintf("%s/oauth/token", uaa.config.AuthenticationEndpoint())
-	request, err := uaa.gateway.NewRequest("POST", path, "Basic "+base64.StdEncoding.EncodeToString([]byte("cf:")), strings.NewReader(data.Encode()))
+	request, err := uaa.gateway.NewRequest("POST", path, "Basic "+base64.StdEncoding.EncodeToString([]byte(clientID+":"+clientSecret)), strings.NewReader(data.Encode()))
 	if err != nil {
 		return fmt.Errorf("%s: %s", T("Failed to start oauth request"), err.Error())
 	}
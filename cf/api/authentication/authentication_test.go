@@ -136,6 +136,42 @@ var _ = Describe("AuthenticationRepository", func() {
 			})
 		})
 
+		Describe("authenticating as a client", func() {
+			var err error
+
+			JustBeforeEach(func() {
+				err = auth.AuthenticateClient("ci-client", "ci-secret")
+			})
+
+			Context("when authentication succeeds", func() {
+				BeforeEach(func() {
+					setupTestServer(successfulClientCredentialsRequest)
+				})
+
+				It("stores the access token and the client identity in the config", func() {
+					Expect(handler).To(HaveAllRequestsCalled())
+					Expect(err).NotTo(HaveOccurred())
+					Expect(config.AccessToken()).To(Equal("BEARER my_client_token"))
+					Expect(config.RefreshToken()).To(BeEmpty())
+					Expect(config.UAAGrantType()).To(Equal("client_credentials"))
+					Expect(config.UAAOAuthClient()).To(Equal("ci-client"))
+					Expect(config.UAAOAuthClientSecret()).To(Equal("ci-secret"))
+				})
+			})
+
+			Context("when the client credentials are rejected", func() {
+				BeforeEach(func() {
+					setupTestServer(unsuccessfulLoginRequest)
+				})
+
+				It("returns an error and does not store the client identity", func() {
+					Expect(err).To(MatchError("Credentials were rejected, please try again."))
+					Expect(config.UAAGrantType()).To(BeEmpty())
+					Expect(config.UAAOAuthClient()).To(BeEmpty())
+				})
+			})
+		})
+
 		Describe("getting login info", func() {
 			var (
 				apiErr  error
@@ -203,6 +239,21 @@ var _ = Describe("AuthenticationRepository", func() {
 				_, apiErr = auth.RefreshAuthToken()
 			})
 
+			Context("when authenticated as a client", func() {
+				BeforeEach(func() {
+					config.SetUAAGrantType("client_credentials")
+					config.SetUAAOAuthClient("ci-client")
+					config.SetUAAOAuthClientSecret("ci-secret")
+					setupTestServer(successfulClientCredentialsRequest)
+				})
+
+				It("requests a new token with the client credentials", func() {
+					Expect(handler).To(HaveAllRequestsCalled())
+					Expect(apiErr).NotTo(HaveOccurred())
+					Expect(config.AccessToken()).To(Equal("BEARER my_client_token"))
+				})
+			})
+
 			Context("when the refresh token has expired", func() {
 				BeforeEach(func() {
 					setupTestServer(refreshTokenExpiredRequestError)
@@ -360,6 +411,30 @@ var successfulLoginMatcher = func(request *http.Request) {
 	Expect(request.Form.Get("scope")).To(Equal(""))
 }
 
+var successfulClientCredentialsRequest = testnet.TestRequest{
+	Method: "POST",
+	Path:   "/oauth/token",
+	Header: http.Header{
+		"accept":        {"application/json"},
+		"content-type":  {"application/x-www-form-urlencoded"},
+		"authorization": {"Basic " + base64.StdEncoding.EncodeToString([]byte("ci-client:ci-secret"))},
+	},
+	Matcher: func(request *http.Request) {
+		err := request.ParseForm()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(request.Form.Get("grant_type")).To(Equal("client_credentials"))
+		Expect(request.Form.Get("refresh_token")).To(BeEmpty())
+	},
+	Response: testnet.TestResponse{
+		Status: http.StatusOK,
+		Body: `
+{
+  "access_token": "my_client_token",
+  "token_type": "BEARER",
+  "expires_in": 43199
+}`},
+}
+
 var unsuccessfulLoginRequest = testnet.TestRequest{
 	Method: "POST",
 	Path:   "/oauth/token",
@@ -35,6 +35,15 @@ type FakeAuthenticationRepository struct {
 	authenticateReturns struct {
 		result1 error
 	}
+	AuthenticateClientStub        func(clientID string, clientSecret string) error
+	authenticateClientMutex       sync.RWMutex
+	authenticateClientArgsForCall []struct {
+		clientID     string
+		clientSecret string
+	}
+	authenticateClientReturns struct {
+		result1 error
+	}
 	AuthorizeStub        func(token string) (string, error)
 	authorizeMutex       sync.RWMutex
 	authorizeArgsForCall []struct {
@@ -156,6 +165,39 @@ func (fake *FakeAuthenticationRepository) AuthenticateReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeAuthenticationRepository) AuthenticateClient(clientID string, clientSecret string) error {
+	fake.authenticateClientMutex.Lock()
+	fake.authenticateClientArgsForCall = append(fake.authenticateClientArgsForCall, struct {
+		clientID     string
+		clientSecret string
+	}{clientID, clientSecret})
+	fake.authenticateClientMutex.Unlock()
+	if fake.AuthenticateClientStub != nil {
+		return fake.AuthenticateClientStub(clientID, clientSecret)
+	} else {
+		return fake.authenticateClientReturns.result1
+	}
+}
+
+func (fake *FakeAuthenticationRepository) AuthenticateClientCallCount() int {
+	fake.authenticateClientMutex.RLock()
+	defer fake.authenticateClientMutex.RUnlock()
+	return len(fake.authenticateClientArgsForCall)
+}
+
+func (fake *FakeAuthenticationRepository) AuthenticateClientArgsForCall(i int) (string, string) {
+	fake.authenticateClientMutex.RLock()
+	defer fake.authenticateClientMutex.RUnlock()
+	return fake.authenticateClientArgsForCall[i].clientID, fake.authenticateClientArgsForCall[i].clientSecret
+}
+
+func (fake *FakeAuthenticationRepository) AuthenticateClientReturns(result1 error) {
+	fake.AuthenticateClientStub = nil
+	fake.authenticateClientReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeAuthenticationRepository) Authorize(token string) (string, error) {
 	fake.authorizeMutex.Lock()
 	fake.authorizeArgsForCall = append(fake.authorizeArgsForCall, struct {
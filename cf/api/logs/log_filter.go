@@ -0,0 +1,78 @@
+package logs
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// LogFilter selects log messages by where they come from, what they say and
+// when they were sent. The zero LogFilter lets every message through.
+type LogFilter struct {
+	// SourceTypes are the source types to show, like APP or RTR. A source
+	// type also matches the messages of its subtypes, so APP matches
+	// APP/PROC/WEB.
+	SourceTypes []string
+
+	// SourceInstances are the instance indexes to show.
+	SourceInstances []string
+
+	// MessageType is OUT or ERR to only show messages of that stream.
+	MessageType string
+
+	// Pattern is a regular expression the message body has to match.
+	Pattern *regexp.Regexp
+
+	// Since is the time messages have to be sent at or after.
+	Since time.Time
+}
+
+// Matches tells whether msg should be shown. Reconnect markers are always
+// shown, since they say the filtered messages may be incomplete.
+func (filter LogFilter) Matches(msg Loggable) bool {
+	if _, ok := msg.(*ReconnectMessage); ok {
+		return true
+	}
+
+	if len(filter.SourceTypes) > 0 && !matchesSourceType(filter.SourceTypes, msg.GetSourceName()) {
+		return false
+	}
+
+	if len(filter.SourceInstances) > 0 && !contains(filter.SourceInstances, msg.GetSourceInstance()) {
+		return false
+	}
+
+	if filter.MessageType != "" && !strings.EqualFold(filter.MessageType, msg.GetMessageType()) {
+		return false
+	}
+
+	if filter.Pattern != nil && !filter.Pattern.MatchString(msg.ToSimpleLog()) {
+		return false
+	}
+
+	if !filter.Since.IsZero() && msg.GetTimestamp().Before(filter.Since) {
+		return false
+	}
+
+	return true
+}
+
+func matchesSourceType(sourceTypes []string, sourceName string) bool {
+	sourceName = strings.ToUpper(sourceName)
+	for _, sourceType := range sourceTypes {
+		sourceType = strings.ToUpper(sourceType)
+		if sourceName == sourceType || strings.HasPrefix(sourceName, sourceType+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
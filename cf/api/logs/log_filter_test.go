@@ -0,0 +1,82 @@
+package logs_test
+
+import (
+	"regexp"
+	"time"
+
+	. "github.com/cloudfoundry/cli/cf/api/logs"
+	"github.com/cloudfoundry/sonde-go/events"
+	"github.com/gogo/protobuf/proto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LogFilter", func() {
+	var (
+		now time.Time
+		msg Loggable
+	)
+
+	BeforeEach(func() {
+		now = time.Now()
+		messageType := events.LogMessage_ERR
+		msg = NewNoaaLogMessage(&events.LogMessage{
+			Message:        []byte("GET /health 500\n"),
+			MessageType:    &messageType,
+			Timestamp:      proto.Int64(now.UnixNano()),
+			AppId:          proto.String("app-guid"),
+			SourceType:     proto.String("APP/PROC/WEB"),
+			SourceInstance: proto.String("1"),
+		})
+	})
+
+	It("lets every message through when it is empty", func() {
+		Expect(LogFilter{}.Matches(msg)).To(BeTrue())
+	})
+
+	It("matches source types case-insensitively, including their subtypes", func() {
+		Expect(LogFilter{SourceTypes: []string{"rtr", "app"}}.Matches(msg)).To(BeTrue())
+		Expect(LogFilter{SourceTypes: []string{"APP/PROC/WEB"}}.Matches(msg)).To(BeTrue())
+		Expect(LogFilter{SourceTypes: []string{"AP"}}.Matches(msg)).To(BeFalse())
+		Expect(LogFilter{SourceTypes: []string{"RTR", "STG"}}.Matches(msg)).To(BeFalse())
+	})
+
+	It("matches instance indexes", func() {
+		Expect(LogFilter{SourceInstances: []string{"0", "1"}}.Matches(msg)).To(BeTrue())
+		Expect(LogFilter{SourceInstances: []string{"0"}}.Matches(msg)).To(BeFalse())
+	})
+
+	It("matches the message type", func() {
+		Expect(LogFilter{MessageType: "err"}.Matches(msg)).To(BeTrue())
+		Expect(LogFilter{MessageType: "OUT"}.Matches(msg)).To(BeFalse())
+	})
+
+	It("matches a regular expression against the message body", func() {
+		Expect(LogFilter{Pattern: regexp.MustCompile(`5\d\d$`)}.Matches(msg)).To(BeTrue())
+		Expect(LogFilter{Pattern: regexp.MustCompile(`POST`)}.Matches(msg)).To(BeFalse())
+	})
+
+	It("matches messages sent at or after a time", func() {
+		Expect(LogFilter{Since: now}.Matches(msg)).To(BeTrue())
+		Expect(LogFilter{Since: now.Add(time.Second)}.Matches(msg)).To(BeFalse())
+	})
+
+	It("always lets reconnect markers through", func() {
+		marker := NewReconnectMessage("app-guid", 3*time.Second, now)
+		filter := LogFilter{
+			SourceTypes: []string{"APP"},
+			MessageType: "ERR",
+			Pattern:     regexp.MustCompile(`POST`),
+		}
+		Expect(filter.Matches(marker)).To(BeTrue())
+	})
+
+	It("requires every criterion to match", func() {
+		filter := LogFilter{
+			SourceTypes: []string{"APP"},
+			MessageType: "ERR",
+			Pattern:     regexp.MustCompile(`POST`),
+		}
+		Expect(filter.Matches(msg)).To(BeFalse())
+	})
+})
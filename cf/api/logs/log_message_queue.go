@@ -0,0 +1,50 @@
+package logs
+
+import (
+	"sort"
+	"sync"
+)
+
+// LogMessageQueue sorts log messages of any kind, from one log stream or
+// several, by their timestamp.
+type LogMessageQueue struct {
+	messages []Loggable
+	mutex    sync.Mutex
+}
+
+func NewLogMessageQueue() *LogMessageQueue {
+	return &LogMessageQueue{}
+}
+
+func (pq *LogMessageQueue) PushMessage(message Loggable) {
+	pq.mutex.Lock()
+	defer pq.mutex.Unlock()
+
+	pq.messages = append(pq.messages, message)
+}
+
+// implement sort interface so we can sort messages as we receive them in PushMessage
+func (pq *LogMessageQueue) Less(i, j int) bool {
+	return pq.messages[i].GetTimestamp().Before(pq.messages[j].GetTimestamp())
+}
+
+func (pq *LogMessageQueue) Swap(i, j int) {
+	pq.messages[i], pq.messages[j] = pq.messages[j], pq.messages[i]
+}
+
+func (pq *LogMessageQueue) Len() int {
+	return len(pq.messages)
+}
+
+func (pq *LogMessageQueue) EnumerateAndClear(onMessage func(Loggable)) {
+	pq.mutex.Lock()
+	defer pq.mutex.Unlock()
+
+	sort.Stable(pq)
+
+	for _, x := range pq.messages {
+		onMessage(x)
+	}
+
+	pq.messages = []Loggable{}
+}
@@ -0,0 +1,66 @@
+package logs_test
+
+import (
+	"time"
+
+	. "github.com/cloudfoundry/cli/cf/api/logs"
+	testlogs "github.com/cloudfoundry/cli/testhelpers/logs"
+	"github.com/cloudfoundry/loggregatorlib/logmessage"
+	"github.com/cloudfoundry/sonde-go/events"
+	"github.com/gogo/protobuf/proto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LogMessageQueue", func() {
+	It("sorts the messages of several apps by their timestamp, clearing after it's enumerated over", func() {
+		pq := NewLogMessageQueue()
+
+		now := time.Now()
+		msg3 := testlogs.NewLogMessage("message 3", "app-1", "APP", "0", logmessage.LogMessage_OUT, now.Add(3*time.Second))
+		msg1 := testlogs.NewLogMessage("message 1", "app-2", "APP", "0", logmessage.LogMessage_OUT, now.Add(1*time.Second))
+		msg2 := testlogs.NewLogMessage("message 2", "app-1", "APP", "0", logmessage.LogMessage_OUT, now.Add(2*time.Second))
+
+		pq.PushMessage(msg3)
+		pq.PushMessage(msg1)
+		pq.PushMessage(msg2)
+
+		var messages []Loggable
+		pq.EnumerateAndClear(func(m Loggable) {
+			messages = append(messages, m)
+		})
+		Expect(messages).To(Equal([]Loggable{msg1, msg2, msg3}))
+
+		var messagesAfter []Loggable
+		pq.EnumerateAndClear(func(m Loggable) {
+			messagesAfter = append(messagesAfter, m)
+		})
+		Expect(messagesAfter).To(BeEmpty())
+	})
+
+	It("sorts messages of different kinds by their timestamp", func() {
+		pq := NewLogMessageQueue()
+
+		now := time.Now()
+		messageType := events.LogMessage_OUT
+		noaaMessage := NewNoaaLogMessage(&events.LogMessage{
+			Message:     []byte("message 2"),
+			AppId:       proto.String("app-1"),
+			MessageType: &messageType,
+			SourceType:  proto.String("APP"),
+			Timestamp:   proto.Int64(now.Add(2 * time.Second).UnixNano()),
+		})
+		reconnect := NewReconnectMessage("app-1", time.Second, now.Add(3*time.Second))
+		loggregatorMessage := testlogs.NewLogMessage("message 1", "app-1", "APP", "0", logmessage.LogMessage_OUT, now.Add(time.Second))
+
+		pq.PushMessage(reconnect)
+		pq.PushMessage(noaaMessage)
+		pq.PushMessage(loggregatorMessage)
+
+		var messages []Loggable
+		pq.EnumerateAndClear(func(m Loggable) {
+			messages = append(messages, m)
+		})
+		Expect(messages).To(Equal([]Loggable{loggregatorMessage, noaaMessage, reconnect}))
+	})
+})
@@ -0,0 +1,37 @@
+package logs
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// LogRecord is a log message in the form it has in `cf logs --format json`
+// output.
+type LogRecord struct {
+	Timestamp      string `json:"timestamp"`
+	AppGUID        string `json:"app_guid"`
+	SourceType     string `json:"source_type"`
+	SourceInstance string `json:"source_instance"`
+	MessageType    string `json:"message_type"`
+	Message        string `json:"message"`
+}
+
+func NewLogRecord(msg Loggable) LogRecord {
+	return LogRecord{
+		Timestamp:      msg.GetTimestamp().UTC().Format(time.RFC3339Nano),
+		AppGUID:        msg.GetAppGUID(),
+		SourceType:     msg.GetSourceName(),
+		SourceInstance: msg.GetSourceInstance(),
+		MessageType:    msg.GetMessageType(),
+		Message:        msg.ToSimpleLog(),
+	}
+}
+
+// ToJSON returns msg as a single line of JSON.
+func ToJSON(msg Loggable) (string, error) {
+	data, err := json.Marshal(NewLogRecord(msg))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,64 @@
+package logs_test
+
+import (
+	"encoding/json"
+	"time"
+
+	. "github.com/cloudfoundry/cli/cf/api/logs"
+	testlogs "github.com/cloudfoundry/cli/testhelpers/logs"
+	"github.com/cloudfoundry/loggregatorlib/logmessage"
+	"github.com/cloudfoundry/sonde-go/events"
+	"github.com/gogo/protobuf/proto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ToJSON", func() {
+	var timestamp time.Time
+
+	BeforeEach(func() {
+		timestamp = time.Date(2016, 7, 1, 12, 30, 15, 123456789, time.FixedZone("CEST", 2*60*60))
+	})
+
+	It("prints a noaa log message as a JSON object", func() {
+		messageType := events.LogMessage_ERR
+		msg := NewNoaaLogMessage(&events.LogMessage{
+			Message:        []byte("something \"bad\" happened\n"),
+			MessageType:    &messageType,
+			Timestamp:      proto.Int64(timestamp.UnixNano()),
+			AppId:          proto.String("app-guid"),
+			SourceType:     proto.String("APP/PROC/WEB"),
+			SourceInstance: proto.String("2"),
+		})
+
+		line, err := ToJSON(msg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(line).To(MatchJSON(`{
+			"timestamp": "2016-07-01T10:30:15.123456789Z",
+			"app_guid": "app-guid",
+			"source_type": "APP/PROC/WEB",
+			"source_instance": "2",
+			"message_type": "ERR",
+			"message": "something \"bad\" happened"
+		}`))
+		Expect(line).NotTo(ContainSubstring("\n"))
+	})
+
+	It("prints a loggregator log message as a JSON object", func() {
+		msg := testlogs.NewLogMessage("GET / 200", "app-guid", "RTR", "0", logmessage.LogMessage_OUT, timestamp)
+
+		line, err := ToJSON(msg)
+		Expect(err).NotTo(HaveOccurred())
+
+		var record LogRecord
+		Expect(json.Unmarshal([]byte(line), &record)).To(Succeed())
+		Expect(record).To(Equal(LogRecord{
+			Timestamp:      "2016-07-01T10:30:15.123456789Z",
+			AppGUID:        "app-guid",
+			SourceType:     "RTR",
+			SourceInstance: "0",
+			MessageType:    "OUT",
+			Message:        "GET / 200",
+		}))
+	})
+})
@@ -6,6 +6,10 @@ type Loggable interface {
 	ToLog(loc *time.Location) string
 	ToSimpleLog() string
 	GetSourceName() string
+	GetSourceInstance() string
+	GetAppGUID() string
+	GetMessageType() string
+	GetTimestamp() time.Time
 }
 
 //go:generate counterfeiter . LogsRepository
@@ -8,7 +8,7 @@ import (
 //go:generate counterfeiter . NoaaConsumer
 
 type NoaaConsumer interface {
-	TailingLogs(string, string) (<-chan *events.LogMessage, <-chan error)
+	TailingLogsWithoutReconnect(string, string) (<-chan *events.LogMessage, <-chan error)
 	RecentLogs(appGuid string, authToken string) ([]*events.LogMessage, error)
 	Close() error
 	SetOnConnectCallback(cb func())
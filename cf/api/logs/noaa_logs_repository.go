@@ -2,6 +2,8 @@ package logs
 
 import (
 	"errors"
+	"fmt"
+	"sync"
 	"time"
 
 	. "github.com/cloudfoundry/cli/cf/i18n"
@@ -18,24 +20,66 @@ type NoaaLogsRepository struct {
 	config         coreconfig.Reader
 	consumer       NoaaConsumer
 	tokenRefresher authentication.TokenRefresher
-	messageQueue   *NoaaMessageQueue
+	messageQueue   *LogMessageQueue
 	BufferTime     time.Duration
+
+	// MaxReconnectAttempts is how many times in a row tailing connects again
+	// after the connection drops, before it gives up and reports the error.
+	MaxReconnectAttempts int
+
+	// ReconnectBackoff is the wait before connecting again. It doubles with
+	// each failed attempt, up to MaxReconnectBackoff.
+	ReconnectBackoff    time.Duration
+	MaxReconnectBackoff time.Duration
+
+	mutex sync.Mutex
+	stops []chan struct{}
 }
 
+const (
+	defaultMaxReconnectAttempts = 10
+	defaultReconnectBackoff     = 500 * time.Millisecond
+	defaultMaxReconnectBackoff  = 30 * time.Second
+	messageHistorySize          = 1000
+)
+
 func NewNoaaLogsRepository(config coreconfig.Reader, consumer NoaaConsumer, tr authentication.TokenRefresher) *NoaaLogsRepository {
 	return &NoaaLogsRepository{
 		config:         config,
 		consumer:       consumer,
 		tokenRefresher: tr,
-		messageQueue:   NewNoaaMessageQueue(),
+		messageQueue:   NewLogMessageQueue(),
 		BufferTime:     defaultBufferTime,
+
+		MaxReconnectAttempts: defaultMaxReconnectAttempts,
+		ReconnectBackoff:     defaultReconnectBackoff,
+		MaxReconnectBackoff:  defaultMaxReconnectBackoff,
 	}
 }
 
+// Close stops the tails started so far. The repository can still be used to
+// tail logs afterwards.
 func (repo *NoaaLogsRepository) Close() {
+	repo.mutex.Lock()
+	for _, stop := range repo.stops {
+		close(stop)
+	}
+	repo.stops = nil
+	repo.mutex.Unlock()
+
 	repo.consumer.Close()
 }
 
+// newStop returns the channel that Close closes to stop a new tail.
+func (repo *NoaaLogsRepository) newStop() chan struct{} {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	stop := make(chan struct{})
+	repo.stops = append(repo.stops, stop)
+	return stop
+}
+
 func loggableMessagesFromNoaaMessages(messages []*events.LogMessage) []Loggable {
 	loggableMessages := make([]Loggable, len(messages))
 
@@ -62,50 +106,233 @@ func (repo *NoaaLogsRepository) RecentLogsFor(appGuid string) ([]Loggable, error
 }
 
 func (repo *NoaaLogsRepository) TailLogsFor(appGuid string, onConnect func(), logChan chan<- Loggable, errChan chan<- error) {
-	ticker := time.NewTicker(repo.BufferTime)
 	endpoint := repo.config.DopplerEndpoint()
 	if endpoint == "" {
 		errChan <- errors.New(T("Loggregator endpoint missing from config file"))
 		return
 	}
 
-	repo.consumer.SetOnConnectCallback(onConnect)
-	c, e := repo.consumer.TailingLogs(appGuid, repo.config.AccessToken())
+	tail := &noaaTail{
+		repo:      repo,
+		appGuid:   appGuid,
+		onConnect: onConnect,
+		logChan:   logChan,
+		history:   newMessageHistory(messageHistorySize),
+		stop:      repo.newStop(),
+		finished:  make(chan bool),
+		flushed:   make(chan struct{}),
+	}
+	repo.consumer.SetOnConnectCallback(tail.connected)
+	c, e := tail.connect()
 
-	go func() {
-		for {
-			select {
-			case msg, ok := <-c:
-				if !ok {
-					repo.flushMessages(logChan)
-					close(logChan)
-					return
-				}
+	go tail.flush()
+	go tail.run(c, e, errChan)
+}
+
+func (repo *NoaaLogsRepository) flushMessages(c chan<- Loggable) {
+	repo.messageQueue.EnumerateAndClear(func(m Loggable) {
+		c <- m
+	})
+}
+
+// noaaTail follows the logs of an app, connecting again with a fresh token
+// and a growing backoff whenever the connection drops.
+type noaaTail struct {
+	repo      *NoaaLogsRepository
+	appGuid   string
+	onConnect func()
+	logChan   chan<- Loggable
+	history   *messageHistory
+	stop      chan struct{}
 
-				repo.messageQueue.PushMessage(msg)
-			case err := <-e:
-				switch err.(type) {
-				case nil:
-				case *noaa_errors.UnauthorizedError:
-					repo.tokenRefresher.RefreshAuthToken()
-					repo.TailLogsFor(appGuid, onConnect, logChan, errChan)
+	// finished tells flush that the tail has ended, and whether to close
+	// logChan; flushed is closed once it has sent the last messages
+	finished chan bool
+	flushed  chan struct{}
+
+	mutex          sync.Mutex
+	everConnected  bool
+	disconnectedAt time.Time
+	failedAttempts int
+	refreshedToken bool
+}
+
+func (t *noaaTail) connect() (<-chan *events.LogMessage, <-chan error) {
+	return t.repo.consumer.TailingLogsWithoutReconnect(t.appGuid, t.repo.config.AccessToken())
+}
+
+func (t *noaaTail) run(c <-chan *events.LogMessage, e <-chan error, errChan chan<- error) {
+	for {
+		err := t.receive(c, e)
+		if err == nil {
+			t.finish(true)
+			return
+		}
+
+		wait, ok := t.nextAttempt(err)
+		if !ok {
+			t.finish(false)
+			errChan <- err
+			return
+		}
+
+		select {
+		case <-t.stop:
+			t.finish(true)
+			return
+		case <-time.After(wait):
+		}
+
+		c, e = t.connect()
+	}
+}
+
+// receive reads the messages of a single connection until it ends, returning
+// nil if it was closed and the error it failed with otherwise.
+func (t *noaaTail) receive(c <-chan *events.LogMessage, e <-chan error) error {
+	for {
+		select {
+		case msg, ok := <-c:
+			if !ok {
+				select {
+				case err := <-e:
+					return err
 				default:
-					errChan <- err
-					return
+					return nil
 				}
 			}
+
+			if t.history.Add(msg) {
+				t.repo.messageQueue.PushMessage(NewNoaaLogMessage(msg))
+			}
+		case err, ok := <-e:
+			if !ok {
+				e = nil
+				continue
+			}
+
+			if err != nil {
+				return err
+			}
 		}
-	}()
+	}
+}
+
+// flush sends the queued messages to logChan every BufferTime, which sorts
+// the messages received in between. It is the only sender on logChan, so
+// that nothing is sent once the tail has closed it.
+func (t *noaaTail) flush() {
+	ticker := time.NewTicker(t.repo.BufferTime)
+	defer ticker.Stop()
 
-	go func() {
-		for _ = range ticker.C {
-			repo.flushMessages(logChan)
+	for {
+		select {
+		case <-ticker.C:
+			t.repo.flushMessages(t.logChan)
+		case closeLogChan := <-t.finished:
+			t.repo.flushMessages(t.logChan)
+			if closeLogChan {
+				close(t.logChan)
+			}
+			close(t.flushed)
+			return
 		}
-	}()
+	}
 }
 
-func (repo *NoaaLogsRepository) flushMessages(c chan<- Loggable) {
-	repo.messageQueue.EnumerateAndClear(func(m *events.LogMessage) {
-		c <- NewNoaaLogMessage(m)
-	})
+// finish stops flush once it has sent the remaining messages.
+func (t *noaaTail) finish(closeLogChan bool) {
+	t.finished <- closeLogChan
+	<-t.flushed
+}
+
+// nextAttempt returns how long to wait before connecting again after err, or
+// false if it is time to give up.
+func (t *noaaTail) nextAttempt(err error) (time.Duration, bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.disconnectedAt.IsZero() {
+		t.disconnectedAt = time.Now()
+	}
+
+	if _, ok := err.(*noaa_errors.UnauthorizedError); ok && !t.refreshedToken {
+		t.refreshedToken = true
+		t.repo.tokenRefresher.RefreshAuthToken()
+		return 0, true
+	}
+
+	if t.failedAttempts >= t.repo.MaxReconnectAttempts {
+		return 0, false
+	}
+
+	wait := t.repo.ReconnectBackoff
+	for i := 0; i < t.failedAttempts && wait < t.repo.MaxReconnectBackoff; i++ {
+		wait *= 2
+	}
+	if wait > t.repo.MaxReconnectBackoff {
+		wait = t.repo.MaxReconnectBackoff
+	}
+	t.failedAttempts++
+
+	return wait, true
+}
+
+// connected is called by the consumer each time it connects. The first time
+// it tells the caller of TailLogsFor; after that it marks the gap in the
+// stream. The marker is queued like any other message, as the callback must
+// not wait for the caller to read logChan.
+func (t *noaaTail) connected() {
+	t.mutex.Lock()
+	firstConnect := !t.everConnected
+	disconnectedAt := t.disconnectedAt
+	t.everConnected = true
+	t.disconnectedAt = time.Time{}
+	t.failedAttempts = 0
+	t.refreshedToken = false
+	t.mutex.Unlock()
+
+	if firstConnect {
+		if t.onConnect != nil {
+			t.onConnect()
+		}
+		return
+	}
+
+	if !disconnectedAt.IsZero() {
+		now := time.Now()
+		t.repo.messageQueue.PushMessage(NewReconnectMessage(t.appGuid, now.Sub(disconnectedAt), now))
+	}
+}
+
+// messageHistory remembers the most recent messages of a log stream, so the
+// ones sent again around a reconnect are only shown once.
+type messageHistory struct {
+	size int
+	keys []string
+	seen map[string]bool
+}
+
+func newMessageHistory(size int) *messageHistory {
+	return &messageHistory{
+		size: size,
+		seen: map[string]bool{},
+	}
+}
+
+// Add remembers msg, returning false if it was seen already.
+func (h *messageHistory) Add(msg *events.LogMessage) bool {
+	key := fmt.Sprintf("%d|%s|%s|%d|%s", msg.GetTimestamp(), msg.GetSourceType(), msg.GetSourceInstance(), msg.GetMessageType(), msg.GetMessage())
+	if h.seen[key] {
+		return false
+	}
+
+	h.seen[key] = true
+	h.keys = append(h.keys, key)
+	if len(h.keys) > h.size {
+		delete(h.seen, h.keys[0])
+		h.keys = h.keys[1:]
+	}
+
+	return true
 }
@@ -2,7 +2,6 @@ package logs_test
 
 import (
 	"errors"
-	"reflect"
 	"time"
 
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
@@ -109,13 +108,14 @@ var _ = Describe("logs with noaa repository", func() {
 		BeforeEach(func() {
 			errChan = make(chan error)
 			logChan = make(chan logs.Loggable)
+			repo.MaxReconnectAttempts = 0
 		})
 
 		Context("when an error occurs", func() {
 			It("returns an error when it occurs", func(done Done) {
 				err := errors.New("oops")
 
-				fakeNoaaConsumer.TailingLogsStub = func(appGuid string, authToken string) (<-chan *events.LogMessage, <-chan error) {
+				fakeNoaaConsumer.TailingLogsWithoutReconnectStub = func(appGuid string, authToken string) (<-chan *events.LogMessage, <-chan error) {
 					errorChan := make(chan error)
 					go func() {
 						errorChan <- err
@@ -145,7 +145,7 @@ var _ = Describe("logs with noaa repository", func() {
 					return nil
 				}
 
-				fakeNoaaConsumer.TailingLogsStub = func(appGuid string, authToken string) (<-chan *events.LogMessage, <-chan error) {
+				fakeNoaaConsumer.TailingLogsWithoutReconnectStub = func(appGuid string, authToken string) (<-chan *events.LogMessage, <-chan error) {
 					errorChan := make(chan error)
 					logChan := make(chan *events.LogMessage)
 
@@ -179,7 +179,7 @@ var _ = Describe("logs with noaa repository", func() {
 
 		Context("when no error occurs", func() {
 			It("asks for the logs for the given app", func(done Done) {
-				fakeNoaaConsumer.TailingLogsStub = func(appGuid string, authToken string) (<-chan *events.LogMessage, <-chan error) {
+				fakeNoaaConsumer.TailingLogsWithoutReconnectStub = func(appGuid string, authToken string) (<-chan *events.LogMessage, <-chan error) {
 					errorChan := make(chan error)
 					go func() {
 						errorChan <- errors.New("quit Tailing")
@@ -190,8 +190,8 @@ var _ = Describe("logs with noaa repository", func() {
 				go func() {
 					defer GinkgoRecover()
 
-					Eventually(fakeNoaaConsumer.TailingLogsCallCount()).Should(Equal(1))
-					appGuid, token := fakeNoaaConsumer.TailingLogsArgsForCall(0)
+					Eventually(fakeNoaaConsumer.TailingLogsWithoutReconnectCallCount()).Should(Equal(1))
+					appGuid, token := fakeNoaaConsumer.TailingLogsWithoutReconnectArgsForCall(0)
 					Expect(appGuid).To(Equal("app-guid"))
 					Expect(token).To(Equal("the-access-token"))
 
@@ -201,8 +201,9 @@ var _ = Describe("logs with noaa repository", func() {
 				repo.TailLogsFor("app-guid", func() {}, logChan, errChan)
 			})
 
-			It("sets the on connect callback", func() {
-				fakeNoaaConsumer.TailingLogsStub = func(appGuid string, authToken string) (<-chan *events.LogMessage, <-chan error) {
+			It("calls the on connect callback when it connects", func() {
+				fakeNoaaConsumer.TailingLogsWithoutReconnectStub = func(appGuid string, authToken string) (<-chan *events.LogMessage, <-chan error) {
+					fakeNoaaConsumer.SetOnConnectCallbackArgsForCall(0)()
 					errorChan := make(chan error)
 					go func() {
 						errorChan <- errors.New("quit Tailing")
@@ -210,12 +211,143 @@ var _ = Describe("logs with noaa repository", func() {
 					return nil, errorChan
 				}
 
-				var cb = func() { return }
-				repo.TailLogsFor("app-guid", cb, logChan, errChan)
+				connected := make(chan bool, 1)
+				repo.TailLogsFor("app-guid", func() { connected <- true }, logChan, errChan)
 
+				Eventually(connected).Should(Receive())
 				Expect(fakeNoaaConsumer.SetOnConnectCallbackCallCount()).To(Equal(1))
-				arg := fakeNoaaConsumer.SetOnConnectCallbackArgsForCall(0)
-				Expect(reflect.ValueOf(arg).Pointer() == reflect.ValueOf(cb).Pointer()).To(BeTrue())
+			})
+		})
+
+		Context("when the connection drops", func() {
+			var (
+				msg1, msg2, msg3 *events.LogMessage
+				connections      chan []*events.LogMessage
+				received         chan logs.Loggable
+				onConnectCount   int
+				onConnectMutex   sync.Mutex
+			)
+
+			BeforeEach(func() {
+				repo.MaxReconnectAttempts = 2
+				repo.ReconnectBackoff = time.Millisecond
+				repo.MaxReconnectBackoff = time.Millisecond
+				onConnectCount = 0
+
+				// the gap is marked at the time of the reconnect, so msg3
+				// is sent after it
+				now := time.Now()
+				msg1 = makeNoaaLogMessage("hello1", now.Add(-2*time.Second).UnixNano())
+				msg2 = makeNoaaLogMessage("hello2", now.Add(-time.Second).UnixNano())
+				msg3 = makeNoaaLogMessage("hello3", now.Add(time.Hour).UnixNano())
+				connections = make(chan []*events.LogMessage, 3)
+
+				fakeNoaaConsumer.TailingLogsWithoutReconnectStub = func(appGuid string, authToken string) (<-chan *events.LogMessage, <-chan error) {
+					outputChan := make(chan *events.LogMessage)
+					errorChan := make(chan error, 1)
+					go func() {
+						defer close(errorChan)
+						defer close(outputChan)
+
+						select {
+						case messages := <-connections:
+							fakeNoaaConsumer.SetOnConnectCallbackArgsForCall(0)()
+							for _, msg := range messages {
+								outputChan <- msg
+							}
+							errorChan <- errors.New("connection reset")
+						default:
+							errorChan <- errors.New("connection refused")
+						}
+					}()
+					return outputChan, errorChan
+				}
+
+				received = make(chan logs.Loggable, 10)
+				go func(logChan <-chan logs.Loggable, received chan<- logs.Loggable) {
+					for msg := range logChan {
+						received <- msg
+					}
+				}(logChan, received)
+			})
+
+			onConnect := func() {
+				onConnectMutex.Lock()
+				defer onConnectMutex.Unlock()
+				onConnectCount++
+			}
+
+			It("connects again, marks the gap and drops the messages sent again", func() {
+				connections <- []*events.LogMessage{msg1, msg2}
+				connections <- []*events.LogMessage{msg2, msg3}
+
+				repo.TailLogsFor("app-guid", onConnect, logChan, errChan)
+
+				Eventually(errChan).Should(Receive(MatchError("connection refused")))
+				Expect(fakeNoaaConsumer.TailingLogsWithoutReconnectCallCount()).To(Equal(4))
+
+				var messages []logs.Loggable
+				Eventually(func() int {
+					for {
+						select {
+						case msg := <-received:
+							messages = append(messages, msg)
+						default:
+							return len(messages)
+						}
+					}
+				}).Should(Equal(4))
+
+				Expect(messages[0]).To(Equal(logs.NewNoaaLogMessage(msg1)))
+				Expect(messages[1]).To(Equal(logs.NewNoaaLogMessage(msg2)))
+				Expect(messages[2].ToSimpleLog()).To(MatchRegexp(`^\[log stream reconnected after \d+s; messages may have been missed\]$`))
+				Expect(messages[2].GetAppGUID()).To(Equal("app-guid"))
+				Expect(messages[3]).To(Equal(logs.NewNoaaLogMessage(msg3)))
+
+				onConnectMutex.Lock()
+				defer onConnectMutex.Unlock()
+				Expect(onConnectCount).To(Equal(1))
+			})
+
+			It("connects again after the repository was closed before", func() {
+				repo.Close()
+
+				connections <- []*events.LogMessage{msg1}
+
+				repo.TailLogsFor("app-guid", onConnect, logChan, errChan)
+
+				Eventually(errChan).Should(Receive(MatchError("connection refused")))
+				Expect(fakeNoaaConsumer.TailingLogsWithoutReconnectCallCount()).To(Equal(3))
+			})
+
+			It("gives up after the maximum number of failed attempts in a row", func() {
+				repo.TailLogsFor("app-guid", onConnect, logChan, errChan)
+
+				Eventually(errChan).Should(Receive(MatchError("connection refused")))
+				Expect(fakeNoaaConsumer.TailingLogsWithoutReconnectCallCount()).To(Equal(3))
+			})
+
+			It("asks for the logs with a refreshed token when the token has expired", func() {
+				fakeNoaaConsumer.TailingLogsWithoutReconnectStub = func(appGuid string, authToken string) (<-chan *events.LogMessage, <-chan error) {
+					errorChan := make(chan error, 1)
+					if authToken == "the-access-token" {
+						errorChan <- noaa_errors.NewUnauthorizedError("token expired")
+					} else {
+						errorChan <- errors.New("oops")
+					}
+					return nil, errorChan
+				}
+				fakeTokenRefresher.RefreshAuthTokenStub = func() (string, error) {
+					config.SetAccessToken("the-new-token")
+					return "the-new-token", nil
+				}
+
+				repo.TailLogsFor("app-guid", onConnect, logChan, errChan)
+
+				Eventually(errChan).Should(Receive(MatchError("oops")))
+				Expect(fakeTokenRefresher.RefreshAuthTokenCallCount()).To(Equal(1))
+				_, token := fakeNoaaConsumer.TailingLogsWithoutReconnectArgsForCall(1)
+				Expect(token).To(Equal("the-new-token"))
 			})
 		})
 
@@ -237,7 +369,7 @@ var _ = Describe("logs with noaa repository", func() {
 				msg2 = makeNoaaLogMessage("hello2", 200)
 				msg3 = makeNoaaLogMessage("hello3", 300)
 
-				fakeNoaaConsumer.TailingLogsStub = func(appGuid string, authToken string) (<-chan *events.LogMessage, <-chan error) {
+				fakeNoaaConsumer.TailingLogsWithoutReconnectStub = func(appGuid string, authToken string) (<-chan *events.LogMessage, <-chan error) {
 					closeWait.Add(1)
 
 					go writeToChan()
@@ -284,6 +416,35 @@ var _ = Describe("logs with noaa repository", func() {
 						logs.NewNoaaLogMessage(msg3),
 					}))
 				})
+
+				It("stops flushing into the channel of a tail once it has ended", func() {
+					repo.BufferTime = 10 * time.Millisecond
+					fakeNoaaConsumer.TailingLogsWithoutReconnectStub = func(appGuid string, authToken string) (<-chan *events.LogMessage, <-chan error) {
+						errorChan = make(chan error)
+						outputChan = make(chan *events.LogMessage)
+						go writeToChan()
+						return outputChan, errorChan
+					}
+					fakeNoaaConsumer.CloseStub = nil
+
+					repo.TailLogsFor("app-guid", func() {}, logChan, errChan)
+					Eventually(logChan).Should(BeClosed())
+
+					nextLogChan := make(chan logs.Loggable)
+					repo.TailLogsFor("app-guid", func() {}, nextLogChan, errChan)
+
+					receivedMessages := []logs.Loggable{}
+					for msg := range nextLogChan {
+						receivedMessages = append(receivedMessages, msg)
+					}
+					repo.Close()
+
+					Expect(receivedMessages).To(Equal([]logs.Loggable{
+						logs.NewNoaaLogMessage(msg1),
+						logs.NewNoaaLogMessage(msg2),
+						logs.NewNoaaLogMessage(msg3),
+					}))
+				})
 			})
 
 			Context("when the channels are read while being written to", func() {
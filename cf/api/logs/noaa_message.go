@@ -30,6 +30,25 @@ func (m *noaaLogMessage) GetSourceName() string {
 	return m.msg.GetSourceType()
 }
 
+func (m *noaaLogMessage) GetAppGUID() string {
+	return m.msg.GetAppId()
+}
+
+func (m *noaaLogMessage) GetSourceInstance() string {
+	return m.msg.GetSourceInstance()
+}
+
+func (m *noaaLogMessage) GetMessageType() string {
+	if m.msg.GetMessageType() == events.LogMessage_ERR {
+		return "ERR"
+	}
+	return "OUT"
+}
+
+func (m *noaaLogMessage) GetTimestamp() time.Time {
+	return time.Unix(0, m.msg.GetTimestamp())
+}
+
 func (m *noaaLogMessage) ToLog(loc *time.Location) string {
 	logMsg := m.msg
 
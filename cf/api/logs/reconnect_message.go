@@ -0,0 +1,55 @@
+package logs
+
+import (
+	"time"
+
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/terminal"
+)
+
+// ReconnectMessage marks the place in a log stream where the connection to
+// the log server dropped and was made again, so messages sent in between may
+// be missing.
+type ReconnectMessage struct {
+	AppGUID     string
+	Gap         time.Duration
+	ConnectedAt time.Time
+}
+
+func NewReconnectMessage(appGuid string, gap time.Duration, connectedAt time.Time) *ReconnectMessage {
+	return &ReconnectMessage{
+		AppGUID:     appGuid,
+		Gap:         gap,
+		ConnectedAt: connectedAt,
+	}
+}
+
+func (m *ReconnectMessage) ToSimpleLog() string {
+	return T("[log stream reconnected after {{.Seconds}}s; messages may have been missed]", map[string]interface{}{
+		"Seconds": int64(m.Gap.Seconds() + 0.5),
+	})
+}
+
+func (m *ReconnectMessage) ToLog(loc *time.Location) string {
+	return terminal.WarningColor(m.ToSimpleLog())
+}
+
+func (m *ReconnectMessage) GetSourceName() string {
+	return "CLI"
+}
+
+func (m *ReconnectMessage) GetSourceInstance() string {
+	return ""
+}
+
+func (m *ReconnectMessage) GetAppGUID() string {
+	return m.AppGUID
+}
+
+func (m *ReconnectMessage) GetMessageType() string {
+	return "GAP"
+}
+
+func (m *ReconnectMessage) GetTimestamp() time.Time {
+	return m.ConnectedAt
+}
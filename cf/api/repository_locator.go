@@ -46,7 +46,7 @@ type RepositoryLocator struct {
 	quotaRepo                       quotas.QuotaRepository
 	spaceRepo                       spaces.SpaceRepository
 	appRepo                         applications.ApplicationRepository
-	appBitsRepo                     applicationbits.CloudControllerApplicationBitsRepository
+	appBitsRepo                     applicationbits.ApplicationBitsRepository
 	appSummaryRepo                  AppSummaryRepository
 	appInstancesRepo                appinstances.AppInstancesRepository
 	appEventsRepo                   appevents.AppEventsRepository
@@ -63,6 +63,7 @@ type RepositoryLocator struct {
 	userRepo                        UserRepository
 	passwordRepo                    password.PasswordRepository
 	logsRepo                        logs.LogsRepository
+	newLogsRepo                     func() logs.LogsRepository
 	authTokenRepo                   ServiceAuthTokenRepository
 	serviceBrokerRepo               ServiceBrokerRepository
 	servicePlanRepo                 CloudControllerServicePlanRepository
@@ -94,7 +95,7 @@ func NewRepositoryLocator(config coreconfig.ReadWriter, gatewaysByName map[strin
 	cloudControllerGateway.SetTokenRefresher(loc.authRepo)
 	uaaGateway.SetTokenRefresher(loc.authRepo)
 
-	loc.appBitsRepo = applicationbits.NewCloudControllerApplicationBitsRepository(config, cloudControllerGateway)
+	loc.appBitsRepo = applicationbits.NewCloudControllerApplicationBitsRepository(config, cloudControllerGateway, appfiles.ApplicationZipper{})
 	loc.appEventsRepo = appevents.NewCloudControllerAppEventsRepository(config, cloudControllerGateway, strategy)
 	loc.appFilesRepo = api_appfiles.NewCloudControllerAppFilesRepository(config, cloudControllerGateway)
 	loc.appRepo = applications.NewCloudControllerApplicationRepository(config, cloudControllerGateway)
@@ -109,15 +110,19 @@ func NewRepositoryLocator(config coreconfig.ReadWriter, gatewaysByName map[strin
 
 	apiVersion, _ := semver.Make(config.APIVersion())
 
-	if apiVersion.GTE(cf.NoaaMinimumAPIVersion) {
-		consumer := consumer.New(config.DopplerEndpoint(), tlsConfig, http.ProxyFromEnvironment)
-		consumer.SetDebugPrinter(terminal.DebugPrinter{Logger: logger})
-		loc.logsRepo = logs.NewNoaaLogsRepository(config, consumer, loc.authRepo)
-	} else {
+	authRepo := loc.authRepo
+	loc.newLogsRepo = func() logs.LogsRepository {
+		if apiVersion.GTE(cf.NoaaMinimumAPIVersion) {
+			consumer := consumer.New(config.DopplerEndpoint(), tlsConfig, http.ProxyFromEnvironment)
+			consumer.SetDebugPrinter(terminal.DebugPrinter{Logger: logger})
+			return logs.NewNoaaLogsRepository(config, consumer, authRepo)
+		}
+
 		consumer := loggregator_consumer.New(config.LoggregatorEndpoint(), tlsConfig, http.ProxyFromEnvironment)
 		consumer.SetDebugPrinter(terminal.DebugPrinter{Logger: logger})
-		loc.logsRepo = logs.NewLoggregatorLogsRepository(config, consumer, loc.authRepo)
+		return logs.NewLoggregatorLogsRepository(config, consumer, authRepo)
 	}
+	loc.logsRepo = loc.newLogsRepo()
 
 	loc.organizationRepo = organizations.NewCloudControllerOrganizationRepository(config, cloudControllerGateway)
 	loc.passwordRepo = password.NewCloudControllerPasswordRepository(config, uaaGateway)
@@ -216,6 +221,11 @@ func (locator RepositoryLocator) GetApplicationRepository() applications.Applica
 	return locator.appRepo
 }
 
+func (locator RepositoryLocator) SetApplicationBitsRepository(repo applicationbits.ApplicationBitsRepository) RepositoryLocator {
+	locator.appBitsRepo = repo
+	return locator
+}
+
 func (locator RepositoryLocator) GetApplicationBitsRepository() applicationbits.ApplicationBitsRepository {
 	return locator.appBitsRepo
 }
@@ -354,8 +364,11 @@ func (locator RepositoryLocator) GetPasswordRepository() password.PasswordReposi
 	return locator.passwordRepo
 }
 
+// SetLogsRepository replaces the logs repository, including the ones
+// NewLogsRepository returns.
 func (locator RepositoryLocator) SetLogsRepository(repo logs.LogsRepository) RepositoryLocator {
 	locator.logsRepo = repo
+	locator.newLogsRepo = nil
 	return locator
 }
 
@@ -363,6 +376,16 @@ func (locator RepositoryLocator) GetLogsRepository() logs.LogsRepository {
 	return locator.logsRepo
 }
 
+// NewLogsRepository returns a logs repository with a connection of its own,
+// for tailing the logs of several apps at the same time. The consumer behind
+// the repository from GetLogsRepository can only follow one app at a time.
+func (locator RepositoryLocator) NewLogsRepository() logs.LogsRepository {
+	if locator.newLogsRepo == nil {
+		return locator.logsRepo
+	}
+	return locator.newLogsRepo()
+}
+
 func (locator RepositoryLocator) SetServiceAuthTokenRepository(repo ServiceAuthTokenRepository) RepositoryLocator {
 	locator.authTokenRepo = repo
 	return locator
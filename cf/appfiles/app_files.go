@@ -6,6 +6,7 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
 	"runtime"
 
@@ -18,15 +19,38 @@ const windowsPathPrefix = `\\?\`
 //go:generate counterfeiter . AppFiles
 
 type AppFiles interface {
-	AppFilesInDir(dir string) (appFiles []models.AppFileFields, err error)
+	AppFilesInDir(dir string, cache *AppResourceCache) (appFiles []models.AppFileFields, err error)
 	CopyFiles(appFiles []models.AppFileFields, fromDir, toDir string) (err error)
 	CountFiles(directory string) int64
 	WalkAppFiles(dir string, onEachFile func(string, string) error) (err error)
+	IgnoredFilesInDir(dir string) (ignoredFiles []IgnoredFile, err error)
+	WithGitignore() AppFiles
 }
 
-type ApplicationFiles struct{}
+type ApplicationFiles struct {
+	useGitignore bool
+}
+
+// IgnoredFile is a file or directory of an app that a rule of an ignore file
+// matches. A directory's path ends in a slash.
+type IgnoredFile struct {
+	Path    string
+	Ignored bool // false when the rule includes it again, with `!`
+	Rule    IgnoreRule
+}
 
-func (appfiles ApplicationFiles) AppFilesInDir(dir string) (appFiles []models.AppFileFields, err error) {
+// WithGitignore returns AppFiles that also ignore the files listed in the
+// .gitignore files of the app. Where both apply, .cfignore rules take
+// precedence.
+func (appfiles ApplicationFiles) WithGitignore() AppFiles {
+	appfiles.useGitignore = true
+	return appfiles
+}
+
+// AppFilesInDir lists the files of the app in dir with their SHA1s. When a
+// cache is given, files whose size and modification time have not changed
+// since they were last hashed are not hashed again.
+func (appfiles ApplicationFiles) AppFilesInDir(dir string, cache *AppResourceCache) (appFiles []models.AppFileFields, err error) {
 	dir, err = filepath.Abs(dir)
 	if err != nil {
 		return
@@ -46,6 +70,8 @@ func (appfiles ApplicationFiles) AppFilesInDir(dir string) (appFiles []models.Ap
 		if fileInfo.IsDir() {
 			appFile.Sha1 = "0"
 			appFile.Size = 0
+		} else if cachedSha1, found := cachedFileSha1(cache, appFile.Path, fileInfo); found {
+			appFile.Sha1 = cachedSha1
 		} else {
 			hash := sha1.New()
 			file, err := os.Open(fullPath)
@@ -60,6 +86,9 @@ func (appfiles ApplicationFiles) AppFilesInDir(dir string) (appFiles []models.Ap
 			}
 
 			appFile.Sha1 = fmt.Sprintf("%x", hash.Sum(nil))
+			if cache != nil {
+				cache.SetSha1(appFile.Path, fileInfo, appFile.Sha1)
+			}
 		}
 
 		appFiles = append(appFiles, appFile)
@@ -70,6 +99,13 @@ func (appfiles ApplicationFiles) AppFilesInDir(dir string) (appFiles []models.Ap
 	return
 }
 
+func cachedFileSha1(cache *AppResourceCache, path string, fileInfo os.FileInfo) (string, bool) {
+	if cache == nil {
+		return "", false
+	}
+	return cache.Sha1(path, fileInfo)
+}
+
 func (appfiles ApplicationFiles) CopyFiles(appFiles []models.AppFileFields, fromDir, toDir string) error {
 	for _, file := range appFiles {
 		err := func() error {
@@ -144,8 +180,26 @@ func (appfiles ApplicationFiles) CountFiles(directory string) int64 {
 	return count
 }
 
+// IgnoredFilesInDir lists the files and directories in dir that a rule of an
+// ignore file matches, along with that rule. The contents of an ignored
+// directory are not listed.
+func (appfiles ApplicationFiles) IgnoredFilesInDir(dir string) ([]IgnoredFile, error) {
+	ignoredFiles := []IgnoredFile{}
+	err := appfiles.walkAppFiles(dir, func(_, _ string) error { return nil }, func(ignoredFile IgnoredFile) {
+		ignoredFiles = append(ignoredFiles, ignoredFile)
+	})
+	return ignoredFiles, err
+}
+
+// WalkAppFiles calls onEachFile with the relative and the full path of each
+// file and directory in dir that is not ignored by the .cfignore files in it
+// and its subdirectories.
 func (appfiles ApplicationFiles) WalkAppFiles(dir string, onEachFile func(string, string) error) error {
-	cfIgnore := loadIgnoreFile(dir)
+	return appfiles.walkAppFiles(dir, onEachFile, func(IgnoredFile) {})
+}
+
+func (appfiles ApplicationFiles) walkAppFiles(dir string, onEachFile func(string, string) error, onEachMatch func(IgnoredFile)) error {
+	cfIgnore := newCfIgnore()
 	walkFunc := func(fullPath string, f os.FileInfo, err error) error {
 		fileRelativePath, _ := filepath.Rel(dir, fullPath)
 		fileRelativeUnixPath := filepath.ToSlash(fileRelativePath)
@@ -158,17 +212,32 @@ func (appfiles ApplicationFiles) WalkAppFiles(dir string, onEachFile func(string
 			fullPath = windowsPathPrefix + fullPath
 		}
 
-		if cfIgnore.FileShouldBeIgnored(fileRelativeUnixPath) {
-			if err == nil && f.IsDir() {
-				return filepath.SkipDir
+		isDir := err == nil && f.IsDir()
+
+		// the parent directories of the file were checked before it
+		if pattern := cfIgnore.patternFor(fileRelativeUnixPath, isDir); pattern != nil && fullPath != dir {
+			matchedPath := fileRelativeUnixPath
+			if isDir {
+				matchedPath += "/"
+			}
+			onEachMatch(IgnoredFile{Path: matchedPath, Ignored: pattern.exclude, Rule: pattern.IgnoreRule})
+
+			if pattern.exclude {
+				if isDir {
+					return filepath.SkipDir
+				}
+				return nil
 			}
-			return nil
 		}
 
 		if err != nil {
 			return err
 		}
 
+		if isDir {
+			appfiles.loadIgnoreFiles(cfIgnore, fullPath, fileRelativeUnixPath)
+		}
+
 		if fullPath == dir {
 			return nil
 		}
@@ -183,11 +252,25 @@ func (appfiles ApplicationFiles) WalkAppFiles(dir string, onEachFile func(string
 	return filepath.Walk(dir, walkFunc)
 }
 
-func loadIgnoreFile(dir string) CfIgnore {
-	fileContents, err := ioutil.ReadFile(filepath.Join(dir, ".cfignore"))
-	if err != nil {
-		return NewCfIgnore("")
+// loadIgnoreFiles adds the patterns of the ignore files in the directory at
+// fullPath to cfIgnore. The .cfignore file is read last so that its patterns
+// take precedence.
+func (appfiles ApplicationFiles) loadIgnoreFiles(cfIgnore *cfIgnore, fullPath string, relativePath string) {
+	base := relativePath
+	if base == "." {
+		base = ""
+	}
+
+	fileNames := []string{".cfignore"}
+	if appfiles.useGitignore {
+		fileNames = []string{".gitignore", ".cfignore"}
 	}
 
-	return NewCfIgnore(string(fileContents))
+	for _, fileName := range fileNames {
+		fileContents, err := ioutil.ReadFile(filepath.Join(fullPath, fileName))
+		if err != nil {
+			continue
+		}
+		cfIgnore.addPatterns(base, path.Join(base, fileName), string(fileContents))
+	}
 }
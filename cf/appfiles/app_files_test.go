@@ -46,7 +46,7 @@ var _ = Describe("AppFiles", func() {
 
 	Describe("AppFilesInDir", func() {
 		It("all files have '/' path separators", func() {
-			files, err := appFiles.AppFilesInDir(fixturePath)
+			files, err := appFiles.AppFilesInDir(fixturePath, nil)
 			Expect(err).ShouldNot(HaveOccurred())
 
 			for _, afile := range files {
@@ -56,7 +56,7 @@ var _ = Describe("AppFiles", func() {
 
 		It("excludes files based on the .cfignore file", func() {
 			appPath := filepath.Join(fixturePath, "app-with-cfignore")
-			files, err := appFiles.AppFilesInDir(appPath)
+			files, err := appFiles.AppFilesInDir(appPath, nil)
 			Expect(err).ShouldNot(HaveOccurred())
 
 			paths := []string{}
@@ -64,19 +64,71 @@ var _ = Describe("AppFiles", func() {
 				paths = append(paths, file.Path)
 			}
 
+			// dir1/child-dir/file3.txt can't be included again, as the directory
+			// it is in is ignored
 			Expect(paths).To(Equal([]string{
 				"dir1",
-				"dir1/child-dir",
-				"dir1/child-dir/file3.txt",
 				"dir1/file1.txt",
 				"dir2",
+			}))
+		})
+
+		It("applies the patterns of nested .cfignore files to the directories they are in", func() {
+			appPath, err := ioutil.TempDir("", "nested-cfignore")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(appPath)
+
+			writeFile := func(name string, contents string) {
+				fullPath := filepath.Join(appPath, filepath.FromSlash(name))
+				Expect(os.MkdirAll(filepath.Dir(fullPath), 0755)).To(Succeed())
+				Expect(ioutil.WriteFile(fullPath, []byte(contents), 0644)).To(Succeed())
+			}
+
+			writeFile(".cfignore", "*.log\ntmp/\n")
+			writeFile("app.rb", "")
+			writeFile("app.log", "")
+			writeFile("tmp/cache", "")
+			writeFile("lib/tmp", "")
+			writeFile("lib/.cfignore", "/local.rb\n!keep.log\n")
+			writeFile("lib/local.rb", "")
+			writeFile("lib/keep.log", "")
+			writeFile("lib/deep/local.rb", "")
+
+			files, err := appFiles.AppFilesInDir(appPath, nil)
+			Expect(err).NotTo(HaveOccurred())
 
-				// TODO: this should be excluded.
-				// .cfignore doesn't handle ** patterns right now
-				"dir2/child-dir2",
+			paths := []string{}
+			for _, file := range files {
+				paths = append(paths, file.Path)
+			}
+
+			Expect(paths).To(Equal([]string{
+				"app.rb",
+				"lib",
+				"lib/deep",
+				"lib/deep/local.rb",
+				"lib/keep.log",
+				"lib/tmp",
 			}))
 		})
 
+		It("only honors .gitignore files when asked to", func() {
+			appPath, err := ioutil.TempDir("", "gitignore")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(appPath)
+
+			Expect(ioutil.WriteFile(filepath.Join(appPath, ".gitignore"), []byte("*.o\n"), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(appPath, "main.o"), []byte{}, 0644)).To(Succeed())
+
+			files, err := appFiles.AppFilesInDir(appPath, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(HaveLen(1))
+
+			files, err = appFiles.WithGitignore().AppFilesInDir(appPath, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(BeEmpty())
+		})
+
 		// NB: on windows, you can never rely on the size of a directory being zero
 		// see: http://msdn.microsoft.com/en-us/library/windows/desktop/aa364946(v=vs.85).aspx
 		// and: https://www.pivotaltracker.com/story/show/70470232
@@ -87,7 +139,7 @@ var _ = Describe("AppFiles", func() {
 				err = os.Mkdir(filepath.Join(tempdir, "nothing"), 0600)
 				Expect(err).ToNot(HaveOccurred())
 
-				files, err := appFiles.AppFilesInDir(tempdir)
+				files, err := appFiles.AppFilesInDir(tempdir, nil)
 				Expect(err).ToNot(HaveOccurred())
 
 				sizes := []int64{}
@@ -100,6 +152,30 @@ var _ = Describe("AppFiles", func() {
 		})
 	})
 
+	Describe("IgnoredFilesInDir", func() {
+		It("lists the ignored files with the rule that matched them", func() {
+			appPath := filepath.Join(fixturePath, "app-with-cfignore")
+			ignoredFiles, err := appFiles.IgnoredFilesInDir(appPath)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(ignoredFiles).To(ContainElement(appfiles.IgnoredFile{
+				Path:    ".cfignore",
+				Ignored: true,
+				Rule:    appfiles.IgnoreRule{Pattern: ".cfignore"},
+			}))
+			Expect(ignoredFiles).To(ContainElement(appfiles.IgnoredFile{
+				Path:    "dir1/child-dir/",
+				Ignored: true,
+				Rule:    appfiles.IgnoreRule{Source: ".cfignore", Line: 1, Pattern: "dir1/**/*"},
+			}))
+			Expect(ignoredFiles).To(ContainElement(appfiles.IgnoredFile{
+				Path:    "dir1/file1.txt",
+				Ignored: false,
+				Rule:    appfiles.IgnoreRule{Source: ".cfignore", Line: 2, Pattern: "!dir1/file1.txt"},
+			}))
+		})
+	})
+
 	Describe("CopyFiles", func() {
 		It("copies only the files specified", func() {
 			copyDir := filepath.Join(fixturePath, "app-copy-test")
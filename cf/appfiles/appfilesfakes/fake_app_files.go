@@ -9,10 +9,11 @@ import (
 )
 
 type FakeAppFiles struct {
-	AppFilesInDirStub        func(dir string) (appFiles []models.AppFileFields, err error)
+	AppFilesInDirStub        func(dir string, cache *appfiles.AppResourceCache) (appFiles []models.AppFileFields, err error)
 	appFilesInDirMutex       sync.RWMutex
 	appFilesInDirArgsForCall []struct {
-		dir string
+		dir   string
+		cache *appfiles.AppResourceCache
 	}
 	appFilesInDirReturns struct {
 		result1 []models.AppFileFields
@@ -45,16 +46,32 @@ type FakeAppFiles struct {
 	walkAppFilesReturns struct {
 		result1 error
 	}
+	IgnoredFilesInDirStub        func(dir string) (ignoredFiles []appfiles.IgnoredFile, err error)
+	ignoredFilesInDirMutex       sync.RWMutex
+	ignoredFilesInDirArgsForCall []struct {
+		dir string
+	}
+	ignoredFilesInDirReturns struct {
+		result1 []appfiles.IgnoredFile
+		result2 error
+	}
+	WithGitignoreStub        func() appfiles.AppFiles
+	withGitignoreMutex       sync.RWMutex
+	withGitignoreArgsForCall []struct{}
+	withGitignoreReturns     struct {
+		result1 appfiles.AppFiles
+	}
 }
 
-func (fake *FakeAppFiles) AppFilesInDir(dir string) (appFiles []models.AppFileFields, err error) {
+func (fake *FakeAppFiles) AppFilesInDir(dir string, cache *appfiles.AppResourceCache) (appFiles []models.AppFileFields, err error) {
 	fake.appFilesInDirMutex.Lock()
 	fake.appFilesInDirArgsForCall = append(fake.appFilesInDirArgsForCall, struct {
-		dir string
-	}{dir})
+		dir   string
+		cache *appfiles.AppResourceCache
+	}{dir, cache})
 	fake.appFilesInDirMutex.Unlock()
 	if fake.AppFilesInDirStub != nil {
-		return fake.AppFilesInDirStub(dir)
+		return fake.AppFilesInDirStub(dir, cache)
 	} else {
 		return fake.appFilesInDirReturns.result1, fake.appFilesInDirReturns.result2
 	}
@@ -66,10 +83,10 @@ func (fake *FakeAppFiles) AppFilesInDirCallCount() int {
 	return len(fake.appFilesInDirArgsForCall)
 }
 
-func (fake *FakeAppFiles) AppFilesInDirArgsForCall(i int) string {
+func (fake *FakeAppFiles) AppFilesInDirArgsForCall(i int) (string, *appfiles.AppResourceCache) {
 	fake.appFilesInDirMutex.RLock()
 	defer fake.appFilesInDirMutex.RUnlock()
-	return fake.appFilesInDirArgsForCall[i].dir
+	return fake.appFilesInDirArgsForCall[i].dir, fake.appFilesInDirArgsForCall[i].cache
 }
 
 func (fake *FakeAppFiles) AppFilesInDirReturns(result1 []models.AppFileFields, result2 error) {
@@ -179,4 +196,61 @@ func (fake *FakeAppFiles) WalkAppFilesReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeAppFiles) IgnoredFilesInDir(dir string) (ignoredFiles []appfiles.IgnoredFile, err error) {
+	fake.ignoredFilesInDirMutex.Lock()
+	fake.ignoredFilesInDirArgsForCall = append(fake.ignoredFilesInDirArgsForCall, struct {
+		dir string
+	}{dir})
+	fake.ignoredFilesInDirMutex.Unlock()
+	if fake.IgnoredFilesInDirStub != nil {
+		return fake.IgnoredFilesInDirStub(dir)
+	} else {
+		return fake.ignoredFilesInDirReturns.result1, fake.ignoredFilesInDirReturns.result2
+	}
+}
+
+func (fake *FakeAppFiles) IgnoredFilesInDirCallCount() int {
+	fake.ignoredFilesInDirMutex.RLock()
+	defer fake.ignoredFilesInDirMutex.RUnlock()
+	return len(fake.ignoredFilesInDirArgsForCall)
+}
+
+func (fake *FakeAppFiles) IgnoredFilesInDirArgsForCall(i int) string {
+	fake.ignoredFilesInDirMutex.RLock()
+	defer fake.ignoredFilesInDirMutex.RUnlock()
+	return fake.ignoredFilesInDirArgsForCall[i].dir
+}
+
+func (fake *FakeAppFiles) IgnoredFilesInDirReturns(result1 []appfiles.IgnoredFile, result2 error) {
+	fake.IgnoredFilesInDirStub = nil
+	fake.ignoredFilesInDirReturns = struct {
+		result1 []appfiles.IgnoredFile
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppFiles) WithGitignore() appfiles.AppFiles {
+	fake.withGitignoreMutex.Lock()
+	fake.withGitignoreArgsForCall = append(fake.withGitignoreArgsForCall, struct{}{})
+	fake.withGitignoreMutex.Unlock()
+	if fake.WithGitignoreStub != nil {
+		return fake.WithGitignoreStub()
+	} else {
+		return fake.withGitignoreReturns.result1
+	}
+}
+
+func (fake *FakeAppFiles) WithGitignoreCallCount() int {
+	fake.withGitignoreMutex.RLock()
+	defer fake.withGitignoreMutex.RUnlock()
+	return len(fake.withGitignoreArgsForCall)
+}
+
+func (fake *FakeAppFiles) WithGitignoreReturns(result1 appfiles.AppFiles) {
+	fake.WithGitignoreStub = nil
+	fake.withGitignoreReturns = struct {
+		result1 appfiles.AppFiles
+	}{result1}
+}
+
 var _ appfiles.AppFiles = new(FakeAppFiles)
@@ -1,6 +1,7 @@
 package appfiles
 
 import (
+	"fmt"
 	"path"
 	"strings"
 
@@ -10,70 +11,166 @@ import (
 //go:generate counterfeiter . CfIgnore
 
 type CfIgnore interface {
+	// FileShouldBeIgnored tells whether the file at path, relative to the app
+	// directory, is ignored. A path ending in a slash is a directory.
 	FileShouldBeIgnored(path string) bool
 }
 
+// IgnoreRule is a line of an ignore file, or one of the patterns that are
+// ignored by default.
+type IgnoreRule struct {
+	Source  string // path of the ignore file, relative to the app directory
+	Line    int
+	Pattern string
+}
+
+func (rule IgnoreRule) String() string {
+	if rule.Source == "" {
+		return fmt.Sprintf("(default) %s", rule.Pattern)
+	}
+	return fmt.Sprintf("%s:%d: %s", rule.Source, rule.Line, rule.Pattern)
+}
+
+// NewCfIgnore reads text as the .cfignore file at the root of an app
+// directory. Its patterns follow the rules of .gitignore files:
+//  - a pattern with a slash before its end, like `/tmp` or `a/b`, matches
+//    paths relative to the directory of the ignore file; one without matches
+//    the name of a file or directory in it at any depth
+//  - a pattern ending in a slash only matches directories
+//  - a pattern starting with `!` includes again what an earlier one ignored,
+//    unless a parent directory of it is ignored
+//  - lines starting with `#` are comments, and `\` escapes special chars
 func NewCfIgnore(text string) CfIgnore {
-	patterns := []ignorePattern{}
-	lines := strings.Split(text, "\n")
-	lines = append(defaultIgnoreLines, lines...)
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
+	ignore := newCfIgnore()
+	ignore.addPatterns("", ".cfignore", text)
+	return ignore
+}
 
-		ignore := true
-		if strings.HasPrefix(line, "!") {
-			line = line[1:]
-			ignore = false
-		}
+type ignorePattern struct {
+	IgnoreRule
+	base     string
+	exclude  bool
+	dirOnly  bool
+	anchored bool
+	glob     glob.Glob
+}
 
-		for _, p := range globsForPattern(path.Clean(line)) {
-			patterns = append(patterns, ignorePattern{ignore, p})
-		}
+type cfIgnore struct {
+	patterns []ignorePattern
+}
+
+func newCfIgnore() *cfIgnore {
+	ignore := &cfIgnore{}
+	for _, line := range defaultIgnoreLines {
+		ignore.addPattern("", IgnoreRule{Pattern: line})
 	}
+	return ignore
+}
 
-	return cfIgnore(patterns)
+// addPatterns adds the patterns in text, read from the ignore file at source
+// in the directory base. They take precedence over the patterns added before.
+func (ignore *cfIgnore) addPatterns(base string, source string, text string) {
+	for i, line := range strings.Split(text, "\n") {
+		ignore.addPattern(base, IgnoreRule{
+			Source:  source,
+			Line:    i + 1,
+			Pattern: strings.TrimRight(line, "\r"),
+		})
+	}
 }
 
-func (ignore cfIgnore) FileShouldBeIgnored(path string) bool {
-	result := false
+func (ignore *cfIgnore) addPattern(base string, rule IgnoreRule) {
+	// leading whitespace has always been left out of .cfignore patterns
+	line := trimTrailingSpaces(strings.TrimLeft(rule.Pattern, " \t"))
+	if line == "" || strings.HasPrefix(line, "#") {
+		return
+	}
+
+	pattern := ignorePattern{IgnoreRule: rule, base: base, exclude: true}
 
-	for _, pattern := range ignore {
-		if strings.HasPrefix(pattern.glob.String(), "/") && !strings.HasPrefix(path, "/") {
-			path = "/" + path
-		}
+	if strings.HasPrefix(line, "!") {
+		line = line[1:]
+		pattern.exclude = false
+	}
 
-		if pattern.glob.Match(path) {
-			result = pattern.exclude
-		}
+	if strings.HasSuffix(line, "/") {
+		line = strings.TrimRight(line, "/")
+		pattern.dirOnly = true
 	}
 
-	return result
+	if strings.HasPrefix(line, "/") {
+		line = strings.TrimLeft(line, "/")
+		pattern.anchored = true
+	} else {
+		pattern.anchored = strings.Contains(line, "/")
+	}
+
+	if line == "" {
+		return
+	}
+
+	var err error
+	pattern.glob, err = glob.CompileIgnoreGlob(line)
+	if err != nil {
+		return
+	}
+
+	ignore.patterns = append(ignore.patterns, pattern)
 }
 
-func globsForPattern(pattern string) (globs []glob.Glob) {
-	globs = append(globs, glob.MustCompileGlob(pattern))
-	globs = append(globs, glob.MustCompileGlob(path.Join(pattern, "*")))
-	globs = append(globs, glob.MustCompileGlob(path.Join(pattern, "**", "*")))
+// trimTrailingSpaces removes the spaces at the end of line, except one that
+// is escaped with a backslash.
+func trimTrailingSpaces(line string) string {
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+		line = line[:len(line)-1]
+	}
+	return line
+}
+
+func (ignore *cfIgnore) FileShouldBeIgnored(filePath string) bool {
+	isDir := strings.HasSuffix(filePath, "/")
+	filePath = strings.Trim(filePath, "/")
 
-	if !strings.HasPrefix(pattern, "/") {
-		globs = append(globs, glob.MustCompileGlob(path.Join("**", pattern)))
-		globs = append(globs, glob.MustCompileGlob(path.Join("**", pattern, "*")))
-		globs = append(globs, glob.MustCompileGlob(path.Join("**", pattern, "**", "*")))
+	components := strings.Split(filePath, "/")
+	for i := 1; i < len(components); i++ {
+		if pattern := ignore.patternFor(strings.Join(components[:i], "/"), true); pattern != nil && pattern.exclude {
+			return true
+		}
 	}
 
-	return
+	pattern := ignore.patternFor(filePath, isDir)
+	return pattern != nil && pattern.exclude
 }
 
-type ignorePattern struct {
-	exclude bool
-	glob    glob.Glob
+// patternFor returns the last pattern that matches the file at filePath, or
+// nil when none does. Parent directories of the file are not looked at.
+func (ignore *cfIgnore) patternFor(filePath string, isDir bool) *ignorePattern {
+	for i := len(ignore.patterns) - 1; i >= 0; i-- {
+		if ignore.patterns[i].matches(filePath, isDir) {
+			return &ignore.patterns[i]
+		}
+	}
+	return nil
 }
 
-type cfIgnore []ignorePattern
+func (pattern ignorePattern) matches(filePath string, isDir bool) bool {
+	if pattern.dirOnly && !isDir {
+		return false
+	}
+
+	if pattern.base != "" {
+		if !strings.HasPrefix(filePath, pattern.base+"/") {
+			return false
+		}
+		filePath = strings.TrimPrefix(filePath, pattern.base+"/")
+	}
+
+	if !pattern.anchored {
+		filePath = path.Base(filePath)
+	}
+
+	return pattern.glob.Match(filePath)
+}
 
 var defaultIgnoreLines = []string{
 	".cfignore",
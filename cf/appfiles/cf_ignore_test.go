@@ -59,6 +59,53 @@ stuff/exclude.c`)
 		Expect(ignore.FileShouldBeIgnored(".git/objects")).To(BeFalse())
 	})
 
+	It("matches patterns without a slash at any depth", func() {
+		ignore := NewCfIgnore(`*.log`)
+		Expect(ignore.FileShouldBeIgnored("app.log")).To(BeTrue())
+		Expect(ignore.FileShouldBeIgnored("logs/2016/app.log")).To(BeTrue())
+	})
+
+	It("anchors patterns with a slash to the root", func() {
+		ignore := NewCfIgnore(`
+/tmp
+docs/*.md`)
+
+		Expect(ignore.FileShouldBeIgnored("tmp")).To(BeTrue())
+		Expect(ignore.FileShouldBeIgnored("lib/tmp")).To(BeFalse())
+		Expect(ignore.FileShouldBeIgnored("docs/README.md")).To(BeTrue())
+		Expect(ignore.FileShouldBeIgnored("vendor/docs/README.md")).To(BeFalse())
+	})
+
+	It("only matches directories with patterns ending in a slash", func() {
+		ignore := NewCfIgnore(`build/`)
+		Expect(ignore.FileShouldBeIgnored("build/")).To(BeTrue())
+		Expect(ignore.FileShouldBeIgnored("build/app.jar")).To(BeTrue())
+		Expect(ignore.FileShouldBeIgnored("src/build/")).To(BeTrue())
+		Expect(ignore.FileShouldBeIgnored("build")).To(BeFalse())
+	})
+
+	It("does not include files again when their parent directory is ignored", func() {
+		ignore := NewCfIgnore(`
+vendor/
+!vendor/keep.rb`)
+
+		Expect(ignore.FileShouldBeIgnored("vendor/keep.rb")).To(BeTrue())
+	})
+
+	It("skips comments and blank lines, and unescapes special characters", func() {
+		ignore := NewCfIgnore(`
+# a comment
+\#notes
+\!important
+trailing-space\ ` + "   ")
+
+		Expect(ignore.FileShouldBeIgnored("# a comment")).To(BeFalse())
+		Expect(ignore.FileShouldBeIgnored("#notes")).To(BeTrue())
+		Expect(ignore.FileShouldBeIgnored("!important")).To(BeTrue())
+		Expect(ignore.FileShouldBeIgnored("important")).To(BeFalse())
+		Expect(ignore.FileShouldBeIgnored("trailing-space ")).To(BeTrue())
+	})
+
 	Describe("files named manifest.yml", func() {
 		var (
 			ignore CfIgnore
@@ -0,0 +1,195 @@
+package appfiles
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf/configuration"
+)
+
+// ResourceCache remembers between pushes the SHA1 of every file of an app,
+// along with the size and modification time the file had when it was hashed,
+// and which of those SHA1s the Cloud Controller already had. It is read from
+// and saved to a single JSON file, and is safe to use from several pushes at
+// once.
+type ResourceCache struct {
+	filePath string
+	loaded   bool
+	apps     map[string]*AppResourceCache
+	mutex    sync.Mutex
+}
+
+// AppResourceCache is the part of a ResourceCache that belongs to one app.
+type AppResourceCache struct {
+	Files   map[string]CachedFile `json:"files"`
+	Matched map[string]bool       `json:"matched"`
+
+	seen  map[string]bool
+	mutex *sync.Mutex
+}
+
+type CachedFile struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	Sha1    string    `json:"sha1"`
+}
+
+func NewResourceCache(filePath string) *ResourceCache {
+	return &ResourceCache{
+		filePath: filePath,
+		apps:     map[string]*AppResourceCache{},
+	}
+}
+
+// ForApp returns the cached files and resource matches of the app with the
+// given GUID, reading the cache file the first time it is called. A missing
+// or unreadable cache file is treated as an empty cache.
+func (cache *ResourceCache) ForApp(appGUID string) *AppResourceCache {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if !cache.loaded {
+		cache.load()
+	}
+
+	appCache, found := cache.apps[appGUID]
+	if !found {
+		appCache = &AppResourceCache{}
+		cache.apps[appGUID] = appCache
+	}
+
+	if appCache.Files == nil {
+		appCache.Files = map[string]CachedFile{}
+	}
+	if appCache.Matched == nil {
+		appCache.Matched = map[string]bool{}
+	}
+	appCache.seen = map[string]bool{}
+	appCache.mutex = &cache.mutex
+
+	return appCache
+}
+
+// Save writes the cache file. Files that were not looked up in an app's
+// cache since ForApp was called for it are left out, as they are no longer
+// part of the app.
+func (cache *ResourceCache) Save() error {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	for _, appCache := range cache.apps {
+		if len(appCache.seen) == 0 {
+			continue
+		}
+
+		present := map[string]bool{}
+		for path, file := range appCache.Files {
+			if !appCache.seen[path] {
+				delete(appCache.Files, path)
+				continue
+			}
+			present[file.Sha1] = true
+		}
+
+		for sha1 := range appCache.Matched {
+			if !present[sha1] {
+				delete(appCache.Matched, sha1)
+			}
+		}
+	}
+
+	data, err := json.Marshal(cache.apps)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(cache.filePath), 0700)
+	if err != nil {
+		return err
+	}
+
+	return configuration.ReplaceFile(cache.filePath, data)
+}
+
+func (cache *ResourceCache) load() {
+	cache.loaded = true
+
+	data, err := ioutil.ReadFile(cache.filePath)
+	if err != nil {
+		return
+	}
+
+	apps := map[string]*AppResourceCache{}
+	if json.Unmarshal(data, &apps) == nil {
+		cache.apps = apps
+	}
+}
+
+// Sha1 returns the SHA1 cached for the file at path, as long as the file has
+// the same size and modification time as when it was hashed.
+func (appCache *AppResourceCache) Sha1(path string, fileInfo os.FileInfo) (string, bool) {
+	appCache.mutex.Lock()
+	defer appCache.mutex.Unlock()
+
+	appCache.seen[path] = true
+
+	file, found := appCache.Files[path]
+	if !found || file.Size != fileInfo.Size() || !file.ModTime.Equal(fileInfo.ModTime()) {
+		return "", false
+	}
+	return file.Sha1, true
+}
+
+func (appCache *AppResourceCache) SetSha1(path string, fileInfo os.FileInfo, sha1 string) {
+	appCache.mutex.Lock()
+	defer appCache.mutex.Unlock()
+
+	appCache.seen[path] = true
+	appCache.Files[path] = CachedFile{
+		Size:    fileInfo.Size(),
+		ModTime: fileInfo.ModTime(),
+		Sha1:    sha1,
+	}
+}
+
+// IsMatched tells whether the Cloud Controller had the resource with the
+// given SHA1 the last time it was asked.
+func (appCache *AppResourceCache) IsMatched(sha1 string) bool {
+	appCache.mutex.Lock()
+	defer appCache.mutex.Unlock()
+
+	return appCache.Matched[sha1]
+}
+
+// SetMatched records the SHA1s the Cloud Controller had. SHA1s it did not
+// have are not recorded, as uploading them usually adds them to its resource
+// cache, so they are asked about again on the next push.
+func (appCache *AppResourceCache) SetMatched(sha1s []string) {
+	appCache.mutex.Lock()
+	defer appCache.mutex.Unlock()
+
+	for _, sha1 := range sha1s {
+		appCache.Matched[sha1] = true
+	}
+}
+
+// HasMatches tells whether any resource matches are recorded.
+func (appCache *AppResourceCache) HasMatches() bool {
+	appCache.mutex.Lock()
+	defer appCache.mutex.Unlock()
+
+	return len(appCache.Matched) > 0
+}
+
+// ForgetMatches drops the recorded resource matches, e.g. after an upload
+// failed because the Cloud Controller no longer had one of them.
+func (appCache *AppResourceCache) ForgetMatches() {
+	appCache.mutex.Lock()
+	defer appCache.mutex.Unlock()
+
+	appCache.Matched = map[string]bool{}
+}
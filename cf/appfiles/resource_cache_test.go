@@ -0,0 +1,130 @@
+package appfiles_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf/appfiles"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ResourceCache", func() {
+	var (
+		tmpDir    string
+		cachePath string
+		appDir    string
+		cache     *appfiles.ResourceCache
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "resource-cache")
+		Expect(err).NotTo(HaveOccurred())
+
+		cachePath = filepath.Join(tmpDir, ".cf", "resource_cache.json")
+		appDir = filepath.Join(tmpDir, "app")
+		Expect(os.MkdirAll(appDir, 0700)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(appDir, "app.rb"), []byte("puts 'hi'"), 0600)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(appDir, "Gemfile"), []byte("source 'https://rubygems.org'"), 0600)).To(Succeed())
+
+		cache = appfiles.NewResourceCache(cachePath)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	fileInfo := func(name string) os.FileInfo {
+		info, err := os.Lstat(filepath.Join(appDir, name))
+		Expect(err).NotTo(HaveOccurred())
+		return info
+	}
+
+	It("returns the SHA1 of a file while its size and modification time are unchanged", func() {
+		appCache := cache.ForApp("app-guid")
+		appCache.SetSha1("app.rb", fileInfo("app.rb"), "app-sha")
+
+		sha1, found := appCache.Sha1("app.rb", fileInfo("app.rb"))
+		Expect(found).To(BeTrue())
+		Expect(sha1).To(Equal("app-sha"))
+
+		later := time.Now().Add(time.Minute)
+		Expect(os.Chtimes(filepath.Join(appDir, "app.rb"), later, later)).To(Succeed())
+
+		_, found = appCache.Sha1("app.rb", fileInfo("app.rb"))
+		Expect(found).To(BeFalse())
+	})
+
+	It("keeps the files and matches of each app separately", func() {
+		cache.ForApp("app-guid").SetMatched([]string{"app-sha"})
+
+		Expect(cache.ForApp("app-guid").IsMatched("app-sha")).To(BeTrue())
+		Expect(cache.ForApp("other-app-guid").IsMatched("app-sha")).To(BeFalse())
+	})
+
+	It("persists the cache between runs", func() {
+		appCache := cache.ForApp("app-guid")
+		appCache.SetSha1("app.rb", fileInfo("app.rb"), "app-sha")
+		appCache.SetMatched([]string{"app-sha"})
+		Expect(cache.Save()).To(Succeed())
+
+		appCache = appfiles.NewResourceCache(cachePath).ForApp("app-guid")
+		sha1, found := appCache.Sha1("app.rb", fileInfo("app.rb"))
+		Expect(found).To(BeTrue())
+		Expect(sha1).To(Equal("app-sha"))
+		Expect(appCache.IsMatched("app-sha")).To(BeTrue())
+	})
+
+	It("drops files that were not looked up before saving", func() {
+		appCache := cache.ForApp("app-guid")
+		appCache.SetSha1("app.rb", fileInfo("app.rb"), "app-sha")
+		appCache.SetSha1("Gemfile", fileInfo("Gemfile"), "gemfile-sha")
+		appCache.SetMatched([]string{"app-sha", "gemfile-sha"})
+		Expect(cache.Save()).To(Succeed())
+
+		cache = appfiles.NewResourceCache(cachePath)
+		appCache = cache.ForApp("app-guid")
+		appCache.Sha1("app.rb", fileInfo("app.rb"))
+		Expect(cache.Save()).To(Succeed())
+
+		appCache = appfiles.NewResourceCache(cachePath).ForApp("app-guid")
+		_, found := appCache.Sha1("Gemfile", fileInfo("Gemfile"))
+		Expect(found).To(BeFalse())
+		Expect(appCache.IsMatched("gemfile-sha")).To(BeFalse())
+		Expect(appCache.IsMatched("app-sha")).To(BeTrue())
+	})
+
+	It("treats an unreadable cache file as an empty cache", func() {
+		Expect(os.MkdirAll(filepath.Dir(cachePath), 0700)).To(Succeed())
+		Expect(ioutil.WriteFile(cachePath, []byte("{not json"), 0600)).To(Succeed())
+
+		appCache := cache.ForApp("app-guid")
+		_, found := appCache.Sha1("app.rb", fileInfo("app.rb"))
+		Expect(found).To(BeFalse())
+	})
+
+	Describe("AppFilesInDir", func() {
+		It("does not hash files again when their SHA1 is cached", func() {
+			appCache := cache.ForApp("app-guid")
+			appCache.SetSha1("app.rb", fileInfo("app.rb"), "cached-sha")
+
+			files, err := appfiles.ApplicationFiles{}.AppFilesInDir(appDir, appCache)
+			Expect(err).NotTo(HaveOccurred())
+
+			sha1s := map[string]string{}
+			for _, file := range files {
+				sha1s[file.Path] = file.Sha1
+			}
+			Expect(sha1s["app.rb"]).To(Equal("cached-sha"))
+			Expect(sha1s["Gemfile"]).To(Equal("480be6c99925724ea2ad584a1cf6a075724bd29c"))
+
+			sha1, found := appCache.Sha1("Gemfile", fileInfo("Gemfile"))
+			Expect(found).To(BeTrue())
+			Expect(sha1).To(Equal(sha1s["Gemfile"]))
+		})
+	})
+})
@@ -17,6 +17,7 @@ import (
 
 type Zipper interface {
 	Zip(dirToZip string, targetFile *os.File) (err error)
+	WriteZip(dirToZip string, writer io.Writer) (err error)
 	IsZipFile(path string) bool
 	Unzip(appDir string, destDir string) (err error)
 	GetZipSize(zipFile *os.File) (int64, error)
@@ -25,22 +26,9 @@ type Zipper interface {
 type ApplicationZipper struct{}
 
 func (zipper ApplicationZipper) Zip(dirOrZipFilePath string, targetFile *os.File) error {
-	if zipper.IsZipFile(dirOrZipFilePath) {
-		zipFile, err := os.Open(dirOrZipFilePath)
-		if err != nil {
-			return err
-		}
-		defer zipFile.Close()
-
-		_, err = io.Copy(targetFile, zipFile)
-		if err != nil {
-			return err
-		}
-	} else {
-		err := writeZipFile(dirOrZipFilePath, targetFile)
-		if err != nil {
-			return err
-		}
+	err := zipper.WriteZip(dirOrZipFilePath, targetFile)
+	if err != nil {
+		return err
 	}
 
 	targetFile.Seek(0, os.SEEK_SET)
@@ -48,6 +36,24 @@ func (zipper ApplicationZipper) Zip(dirOrZipFilePath string, targetFile *os.File
 	return nil
 }
 
+// WriteZip writes the zip of the files in a directory, or the contents of a
+// zip file, to writer. It only writes forwards, so writer can be e.g. the
+// body of a request that is being sent while the zip is written.
+func (zipper ApplicationZipper) WriteZip(dirOrZipFilePath string, writer io.Writer) error {
+	if !zipper.IsZipFile(dirOrZipFilePath) {
+		return writeZipFile(dirOrZipFilePath, writer)
+	}
+
+	zipFile, err := os.Open(dirOrZipFilePath)
+	if err != nil {
+		return err
+	}
+	defer zipFile.Close()
+
+	_, err = io.Copy(writer, zipFile)
+	return err
+}
+
 func (zipper ApplicationZipper) IsZipFile(name string) bool {
 	f, err := os.Open(name)
 	if err != nil {
@@ -133,7 +139,7 @@ func (zipper ApplicationZipper) GetZipSize(zipFile *os.File) (int64, error) {
 	return zipFileSize, nil
 }
 
-func writeZipFile(dir string, targetFile *os.File) error {
+func writeZipFile(dir string, target io.Writer) error {
 	isEmpty, err := fileutils.IsDirEmpty(dir)
 	if err != nil {
 		return err
@@ -143,11 +149,10 @@ func writeZipFile(dir string, targetFile *os.File) error {
 		return errors.NewEmptyDirError(dir)
 	}
 
-	writer := zip.NewWriter(targetFile)
-	defer writer.Close()
+	writer := zip.NewWriter(target)
 
 	appfiles := ApplicationFiles{}
-	return appfiles.WalkAppFiles(dir, func(fileName string, fullPath string) error {
+	err = appfiles.WalkAppFiles(dir, func(fileName string, fullPath string) error {
 		fileInfo, err := os.Stat(fullPath)
 		if err != nil {
 			return err
@@ -190,6 +195,11 @@ func writeZipFile(dir string, targetFile *os.File) error {
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	return writer.Close()
 }
 
 func (zipper ApplicationZipper) zipFileHeaderLocation(name string) (int64, error) {
@@ -43,6 +43,7 @@ type Dependency struct {
 	WordGenerator      generator.WordGenerator
 	AppZipper          appfiles.Zipper
 	AppFiles           appfiles.AppFiles
+	ResourceCache      *appfiles.ResourceCache
 	PushActor          actors.PushActor
 	ChecksumUtil       utils.Sha1Checksum
 	WildcardDependency interface{} //use for injecting fakes
@@ -64,9 +65,15 @@ type PluginModels struct {
 }
 
 func NewDependency(logger trace.Printer) Dependency {
+	return NewDependencyWithOutputFormat(logger, terminal.OutputTable)
+}
+
+// NewDependencyWithOutputFormat is like NewDependency, but its UI prints
+// tables in the given format.
+func NewDependencyWithOutputFormat(logger trace.Printer, outputFormat terminal.OutputFormat) Dependency {
 	deps := Dependency{}
 	deps.TeePrinter = terminal.NewTeePrinter()
-	deps.UI = terminal.NewUI(os.Stdin, deps.TeePrinter, logger)
+	deps.UI = terminal.NewUIWithOutputFormat(os.Stdin, deps.TeePrinter, terminal.NewWriterPrinter(os.Stderr), logger, outputFormat)
 
 	errorHandler := func(err error) {
 		if err != nil {
@@ -74,6 +81,9 @@ func NewDependency(logger trace.Printer) Dependency {
 		}
 	}
 	deps.Config = coreconfig.NewRepositoryFromFilepath(confighelpers.DefaultFilePath(), errorHandler)
+	if profile := os.Getenv("CF_PROFILE"); profile != "" {
+		errorHandler(deps.Config.SelectProfile(profile))
+	}
 
 	deps.ManifestRepo = manifest.NewManifestDiskRepository()
 	deps.AppManifest = manifest.NewGenerator()
@@ -127,6 +137,7 @@ func NewDependency(logger trace.Printer) Dependency {
 
 	deps.AppZipper = appfiles.ApplicationZipper{}
 	deps.AppFiles = appfiles.ApplicationFiles{}
+	deps.ResourceCache = appfiles.NewResourceCache(confighelpers.ResourceCacheFilePath())
 
 	deps.PushActor = actors.NewPushActor(deps.RepoLocator.GetApplicationBitsRepository(), deps.AppZipper, deps.AppFiles)
 
@@ -183,6 +183,7 @@ func (cmd *ShowApp) ShowApp(app models.Application, orgName, spaceName string) {
 	}
 
 	table := cmd.ui.Table([]string{"", T("state"), T("since"), T("cpu"), T("memory"), T("disk"), T("details")})
+	table.SetFieldNames("index", "state", "since", "cpu", "memory", "disk", "details")
 
 	for index, instance := range instances {
 		table.Add(
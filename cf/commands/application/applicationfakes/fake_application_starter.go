@@ -46,6 +46,14 @@ type FakeApplicationStarter struct {
 	setStartTimeoutInSecondsArgsForCall []struct {
 		timeout int
 	}
+	SetWaitForAllInstancesStub        func(wait bool) (previous bool)
+	setWaitForAllInstancesMutex       sync.RWMutex
+	setWaitForAllInstancesArgsForCall []struct {
+		wait bool
+	}
+	setWaitForAllInstancesReturns struct {
+		result1 bool
+	}
 	ApplicationStartStub        func(app models.Application, orgName string, spaceName string) (updatedApp models.Application, err error)
 	applicationStartMutex       sync.RWMutex
 	applicationStartArgsForCall []struct {
@@ -195,6 +203,38 @@ func (fake *FakeApplicationStarter) SetStartTimeoutInSecondsArgsForCall(i int) i
 	return fake.setStartTimeoutInSecondsArgsForCall[i].timeout
 }
 
+func (fake *FakeApplicationStarter) SetWaitForAllInstances(wait bool) (previous bool) {
+	fake.setWaitForAllInstancesMutex.Lock()
+	fake.setWaitForAllInstancesArgsForCall = append(fake.setWaitForAllInstancesArgsForCall, struct {
+		wait bool
+	}{wait})
+	fake.setWaitForAllInstancesMutex.Unlock()
+	if fake.SetWaitForAllInstancesStub != nil {
+		return fake.SetWaitForAllInstancesStub(wait)
+	} else {
+		return fake.setWaitForAllInstancesReturns.result1
+	}
+}
+
+func (fake *FakeApplicationStarter) SetWaitForAllInstancesCallCount() int {
+	fake.setWaitForAllInstancesMutex.RLock()
+	defer fake.setWaitForAllInstancesMutex.RUnlock()
+	return len(fake.setWaitForAllInstancesArgsForCall)
+}
+
+func (fake *FakeApplicationStarter) SetWaitForAllInstancesArgsForCall(i int) bool {
+	fake.setWaitForAllInstancesMutex.RLock()
+	defer fake.setWaitForAllInstancesMutex.RUnlock()
+	return fake.setWaitForAllInstancesArgsForCall[i].wait
+}
+
+func (fake *FakeApplicationStarter) SetWaitForAllInstancesReturns(result1 bool) {
+	fake.SetWaitForAllInstancesStub = nil
+	fake.setWaitForAllInstancesReturns = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *FakeApplicationStarter) ApplicationStart(app models.Application, orgName string, spaceName string) (updatedApp models.Application, err error) {
 	fake.applicationStartMutex.Lock()
 	fake.applicationStartArgsForCall = append(fake.applicationStartArgsForCall, struct {
@@ -85,11 +85,6 @@ func (cmd *ListApps) Execute(c flags.FlagContext) {
 	cmd.ui.Ok()
 	cmd.ui.Say("")
 
-	if len(apps) == 0 {
-		cmd.ui.Say(T("No apps found"))
-		return
-	}
-
 	table := cmd.ui.Table([]string{
 		T("name"),
 		T("requested state"),
@@ -100,6 +95,15 @@ func (cmd *ListApps) Execute(c flags.FlagContext) {
 		// T("app ports"),
 		T("urls"),
 	})
+	table.SetFieldNames("name", "requested_state", "instances", "memory", "disk", "urls")
+
+	if len(apps) == 0 {
+		if table.OutputFormat.IsStructured() {
+			table.Print()
+		}
+		cmd.ui.Say(T("No apps found"))
+		return
+	}
 
 	for _, application := range apps {
 		var urls []string
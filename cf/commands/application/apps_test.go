@@ -5,6 +5,7 @@ import (
 	"github.com/cloudfoundry/cli/cf/commandregistry"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
 	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/terminal"
 	"github.com/cloudfoundry/cli/cf/trace/tracefakes"
 	"github.com/cloudfoundry/cli/plugin/models"
 	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
@@ -192,6 +193,20 @@ var _ = Describe("list-apps command", func() {
 			))
 		})
 
+		It("lists apps as JSON records with the json output format", func() {
+			ui.OutputFormat = terminal.OutputJSON
+
+			runCommand()
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{`"name": "Application-1"`},
+				[]string{`"requested_state": "started"`},
+				[]string{`"instances": "1/1"`},
+				[]string{`"urls": "app1.cfapps.io, app1.example.com"`},
+				[]string{`"name": "Application-2"`},
+			))
+		})
+
 		Context("when an app's running instances is unknown", func() {
 			It("dipslays a '?' for running instances", func() {
 				appRoutes := []models.RouteSummary{
@@ -232,6 +247,14 @@ var _ = Describe("list-apps command", func() {
 					[]string{"No apps found"},
 				))
 			})
+
+			It("prints an empty list with the json output format", func() {
+				appSummaryRepo.GetSummariesInCurrentSpaceApps = []models.Application{}
+				ui.OutputFormat = terminal.OutputJSON
+
+				runCommand()
+				Expect(ui.Outputs).To(ContainElement("[]"))
+			})
 		})
 	})
 })
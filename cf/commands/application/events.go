@@ -65,6 +65,7 @@ func (cmd *Events) Execute(c flags.FlagContext) {
 			"Username":  terminal.EntityNameColor(cmd.config.Username())}))
 
 	table := cmd.ui.Table([]string{T("time"), T("event"), T("actor"), T("description")})
+	table.SetFieldNames("time", "event", "actor", "description")
 
 	events, apiErr := cmd.eventsRepo.RecentEvents(app.GUID, 50)
 	if apiErr != nil {
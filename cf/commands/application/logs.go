@@ -1,8 +1,14 @@
 package application
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/cloudfoundry/cli/cf/api"
+	"github.com/cloudfoundry/cli/cf/api/applications"
 	"github.com/cloudfoundry/cli/cf/api/logs"
 	"github.com/cloudfoundry/cli/cf/commandregistry"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
@@ -15,12 +21,20 @@ import (
 )
 
 type Logs struct {
-	ui       terminal.UI
-	logsRepo logs.LogsRepository
-	config   coreconfig.Reader
-	appReq   requirements.ApplicationRequirement
+	ui             terminal.UI
+	logsRepo       logs.LogsRepository
+	newLogsRepo    func() logs.LogsRepository
+	appRepo        applications.ApplicationRepository
+	appSummaryRepo api.AppSummaryRepository
+	config         coreconfig.Reader
+	appReq         requirements.ApplicationRequirement
+	jsonFormat     bool
 }
 
+// MergeInterval is how long the logs of several apps are collected before
+// they are sorted by their timestamp and printed.
+var MergeInterval = 250 * time.Millisecond
+
 func init() {
 	commandregistry.Register(&Logs{})
 }
@@ -28,28 +42,61 @@ func init() {
 func (cmd *Logs) MetaData() commandregistry.CommandMetadata {
 	fs := make(map[string]flags.FlagSet)
 	fs["recent"] = &flags.BoolFlag{Name: "recent", Usage: T("Dump recent logs instead of tailing")}
+	fs["space"] = &flags.BoolFlag{Name: "space", Usage: T("Show the logs of every app in the targeted space")}
+	fs["since"] = &flags.StringFlag{Name: "since", Usage: T("Only dump the recent logs of the last DURATION, e.g. 30s, 15m or 2h (requires --recent)")}
+	fs["source"] = &flags.StringFlag{Name: "source", Usage: T("Only show logs from these source types, separated by commas, e.g. APP,RTR (APP, RTR, STG, CELL, API)")}
+	fs["instance"] = &flags.StringFlag{Name: "instance", Usage: T("Only show logs from these instance indexes, separated by commas")}
+	fs["stream"] = &flags.StringFlag{Name: "stream", Usage: T("Only show logs written to this stream (OUT or ERR)")}
+	fs["grep"] = &flags.StringFlag{Name: "grep", Usage: T("Only show logs whose message matches the regular expression REGEX")}
+	fs["format"] = &flags.StringFlag{Name: "format", Usage: T("Print each log message as text (default) or as a line of json")}
 
 	return commandregistry.CommandMetadata{
 		Name:        "logs",
 		Description: T("Tail or show recent logs for an app"),
 		Usage: []string{
-			T("CF_NAME logs APP_NAME"),
+			T("CF_NAME logs APP_NAME [APP_NAME...] [--recent [--since DURATION]] [--source TYPES] [--instance INDEXES] [--stream OUT|ERR] [--grep REGEX] [--format text|json]\n"),
+			T("   CF_NAME logs --space [--recent [--since DURATION]] [--source TYPES] [--instance INDEXES] [--stream OUT|ERR] [--grep REGEX] [--format text|json]"),
+		},
+		Examples: []string{
+			"CF_NAME logs my-app --recent --since 1h --source APP,RTR",
+			"CF_NAME logs my-app --instance 0 --stream ERR --grep \"timeout|refused\"",
+			"CF_NAME logs frontend orders payments",
+			"CF_NAME logs my-app --recent --format json | jq .message",
 		},
 		Flags: fs,
 	}
 }
 
 func (cmd *Logs) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
-	if len(fc.Args()) != 1 {
+	if len(fc.Args()) == 0 && !fc.Bool("space") {
 		cmd.ui.Failed(T("Incorrect Usage. Requires an argument\n\n") + commandregistry.Commands.CommandUsage("logs"))
 	}
 
-	cmd.appReq = requirementsFactory.NewApplicationRequirement(fc.Args()[0])
+	if len(fc.Args()) > 0 && fc.Bool("space") {
+		cmd.ui.Failed(T("Incorrect Usage: --space cannot be used with app names\n\n") + commandregistry.Commands.CommandUsage("logs"))
+	}
+
+	if fc.IsSet("since") && !fc.Bool("recent") {
+		cmd.ui.Failed(T("Incorrect Usage: --since can only be used with --recent\n\n") + commandregistry.Commands.CommandUsage("logs"))
+	}
+
+	if stream := fc.String("stream"); stream != "" && !strings.EqualFold(stream, "OUT") && !strings.EqualFold(stream, "ERR") {
+		cmd.ui.Failed(T("Incorrect Usage: --stream must be OUT or ERR\n\n") + commandregistry.Commands.CommandUsage("logs"))
+	}
+
+	if format := fc.String("format"); format != "" && format != "text" && format != "json" {
+		cmd.ui.Failed(T("Incorrect Usage: --format must be text or json\n\n") + commandregistry.Commands.CommandUsage("logs"))
+	}
 
 	reqs := []requirements.Requirement{
 		requirementsFactory.NewLoginRequirement(),
 		requirementsFactory.NewTargetedSpaceRequirement(),
-		cmd.appReq,
+	}
+
+	cmd.appReq = nil
+	if len(fc.Args()) == 1 {
+		cmd.appReq = requirementsFactory.NewApplicationRequirement(fc.Args()[0])
+		reqs = append(reqs, cmd.appReq)
 	}
 
 	return reqs
@@ -59,21 +106,74 @@ func (cmd *Logs) SetDependency(deps commandregistry.Dependency, pluginCall bool)
 	cmd.ui = deps.UI
 	cmd.config = deps.Config
 	cmd.logsRepo = deps.RepoLocator.GetLogsRepository()
+	cmd.newLogsRepo = deps.RepoLocator.NewLogsRepository
+	cmd.appRepo = deps.RepoLocator.GetApplicationRepository()
+	cmd.appSummaryRepo = deps.RepoLocator.GetAppSummaryRepository()
 	return cmd
 }
 
 func (cmd *Logs) Execute(c flags.FlagContext) {
+	filter := cmd.logFilter(c)
+	cmd.jsonFormat = c.String("format") == "json"
+
+	if cmd.appReq == nil {
+		apps := cmd.appsToShow(c)
+		if c.Bool("recent") {
+			cmd.recentLogsForApps(apps, filter)
+		} else {
+			cmd.tailLogsForApps(apps, filter)
+		}
+		return
+	}
+
 	app := cmd.appReq.GetApplication()
 
 	if c.Bool("recent") {
-		cmd.recentLogsFor(app)
+		cmd.recentLogsFor(app, filter)
 	} else {
-		cmd.tailLogsFor(app)
+		cmd.tailLogsFor(app, filter)
 	}
 }
 
-func (cmd *Logs) recentLogsFor(app models.Application) {
-	cmd.ui.Say(T("Connected, dumping recent logs for app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...\n",
+func (cmd *Logs) logFilter(c flags.FlagContext) logs.LogFilter {
+	filter := logs.LogFilter{
+		SourceTypes:     splitList(c.String("source")),
+		SourceInstances: splitList(c.String("instance")),
+		MessageType:     strings.ToUpper(c.String("stream")),
+	}
+
+	if c.String("grep") != "" {
+		pattern, err := regexp.Compile(c.String("grep"))
+		if err != nil {
+			cmd.ui.Failed(T("Invalid regular expression for --grep: {{.Err}}", map[string]interface{}{"Err": err.Error()}))
+		}
+		filter.Pattern = pattern
+	}
+
+	if c.IsSet("since") {
+		since, err := time.ParseDuration(c.String("since"))
+		if err != nil || since <= 0 {
+			cmd.ui.Failed(T("Invalid duration for --since: {{.Duration}}. Use a duration like 30s, 15m or 2h", map[string]interface{}{"Duration": c.String("since")}))
+		}
+		filter.Since = time.Now().Add(-since)
+	}
+
+	return filter
+}
+
+// splitList splits a comma-separated flag value, leaving out empty items.
+func splitList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (cmd *Logs) recentLogsFor(app models.Application, filter logs.LogFilter) {
+	cmd.sayStatus(T("Connected, dumping recent logs for app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...\n",
 		map[string]interface{}{
 			"AppName":   terminal.EntityNameColor(app.Name),
 			"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
@@ -86,13 +186,15 @@ func (cmd *Logs) recentLogsFor(app models.Application) {
 	}
 
 	for _, msg := range messages {
-		cmd.ui.Say("%s", msg.ToLog(time.Local))
+		if filter.Matches(msg) {
+			cmd.printLog(msg, "")
+		}
 	}
 }
 
-func (cmd *Logs) tailLogsFor(app models.Application) {
+func (cmd *Logs) tailLogsFor(app models.Application, filter logs.LogFilter) {
 	onConnect := func() {
-		cmd.ui.Say(T("Connected, tailing logs for app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...\n",
+		cmd.sayStatus(T("Connected, tailing logs for app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...\n",
 			map[string]interface{}{
 				"AppName":   terminal.EntityNameColor(app.Name),
 				"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
@@ -111,13 +213,232 @@ func (cmd *Logs) tailLogsFor(app models.Application) {
 			if !ok {
 				return
 			}
-			cmd.ui.Say("%s", msg.ToLog(time.Local))
+			if filter.Matches(msg) {
+				cmd.printLog(msg, "")
+			}
 		case err := <-e:
 			cmd.handleError(err)
 		}
 	}
 }
 
+// appsToShow returns the apps named on the command line, or all the apps of
+// the targeted space with --space.
+func (cmd *Logs) appsToShow(c flags.FlagContext) []models.Application {
+	if c.Bool("space") {
+		apps, err := cmd.appSummaryRepo.GetSummariesInCurrentSpace()
+		if err != nil {
+			cmd.ui.Failed(err.Error())
+		}
+		if len(apps) == 0 {
+			cmd.ui.Failed(T("No apps found in space {{.SpaceName}}", map[string]interface{}{"SpaceName": cmd.config.SpaceFields().Name}))
+		}
+		return apps
+	}
+
+	apps := []models.Application{}
+	for _, appName := range c.Args() {
+		app, err := cmd.appRepo.Read(appName)
+		if err != nil {
+			cmd.ui.Failed(err.Error())
+		}
+		apps = append(apps, app)
+	}
+	return apps
+}
+
+// appLogMessage is a log message of one of several apps whose logs are
+// shown together.
+type appLogMessage struct {
+	logs.Loggable
+	appName string
+}
+
+func (cmd *Logs) recentLogsForApps(apps []models.Application, filter logs.LogFilter) {
+	cmd.sayStatus(T("Connected, dumping recent logs for apps {{.AppNames}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...\n",
+		map[string]interface{}{
+			"AppNames":  terminal.EntityNameColor(strings.Join(appNames(apps), ", ")),
+			"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+			"SpaceName": terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+			"Username":  terminal.EntityNameColor(cmd.config.Username())}))
+
+	queue := logs.NewLogMessageQueue()
+	failures := 0
+	for _, app := range apps {
+		messages, err := cmd.logsRepo.RecentLogsFor(app.GUID)
+		if err != nil {
+			cmd.warnAboutStream(app, err)
+			failures++
+			continue
+		}
+
+		for _, msg := range messages {
+			if filter.Matches(msg) {
+				queue.PushMessage(appLogMessage{Loggable: msg, appName: app.Name})
+			}
+		}
+	}
+
+	cmd.printAppLogs(queue, appPrefixes(apps))
+
+	if failures == len(apps) {
+		cmd.ui.Failed(T("Could not get the logs of any app"))
+	}
+}
+
+// tailLogsForApps follows the logs of each app over a connection of its
+// own, printing their messages in the order of their timestamps. When the
+// logs of an app cannot be followed, the others still are.
+func (cmd *Logs) tailLogsForApps(apps []models.Application, filter logs.LogFilter) {
+	queue := logs.NewLogMessageQueue()
+
+	failuresLock := &sync.Mutex{}
+	failures := 0
+
+	wg := &sync.WaitGroup{}
+	for _, app := range apps {
+		wg.Add(1)
+		go func(app models.Application) {
+			defer wg.Done()
+
+			err := cmd.followAppLogs(app, filter, queue)
+			if err != nil {
+				cmd.warnAboutStream(app, err)
+				failuresLock.Lock()
+				failures++
+				failuresLock.Unlock()
+			}
+		}(app)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	prefixes := appPrefixes(apps)
+	ticker := time.NewTicker(MergeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			cmd.printAppLogs(queue, prefixes)
+		case <-done:
+			cmd.printAppLogs(queue, prefixes)
+			if failures == len(apps) {
+				cmd.ui.Failed(T("Could not get the logs of any app"))
+			}
+			return
+		}
+	}
+}
+
+// followAppLogs pushes the log messages of app that match filter to queue
+// until its log stream ends or fails.
+func (cmd *Logs) followAppLogs(app models.Application, filter logs.LogFilter, queue *logs.LogMessageQueue) error {
+	onConnect := func() {
+		cmd.sayStatus(T("Connected, tailing logs for app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...",
+			map[string]interface{}{
+				"AppName":   terminal.EntityNameColor(app.Name),
+				"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+				"SpaceName": terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+				"Username":  terminal.EntityNameColor(cmd.config.Username())}))
+	}
+
+	c := make(chan logs.Loggable)
+	e := make(chan error)
+
+	repo := cmd.newLogsRepo()
+	defer repo.Close()
+
+	go repo.TailLogsFor(app.GUID, onConnect, c, e)
+
+	for {
+		select {
+		case msg, ok := <-c:
+			if !ok {
+				return nil
+			}
+			if filter.Matches(msg) {
+				queue.PushMessage(appLogMessage{Loggable: msg, appName: app.Name})
+			}
+		case err, ok := <-e:
+			if !ok {
+				e = nil
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (cmd *Logs) printAppLogs(queue *logs.LogMessageQueue, prefixes map[string]string) {
+	queue.EnumerateAndClear(func(msg logs.Loggable) {
+		cmd.printLog(msg, prefixes[msg.(appLogMessage).appName])
+	})
+}
+
+// printLog prints msg as text with prefix in front of each line, or as a
+// line of JSON.
+func (cmd *Logs) printLog(msg logs.Loggable, prefix string) {
+	if cmd.jsonFormat {
+		line, err := logs.ToJSON(msg)
+		if err != nil {
+			cmd.ui.Failed(err.Error())
+		}
+		cmd.ui.Say("%s", line)
+		return
+	}
+
+	cmd.ui.Say("%s", prefix+strings.Replace(msg.ToLog(time.Local), "\n", "\n"+prefix, -1))
+}
+
+// sayStatus prints a message about the log stream, unless the messages are
+// printed as JSON, where it would get in the way of reading them.
+func (cmd *Logs) sayStatus(message string) {
+	if !cmd.jsonFormat {
+		cmd.ui.Say(message)
+	}
+}
+
+func (cmd *Logs) warnAboutStream(app models.Application, err error) {
+	message := err.Error()
+	if _, ok := err.(*errors.InvalidSSLCert); ok {
+		message += T("\nTIP: use 'cf login -a API --skip-ssl-validation' or 'cf api API --skip-ssl-validation' to suppress this error")
+	}
+	cmd.ui.Warn(T("Could not get the logs of app {{.AppName}}: {{.Err}}",
+		map[string]interface{}{"AppName": app.Name, "Err": message}))
+}
+
+func appNames(apps []models.Application) []string {
+	names := make([]string, len(apps))
+	for i, app := range apps {
+		names[i] = app.Name
+	}
+	return names
+}
+
+// appPrefixes returns the colored prefix shown in front of each app's log
+// lines, padded so that the lines of all apps line up.
+func appPrefixes(apps []models.Application) map[string]string {
+	width := 0
+	for _, app := range apps {
+		if len(app.Name) > width {
+			width = len(app.Name)
+		}
+	}
+
+	prefixes := map[string]string{}
+	for _, app := range apps {
+		prefixes[app.Name] = terminal.LogPrefixColor(app.Name, fmt.Sprintf("%-*s | ", width, app.Name))
+	}
+	return prefixes
+}
+
 func (cmd *Logs) handleError(err error) {
 	switch err.(type) {
 	case nil:
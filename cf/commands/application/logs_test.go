@@ -1,12 +1,15 @@
 package application_test
 
 import (
+	"errors"
 	"time"
 
+	"github.com/cloudfoundry/cli/cf/api/apifakes"
+	"github.com/cloudfoundry/cli/cf/api/applications/applicationsfakes"
 	"github.com/cloudfoundry/cli/cf/api/logs"
 	"github.com/cloudfoundry/cli/cf/api/logs/logsfakes"
 	"github.com/cloudfoundry/cli/cf/commandregistry"
-	"github.com/cloudfoundry/cli/cf/errors"
+	cferrors "github.com/cloudfoundry/cli/cf/errors"
 	"github.com/cloudfoundry/cli/cf/models"
 	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
 	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
@@ -25,6 +28,8 @@ var _ = Describe("logs command", func() {
 	var (
 		ui                  *testterm.FakeUI
 		logsRepo            *logsfakes.FakeLogsRepository
+		appRepo             *applicationsfakes.FakeApplicationRepository
+		appSummaryRepo      *apifakes.FakeAppSummaryRepository
 		requirementsFactory *testreq.FakeReqFactory
 		configRepo          coreconfig.Repository
 		deps                commandregistry.Dependency
@@ -33,6 +38,8 @@ var _ = Describe("logs command", func() {
 	updateCommandDependency := func(pluginCall bool) {
 		deps.UI = ui
 		deps.RepoLocator = deps.RepoLocator.SetLogsRepository(logsRepo)
+		deps.RepoLocator = deps.RepoLocator.SetApplicationRepository(appRepo)
+		deps.RepoLocator = deps.RepoLocator.SetAppSummaryRepository(appSummaryRepo)
 		deps.Config = configRepo
 		commandregistry.Commands.SetCommand(commandregistry.Commands.FindCommand("logs").SetDependency(deps, pluginCall))
 	}
@@ -41,6 +48,8 @@ var _ = Describe("logs command", func() {
 		ui = &testterm.FakeUI{}
 		configRepo = testconfig.NewRepositoryWithDefaults()
 		logsRepo = new(logsfakes.FakeLogsRepository)
+		appRepo = new(applicationsfakes.FakeApplicationRepository)
+		appSummaryRepo = new(apifakes.FakeAppSummaryRepository)
 		requirementsFactory = &testreq.FakeReqFactory{}
 	})
 
@@ -118,6 +127,249 @@ var _ = Describe("logs command", func() {
 			))
 		})
 
+		Context("when filtering logs", func() {
+			BeforeEach(func() {
+				now := time.Now()
+				logsRepo.RecentLogsForReturns([]logs.Loggable{
+					testlogs.NewLogMessage("old app line", app.GUID, "APP", "0", logmessage.LogMessage_OUT, now.Add(-2*time.Hour)),
+					testlogs.NewLogMessage("app line from instance 0", app.GUID, "APP", "0", logmessage.LogMessage_OUT, now),
+					testlogs.NewLogMessage("app error from instance 1", app.GUID, "APP", "1", logmessage.LogMessage_ERR, now),
+					testlogs.NewLogMessage("GET /health 503", app.GUID, "RTR", "0", logmessage.LogMessage_OUT, now),
+				}, nil)
+			})
+
+			It("only shows logs of the given source types", func() {
+				runCommand("--recent", "--source", "rtr", "my-app")
+
+				Expect(ui.Outputs).To(ContainSubstrings([]string{"GET /health 503"}))
+				Expect(ui.Outputs).ToNot(ContainSubstrings([]string{"app line"}))
+			})
+
+			It("only shows logs of the given instances and stream", func() {
+				runCommand("--recent", "--instance", "1", "--stream", "err", "my-app")
+
+				Expect(ui.Outputs).To(ContainSubstrings([]string{"app error from instance 1"}))
+				Expect(ui.Outputs).ToNot(ContainSubstrings([]string{"instance 0"}))
+				Expect(ui.Outputs).ToNot(ContainSubstrings([]string{"GET /health"}))
+			})
+
+			It("only shows logs whose message matches --grep", func() {
+				runCommand("--recent", "--grep", "^app .* instance", "my-app")
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"app line from instance 0"},
+					[]string{"app error from instance 1"},
+				))
+				Expect(ui.Outputs).ToNot(ContainSubstrings([]string{"old app line"}))
+				Expect(ui.Outputs).ToNot(ContainSubstrings([]string{"GET /health"}))
+			})
+
+			It("only shows the recent logs of the duration given with --since", func() {
+				runCommand("--recent", "--since", "1h", "my-app")
+
+				Expect(ui.Outputs).To(ContainSubstrings([]string{"app line from instance 0"}))
+				Expect(ui.Outputs).ToNot(ContainSubstrings([]string{"old app line"}))
+			})
+
+			It("filters tailed logs", func() {
+				logsRepo.TailLogsForStub = func(appGUID string, onConnect func(), logChan chan<- logs.Loggable, errChan chan<- error) {
+					onConnect()
+					go func() {
+						logChan <- testlogs.NewLogMessage("Log Line 1", app.GUID, "APP", "0", logmessage.LogMessage_OUT, time.Now())
+						logChan <- testlogs.NewLogMessage("Log Line 2", app.GUID, "STG", "0", logmessage.LogMessage_OUT, time.Now())
+						close(logChan)
+					}()
+				}
+
+				runCommand("--source", "STG", "my-app")
+
+				Expect(ui.Outputs).To(ContainSubstrings([]string{"Log Line 2"}))
+				Expect(ui.Outputs).ToNot(ContainSubstrings([]string{"Log Line 1"}))
+			})
+
+			It("fails when --since is given without --recent", func() {
+				Expect(runCommand("--since", "1h", "my-app")).To(BeFalse())
+				Expect(ui.Outputs).To(ContainSubstrings([]string{"Incorrect Usage", "--since can only be used with --recent"}))
+			})
+
+			It("fails with an invalid duration", func() {
+				runCommand("--recent", "--since", "yesterday", "my-app")
+				Expect(ui.Outputs).To(ContainSubstrings([]string{"FAILED"}, []string{"Invalid duration for --since: yesterday"}))
+			})
+
+			It("fails with an invalid stream", func() {
+				Expect(runCommand("--stream", "LOG", "my-app")).To(BeFalse())
+				Expect(ui.Outputs).To(ContainSubstrings([]string{"Incorrect Usage", "--stream must be OUT or ERR"}))
+			})
+
+			It("fails with an invalid regular expression", func() {
+				runCommand("--recent", "--grep", "(", "my-app")
+				Expect(ui.Outputs).To(ContainSubstrings([]string{"FAILED"}, []string{"Invalid regular expression for --grep"}))
+			})
+		})
+
+		Context("with --format json", func() {
+			It("prints each recent log message as a line of JSON", func() {
+				runCommand("--recent", "--format", "json", "my-app")
+
+				Expect(ui.Outputs).ToNot(ContainSubstrings([]string{"Connected"}))
+				Expect(ui.Outputs).To(HaveLen(2))
+				Expect(ui.Outputs[0]).To(MatchRegexp(`^\{"timestamp":"[^"]+","app_guid":"my-app-guid","source_type":"DEA","source_instance":"1","message_type":"ERR","message":"Log Line 1"\}$`))
+				Expect(ui.Outputs[1]).To(ContainSubstring(`"message":"Log Line 2"`))
+			})
+
+			It("prints each tailed log message as a line of JSON", func() {
+				runCommand("--format", "json", "my-app")
+
+				Expect(ui.Outputs).To(HaveLen(1))
+				Expect(ui.Outputs[0]).To(ContainSubstring(`"message":"Log Line 1"`))
+			})
+
+			It("fails with usage for an unknown format", func() {
+				Expect(runCommand("--format", "xml", "my-app")).To(BeFalse())
+				Expect(ui.Outputs).To(ContainSubstrings([]string{"Incorrect Usage", "--format must be text or json"}))
+			})
+		})
+
+		Context("when showing the logs of several apps", func() {
+			var now time.Time
+
+			BeforeEach(func() {
+				now = time.Now()
+
+				appRepo.ReadStub = func(name string) (models.Application, error) {
+					if name == "missing-app" {
+						return models.Application{}, cferrors.NewModelNotFoundError("App", name)
+					}
+					app := models.Application{}
+					app.Name = name
+					app.GUID = name + "-guid"
+					return app, nil
+				}
+
+				appLogs := map[string][]logs.Loggable{
+					"frontend-guid": {
+						testlogs.NewLogMessage("frontend line 1", "frontend-guid", "APP", "0", logmessage.LogMessage_OUT, now),
+						testlogs.NewLogMessage("frontend line 2", "frontend-guid", "APP", "0", logmessage.LogMessage_OUT, now.Add(2*time.Second)),
+					},
+					"orders-guid": {
+						testlogs.NewLogMessage("orders line 1", "orders-guid", "APP", "0", logmessage.LogMessage_OUT, now.Add(time.Second)),
+					},
+				}
+
+				logsRepo.RecentLogsForStub = func(appGUID string) ([]logs.Loggable, error) {
+					if appGUID == "broken-guid" {
+						return nil, errors.New("recent logs failed")
+					}
+					return appLogs[appGUID], nil
+				}
+				logsRepo.TailLogsForStub = func(appGUID string, onConnect func(), logChan chan<- logs.Loggable, errChan chan<- error) {
+					if appGUID == "broken-guid" {
+						errChan <- errors.New("websocket closed")
+						return
+					}
+					onConnect()
+					go func() {
+						for _, log := range appLogs[appGUID] {
+							logChan <- log
+						}
+						close(logChan)
+					}()
+				}
+			})
+
+			It("merges the tailed logs of the apps in the order of their timestamps", func() {
+				runCommand("frontend", "orders")
+
+				Expect(logsRepo.TailLogsForCallCount()).To(Equal(2))
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"frontend | ", "frontend line 1"},
+					[]string{"orders   | ", "orders line 1"},
+					[]string{"frontend | ", "frontend line 2"},
+				))
+			})
+
+			It("closes the logs repository of each app once its logs end", func() {
+				runCommand("frontend", "broken", "orders")
+
+				Expect(logsRepo.CloseCallCount()).To(Equal(3))
+			})
+
+			It("keeps following the other apps when the logs of one cannot be tailed", func() {
+				runCommand("frontend", "broken", "orders")
+
+				Expect(ui.WarnOutputs).To(ContainSubstrings([]string{"Could not get the logs of app broken", "websocket closed"}))
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"frontend line 1"},
+					[]string{"orders line 1"},
+					[]string{"frontend line 2"},
+				))
+			})
+
+			It("fails when the logs of no app can be tailed", func() {
+				runCommand("broken", "broken")
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"FAILED"},
+					[]string{"Could not get the logs of any app"},
+				))
+			})
+
+			It("merges the recent logs of the apps in the order of their timestamps", func() {
+				runCommand("--recent", "frontend", "orders", "broken")
+
+				Expect(ui.WarnOutputs).To(ContainSubstrings([]string{"Could not get the logs of app broken", "recent logs failed"}))
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"Connected, dumping recent logs for apps", "frontend, orders, broken"},
+					[]string{"frontend | ", "frontend line 1"},
+					[]string{"orders   | ", "orders line 1"},
+					[]string{"frontend | ", "frontend line 2"},
+				))
+			})
+
+			It("fails when one of the apps does not exist", func() {
+				runCommand("frontend", "missing-app")
+
+				Expect(logsRepo.TailLogsForCallCount()).To(BeZero())
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"FAILED"},
+					[]string{"missing-app", "not found"},
+				))
+			})
+
+			It("shows the logs of every app in the space with --space", func() {
+				frontend := models.Application{}
+				frontend.Name = "frontend"
+				frontend.GUID = "frontend-guid"
+				orders := models.Application{}
+				orders.Name = "orders"
+				orders.GUID = "orders-guid"
+				appSummaryRepo.GetSummariesInCurrentSpaceReturns([]models.Application{frontend, orders}, nil)
+
+				runCommand("--space", "--recent")
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"frontend line 1"},
+					[]string{"orders line 1"},
+					[]string{"frontend line 2"},
+				))
+			})
+
+			It("prints the logs of all apps as JSON lines with --format json", func() {
+				runCommand("--format", "json", "frontend", "orders")
+
+				Expect(ui.Outputs).To(HaveLen(3))
+				Expect(ui.Outputs[0]).To(ContainSubstring(`"app_guid":"frontend-guid"`))
+				Expect(ui.Outputs[1]).To(ContainSubstring(`"app_guid":"orders-guid"`))
+				Expect(ui.Outputs[2]).To(ContainSubstring(`"message":"frontend line 2"`))
+			})
+
+			It("fails with usage when --space is given with app names", func() {
+				Expect(runCommand("--space", "frontend")).To(BeFalse())
+				Expect(ui.Outputs).To(ContainSubstrings([]string{"Incorrect Usage", "--space cannot be used with app names"}))
+			})
+		})
+
 		Context("when the log messages contain format string identifiers", func() {
 			BeforeEach(func() {
 				logsRepo.RecentLogsForReturns([]logs.Loggable{
@@ -147,7 +399,7 @@ var _ = Describe("logs command", func() {
 			Context("when the skip-ssl-validation flag is not set", func() {
 				It("fails and informs the user about the skip-ssl-validation flag", func() {
 					logsRepo.TailLogsForStub = func(appGuid string, onConnect func(), logChan chan<- logs.Loggable, errChan chan<- error) {
-						errChan <- errors.NewInvalidSSLCert("https://example.com", "it don't work good")
+						errChan <- cferrors.NewInvalidSSLCert("https://example.com", "it don't work good")
 					}
 					runCommand("my-app")
 
@@ -158,7 +410,7 @@ var _ = Describe("logs command", func() {
 				})
 
 				It("informs the user of the error when they include the --recent flag", func() {
-					logsRepo.RecentLogsForReturns(nil, errors.NewInvalidSSLCert("https://example.com", "how does SSL work???"))
+					logsRepo.RecentLogsForReturns(nil, cferrors.NewInvalidSSLCert("https://example.com", "how does SSL work???"))
 					runCommand("--recent", "my-app")
 
 					Expect(ui.Outputs).To(ContainSubstrings(
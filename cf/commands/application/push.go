@@ -5,8 +5,10 @@ import (
 	"io/ioutil"
 	"os"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	. "github.com/cloudfoundry/cli/cf/i18n"
 	"github.com/cloudfoundry/cli/flags"
@@ -16,6 +18,7 @@ import (
 	"github.com/cloudfoundry/cli/cf/api"
 	"github.com/cloudfoundry/cli/cf/api/applications"
 	"github.com/cloudfoundry/cli/cf/api/authentication"
+	"github.com/cloudfoundry/cli/cf/api/resources"
 	"github.com/cloudfoundry/cli/cf/api/stacks"
 	"github.com/cloudfoundry/cli/cf/appfiles"
 	"github.com/cloudfoundry/cli/cf/commandregistry"
@@ -31,22 +34,25 @@ import (
 )
 
 type Push struct {
-	ui            terminal.UI
-	config        coreconfig.Reader
-	manifestRepo  manifest.ManifestRepository
-	appStarter    ApplicationStarter
-	appStopper    ApplicationStopper
-	serviceBinder service.ServiceBinder
-	appRepo       applications.ApplicationRepository
-	domainRepo    api.DomainRepository
-	routeRepo     api.RouteRepository
-	serviceRepo   api.ServiceRepository
-	stackRepo     stacks.StackRepository
-	authRepo      authentication.AuthenticationRepository
-	wordGenerator generator.WordGenerator
-	actor         actors.PushActor
-	zipper        appfiles.Zipper
-	appfiles      appfiles.AppFiles
+	ui             terminal.UI
+	config         coreconfig.Reader
+	manifestRepo   manifest.ManifestRepository
+	appStarter     ApplicationStarter
+	appStopper     ApplicationStopper
+	serviceBinder  service.ServiceBinder
+	appRepo        applications.ApplicationRepository
+	appSummaryRepo api.AppSummaryRepository
+	domainRepo     api.DomainRepository
+	routeRepo      api.RouteRepository
+	serviceRepo    api.ServiceRepository
+	stackRepo      stacks.StackRepository
+	authRepo       authentication.AuthenticationRepository
+	wordGenerator  generator.WordGenerator
+	actor          actors.PushActor
+	appfiles       appfiles.AppFiles
+	resourceCache  *appfiles.ResourceCache
+
+	deps commandregistry.Dependency
 }
 
 func init() {
@@ -58,7 +64,7 @@ func (cmd *Push) MetaData() commandregistry.CommandMetadata {
 	fs["b"] = &flags.StringFlag{ShortName: "b", Usage: T("Custom buildpack by name (e.g. my-buildpack) or Git URL (e.g. 'https://github.com/cloudfoundry/java-buildpack.git') or Git URL with a branch or tag (e.g. 'https://github.com/cloudfoundry/java-buildpack.git#v3.3.0' for 'v3.3.0' tag). To use built-in buildpacks only, specify 'default' or 'null'")}
 	fs["c"] = &flags.StringFlag{ShortName: "c", Usage: T("Startup command, set to null to reset to default start command")}
 	fs["d"] = &flags.StringFlag{ShortName: "d", Usage: T("Domain (e.g. example.com)")}
-	fs["f"] = &flags.StringFlag{ShortName: "f", Usage: T("Path to manifest")}
+	fs["f"] = &flags.StringSliceFlag{ShortName: "f", Usage: T("Path to manifest. This flag can be defined more than once; later manifests override earlier ones.")}
 	fs["i"] = &flags.IntFlag{ShortName: "i", Usage: T("Number of instances")}
 	fs["k"] = &flags.StringFlag{ShortName: "k", Usage: T("Disk limit (e.g. 256M, 1024M, 1G)")}
 	fs["m"] = &flags.StringFlag{ShortName: "m", Usage: T("Memory limit (e.g. 256M, 1024M, 1G)")}
@@ -66,14 +72,23 @@ func (cmd *Push) MetaData() commandregistry.CommandMetadata {
 	fs["p"] = &flags.StringFlag{ShortName: "p", Usage: T("Path to app directory or to a zip file of the contents of the app directory")}
 	fs["s"] = &flags.StringFlag{ShortName: "s", Usage: T("Stack to use (a stack is a pre-built file system, including an operating system, that can run apps)")}
 	fs["t"] = &flags.StringFlag{ShortName: "t", Usage: T("Maximum time (in seconds) for CLI to wait for application start, other server side timeouts may apply")}
+	fs["dry-run"] = &flags.BoolFlag{Name: "dry-run", Usage: T("Show the changes that would be made to the app without making them")}
 	fs["docker-image"] = &flags.StringFlag{Name: "docker-image", ShortName: "o", Usage: T("Docker-image to be used (e.g. user/docker-image-name)")}
 	fs["health-check-type"] = &flags.StringFlag{Name: "health-check-type", ShortName: "u", Usage: T("Application health check type (e.g. 'port' or 'none')")}
 	fs["no-hostname"] = &flags.BoolFlag{Name: "no-hostname", Usage: T("Map the root domain to this app")}
+	fs["no-cache"] = &flags.BoolFlag{Name: "no-cache", Usage: T("Hash every app file and ask the server about all of them, instead of using the local cache of file SHA1s and of the files the server already has")}
 	fs["no-manifest"] = &flags.BoolFlag{Name: "no-manifest", Usage: T("Ignore manifest file")}
 	fs["no-route"] = &flags.BoolFlag{Name: "no-route", Usage: T("Do not map a route to this app and remove routes from previous pushes of this app")}
 	fs["no-start"] = &flags.BoolFlag{Name: "no-start", Usage: T("Do not start an app after pushing")}
+	fs["parallel"] = &flags.IntFlag{Name: "parallel", Usage: T("Number of apps from the manifest to push at the same time (Default: 1)")}
 	fs["random-route"] = &flags.BoolFlag{Name: "random-route", Usage: T("Create a random route for this app")}
+	fs["show-ignored"] = &flags.BoolFlag{Name: "show-ignored", Usage: T("List the app files that are not uploaded, or uploaded only because of a '!' pattern, along with the ignore file rule responsible")}
+	fs["use-gitignore"] = &flags.BoolFlag{Name: "use-gitignore", Usage: T("Also ignore the files listed in .gitignore files. Patterns in .cfignore files take precedence")}
+	fs["strategy"] = &flags.StringFlag{Name: "strategy", Usage: T("Strategy for replacing an existing app. 'blue-green' pushes the new version as a separate app and moves the routes to it once all of its instances are running")}
+	fs["keep-old-app"] = &flags.BoolFlag{Name: "keep-old-app", Usage: T("Keep the old version of the app, renamed to APP_NAME-old, after a blue-green push")}
 	fs["route-path"] = &flags.StringFlag{Name: "route-path", Usage: T("Path for the route")}
+	fs["var"] = &flags.StringSliceFlag{Name: "var", Usage: T("Variable key value pair for variable substitution in the manifest (e.g. name=app1). This flag can be defined more than once.")}
+	fs["vars-file"] = &flags.StringSliceFlag{Name: "vars-file", Usage: T("Path to a YAML file of variables for variable substitution in the manifest. This flag can be defined more than once.")}
 	// Hidden:true to hide app-ports for release #117189491
 	fs["app-ports"] = &flags.StringFlag{Name: "app-ports", Usage: T("Comma delimited list of ports the application may listen on"), Hidden: true}
 
@@ -101,14 +116,26 @@ func (cmd *Push) MetaData() commandregistry.CommandMetadata {
 			fmt.Sprintf("[-u %s] ", T("HEALTH_CHECK_TYPE")),
 			fmt.Sprintf("[--route-path %s] ", T("ROUTE_PATH")),
 			"\n   ",
+			fmt.Sprintf("[--var %s] ", T("KEY=VALUE")),
+			fmt.Sprintf("[--vars-file %s] ", T("VARS_FILE_PATH")),
+			fmt.Sprintf("[--strategy %s] [--keep-old-app]", T("STRATEGY")),
+			"\n   ",
 			// Commented to hide app-ports for release #117189491
 			// fmt.Sprintf("[--app-ports %s] ", T("APP_PORTS")),
-			"[--no-hostname] [--no-manifest] [--no-route] [--no-start]\n",
+			"[--dry-run] [--no-cache] [--no-hostname] [--no-manifest] [--no-route] [--no-start]",
+			"\n   ",
+			"[--show-ignored] [--use-gitignore]\n",
 			"\n   ",
 			T("Push multiple apps with a manifest"),
 			":\n   ",
 			"CF_NAME push ",
 			fmt.Sprintf("[-f %s] ", T("MANIFEST_PATH")),
+			fmt.Sprintf("[--var %s] ", T("KEY=VALUE")),
+			fmt.Sprintf("[--vars-file %s] ", T("VARS_FILE_PATH")),
+			fmt.Sprintf("[--parallel %s] ", T("NUM_APPS")),
+			"\n   ",
+			fmt.Sprintf("[--strategy %s] [--keep-old-app] ", T("STRATEGY")),
+			"[--dry-run] [--no-cache] [--show-ignored] [--use-gitignore]",
 			"\n",
 		},
 		Flags: fs,
@@ -143,6 +170,7 @@ func (cmd *Push) Requirements(requirementsFactory requirements.Factory, fc flags
 }
 
 func (cmd *Push) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	cmd.deps = deps
 	cmd.ui = deps.UI
 	cmd.config = deps.Config
 	cmd.manifestRepo = deps.ManifestRepo
@@ -163,6 +191,7 @@ func (cmd *Push) SetDependency(deps commandregistry.Dependency, pluginCall bool)
 	cmd.serviceBinder = appCommand.(service.ServiceBinder)
 
 	cmd.appRepo = deps.RepoLocator.GetApplicationRepository()
+	cmd.appSummaryRepo = deps.RepoLocator.GetAppSummaryRepository()
 	cmd.domainRepo = deps.RepoLocator.GetDomainRepository()
 	cmd.routeRepo = deps.RepoLocator.GetRouteRepository()
 	cmd.serviceRepo = deps.RepoLocator.GetServiceRepository()
@@ -170,8 +199,8 @@ func (cmd *Push) SetDependency(deps commandregistry.Dependency, pluginCall bool)
 	cmd.authRepo = deps.RepoLocator.GetAuthenticationRepository()
 	cmd.wordGenerator = deps.WordGenerator
 	cmd.actor = deps.PushActor
-	cmd.zipper = deps.AppZipper
 	cmd.appfiles = deps.AppFiles
+	cmd.resourceCache = deps.ResourceCache
 
 	return cmd
 }
@@ -181,97 +210,550 @@ func (cmd *Push) Execute(c flags.FlagContext) {
 	appFromContext := cmd.getAppParamsFromContext(c)
 	appSet := cmd.createAppSetFromContextAndManifest(appFromContext, appsFromManifest)
 
+	if c.IsSet("parallel") && c.Int("parallel") < 1 {
+		cmd.ui.Failed(T("Invalid parallel count: {{.Count}}\nParallel count must be a positive integer",
+			map[string]interface{}{"Count": c.Int("parallel")}))
+	}
+
+	switch c.String("strategy") {
+	case "":
+	case blueGreenStrategy:
+		if c.Bool("no-start") {
+			cmd.ui.Failed(T("Incorrect Usage. '--no-start' cannot be used with the blue-green strategy, which needs the new version to be running before it takes over."))
+		}
+	default:
+		cmd.ui.Failed(T("Invalid strategy: {{.Strategy}}\nThe supported strategy is '{{.BlueGreen}}'",
+			map[string]interface{}{"Strategy": c.String("strategy"), "BlueGreen": blueGreenStrategy}))
+	}
+
 	_, err := cmd.authRepo.RefreshAuthToken()
 	if err != nil {
 		cmd.ui.Failed(err.Error())
 		return
 	}
 
+	if c.Bool("dry-run") {
+		cmd.ui.Say(T("Dry run: nothing will be changed on the server\n"))
+		for _, appParams := range appSet {
+			cmd.showPushPlan(appParams)
+		}
+		return
+	}
+
+	if c.Int("parallel") > 1 && len(appSet) > 1 {
+		cmd.pushInParallel(appSet, c.Int("parallel"), c)
+		return
+	}
+
 	routeActor := actors.NewRouteActor(cmd.ui, cmd.routeRepo)
 
 	for _, appParams := range appSet {
-		if appParams.Name == nil {
-			cmd.ui.Failed(T("Error: No name found for app"))
+		cmd.pushApp(appParams, routeActor, c)
+	}
+}
+
+type appPushResult struct {
+	appName string
+	failure string
+	failed  bool
+}
+
+// pushInParallel pushes the apps with up to workers of them being uploaded
+// and staged at the same time. The output of each app, including its staging
+// logs, is prefixed with the app's name. A failed app does not stop the
+// others; the outcome of every app is summarized once they are all done.
+func (cmd *Push) pushInParallel(appSet []models.AppParams, workers int, c flags.FlagContext) {
+	if workers > len(appSet) {
+		workers = len(appSet)
+	}
+
+	prefixWidth := 0
+	for _, appParams := range appSet {
+		if len(*appParams.Name) > prefixWidth {
+			prefixWidth = len(*appParams.Name)
 		}
+	}
+
+	outputLock := &sync.Mutex{}
+	uis := make([]*appPushUI, len(appSet))
+	pushers := make([]*Push, len(appSet))
+	for i, appParams := range appSet {
+		prefix := fmt.Sprintf("%-*s | ", prefixWidth, *appParams.Name)
+		uis[i] = &appPushUI{UI: terminal.NewPrefixedUI(cmd.ui, prefix, outputLock)}
+		pushers[i] = cmd.newAppPusher(uis[i])
+	}
+
+	results := make([]appPushResult, len(appSet))
+	jobs := make(chan int)
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				results[index] = pushers[index].tryPushApp(appSet[index], uis[index], c)
+			}
+		}()
+	}
+
+	for index := range appSet {
+		jobs <- index
+	}
+	close(jobs)
+	wg.Wait()
 
-		cmd.fetchStackGUID(&appParams)
+	cmd.ui.Say("")
 
-		if c.IsSet("docker-image") {
-			diego := true
-			appParams.Diego = &diego
+	failedCount := 0
+	table := cmd.ui.Table([]string{T("app"), T("status"), T("details")})
+	table.SetFieldNames("app", "status", "details")
+	for _, result := range results {
+		if result.failed {
+			failedCount++
+			table.Add(result.appName, terminal.FailureColor(T("failed")), result.failure)
+		} else {
+			table.Add(result.appName, terminal.SuccessColor(T("pushed")), "")
 		}
+	}
+	table.Print()
 
-		var app models.Application
-		existingApp, err := cmd.appRepo.Read(*appParams.Name)
+	if failedCount > 0 {
+		cmd.ui.Say("")
+		cmd.ui.Failed(T("{{.FailedCount}} of {{.AppCount}} apps failed to push",
+			map[string]interface{}{"FailedCount": failedCount, "AppCount": len(appSet)}))
+	}
+}
+
+// tryPushApp pushes a single app, turning a failure reported through ui into
+// a result rather than letting it end the whole command.
+func (cmd *Push) tryPushApp(appParams models.AppParams, ui *appPushUI, c flags.FlagContext) (result appPushResult) {
+	result.appName = *appParams.Name
+
+	defer func() {
+		if r := recover(); r != nil {
+			if r != terminal.QuietPanic {
+				panic(r)
+			}
+			result.failed = true
+			result.failure = ui.failure
+		}
+	}()
+
+	cmd.pushApp(appParams, actors.NewRouteActor(ui, cmd.routeRepo), c)
+	return
+}
+
+// newAppPusher returns a copy of cmd that reports through ui and tails
+// staging logs over a connection of its own. The registered start, stop and
+// app commands are single instances shared by every command using them, so
+// the pusher gets new ones rather than rewiring those. Any other registered
+// implementation is used as it is.
+func (cmd *Push) newAppPusher(ui terminal.UI) *Push {
+	deps := cmd.deps
+	deps.UI = ui
+	deps.RepoLocator = deps.RepoLocator.SetLogsRepository(deps.RepoLocator.NewLogsRepository())
+
+	pusher := *cmd
+	pusher.ui = ui
+
+	if _, ok := commandregistry.Commands.FindCommand("start").(*Start); ok {
+		starter := &Start{}
+		starter.SetDependency(deps, false)
+		starter.appDisplayer = (&ShowApp{}).SetDependency(deps, false).(ApplicationDisplayer)
+		pusher.appStarter = starter
+	}
+
+	if _, ok := commandregistry.Commands.FindCommand("stop").(*Stop); ok {
+		pusher.appStopper = (&Stop{}).SetDependency(deps, false).(ApplicationStopper)
+	}
+
+	return &pusher
+}
+
+// appPushUI remembers the last failure reported for an app, for the summary
+// of a parallel push.
+type appPushUI struct {
+	terminal.UI
+	failure string
+}
+
+func (ui *appPushUI) Failed(message string, args ...interface{}) {
+	ui.failure = strings.TrimSpace(fmt.Sprintf(message, args...))
+	ui.UI.Failed("%s", ui.failure)
+}
+
+func (cmd *Push) pushApp(appParams models.AppParams, routeActor actors.RouteActor, c flags.FlagContext) {
+	if appParams.Name == nil {
+		cmd.ui.Failed(T("Error: No name found for app"))
+	}
+
+	if c.String("strategy") == blueGreenStrategy {
+		liveApp, err := cmd.appRepo.Read(*appParams.Name)
 		switch err.(type) {
 		case nil:
-			cmd.ui.Say(T("Updating app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...",
-				map[string]interface{}{
-					"AppName":   terminal.EntityNameColor(existingApp.Name),
-					"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
-					"SpaceName": terminal.EntityNameColor(cmd.config.SpaceFields().Name),
-					"Username":  terminal.EntityNameColor(cmd.config.Username())}))
-
-			if appParams.EnvironmentVars != nil {
-				for key, val := range existingApp.EnvironmentVars {
-					if _, ok := (*appParams.EnvironmentVars)[key]; !ok {
-						(*appParams.EnvironmentVars)[key] = val
-					}
+			cmd.pushBlueGreen(liveApp, appParams, routeActor, c)
+			return
+		case *errors.ModelNotFoundError:
+			// there is no live app to keep serving, so push it as usual
+		default:
+			cmd.ui.Failed(err.Error())
+		}
+	}
+
+	app := cmd.createOrUpdateApp(appParams, c)
+	cmd.updateRoutes(routeActor, app, appParams)
+	cmd.deployApp(app, appParams, c)
+}
+
+func (cmd *Push) createOrUpdateApp(appParams models.AppParams, c flags.FlagContext) models.Application {
+	cmd.fetchStackGUID(&appParams)
+
+	if c.IsSet("docker-image") {
+		diego := true
+		appParams.Diego = &diego
+	}
+
+	var app models.Application
+	existingApp, err := cmd.appRepo.Read(*appParams.Name)
+	switch err.(type) {
+	case nil:
+		cmd.ui.Say(T("Updating app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...",
+			map[string]interface{}{
+				"AppName":   terminal.EntityNameColor(existingApp.Name),
+				"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+				"SpaceName": terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+				"Username":  terminal.EntityNameColor(cmd.config.Username())}))
+
+		if appParams.EnvironmentVars != nil {
+			for key, val := range existingApp.EnvironmentVars {
+				if _, ok := (*appParams.EnvironmentVars)[key]; !ok {
+					(*appParams.EnvironmentVars)[key] = val
 				}
 			}
+		}
 
-			app, err = cmd.appRepo.Update(existingApp.GUID, appParams)
-			if err != nil {
-				cmd.ui.Failed(err.Error())
-			}
+		app, err = cmd.appRepo.Update(existingApp.GUID, appParams)
+		if err != nil {
+			cmd.ui.Failed(err.Error())
+		}
+	case *errors.ModelNotFoundError:
+		spaceGUID := cmd.config.SpaceFields().GUID
+		appParams.SpaceGUID = &spaceGUID
+
+		cmd.ui.Say(T("Creating app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...",
+			map[string]interface{}{
+				"AppName":   terminal.EntityNameColor(*appParams.Name),
+				"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+				"SpaceName": terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+				"Username":  terminal.EntityNameColor(cmd.config.Username())}))
+
+		app, err = cmd.appRepo.Create(appParams)
+		if err != nil {
+			cmd.ui.Failed(err.Error())
+		}
+	default:
+		cmd.ui.Failed(err.Error())
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+
+	return app
+}
+
+func (cmd *Push) deployApp(app models.Application, appParams models.AppParams, c flags.FlagContext) {
+	if c.String("docker-image") == "" {
+		err := cmd.actor.ProcessPath(*appParams.Path, cmd.processPathCallback(*appParams.Path, app, c))
+		if err != nil {
+			cmd.ui.Failed(
+				T("Error processing app files: {{.Error}}",
+					map[string]interface{}{
+						"Error": err.Error(),
+					}),
+			)
+			return
+		}
+	}
+
+	if appParams.ServicesToBind != nil {
+		cmd.bindAppToServices(*appParams.ServicesToBind, app)
+	}
+
+	cmd.restart(app, appParams, c)
+}
+
+const blueGreenStrategy = "blue-green"
+
+// pushBlueGreen replaces liveApp without downtime. The new version is pushed
+// as a separate app, and only once all of its instances are running are the
+// routes moved over to it and the apps renamed. Should any of those steps
+// fail, the ones done so far are undone, so liveApp keeps serving its routes.
+func (cmd *Push) pushBlueGreen(liveApp models.Application, appParams models.AppParams, routeActor actors.RouteActor, c flags.FlagContext) {
+	appName := liveApp.Name
+	newAppName := appName + "-new"
+	oldAppName := appName + "-old"
+
+	// the new app must not take over, or on failure delete, an app that
+	// this push did not create
+	for _, name := range []string{oldAppName, newAppName} {
+		_, err := cmd.appRepo.Read(name)
+		switch err.(type) {
+		case nil:
+			cmd.ui.Failed(T("App {{.ExistingAppName}} already exists. Rename or delete it before pushing {{.AppName}} with the blue-green strategy.",
+				map[string]interface{}{"ExistingAppName": name, "AppName": appName}))
 		case *errors.ModelNotFoundError:
-			spaceGUID := cmd.config.SpaceFields().GUID
-			appParams.SpaceGUID = &spaceGUID
+		default:
+			cmd.ui.Failed(err.Error())
+		}
+	}
 
-			cmd.ui.Say(T("Creating app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...",
+	summary, err := cmd.appSummaryRepo.GetSummary(liveApp.GUID)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+	liveApp.Routes = summary.Routes
+
+	cmd.ui.Say(T("Pushing the new version of {{.AppName}} as {{.NewAppName}}...",
+		map[string]interface{}{
+			"AppName":    terminal.EntityNameColor(appName),
+			"NewAppName": terminal.EntityNameColor(newAppName)}))
+	cmd.ui.Say("")
+
+	newParams := blueGreenAppParams(liveApp, summary.Services, appParams, newAppName)
+	wasWaitingForAllInstances := cmd.appStarter.SetWaitForAllInstances(true)
+	defer cmd.appStarter.SetWaitForAllInstances(wasWaitingForAllInstances)
+
+	newApp := cmd.createOrUpdateApp(newParams, c)
+
+	// until the new app has taken over, a failure undoes every step so far,
+	// and the old app keeps serving its routes
+	var undo []func()
+	defer func() {
+		if r := recover(); r != nil {
+			if len(undo) > 0 {
+				cmd.ui.Say("")
+				cmd.ui.Say(T("Rolling back, {{.AppName}} keeps serving its routes...",
+					map[string]interface{}{"AppName": terminal.EntityNameColor(liveApp.Name)}))
+				for i := len(undo) - 1; i >= 0; i-- {
+					undo[i]()
+				}
+			}
+			panic(r)
+		}
+	}()
+
+	undo = append(undo, func() {
+		cmd.deleteApp(newApp)
+	})
+
+	if len(newApp.Routes) > 0 {
+		routeActor.UnbindAll(newApp)
+		newApp.Routes = nil
+	}
+	cmd.deployApp(newApp, newParams, c)
+
+	cmd.switchToNewApp(liveApp, newApp, appParams, routeActor, oldAppName, &undo)
+	undo = nil
+
+	if c.Bool("keep-old-app") {
+		cmd.ui.Say(T("The old version of {{.AppName}} has been kept as {{.OldAppName}}",
+			map[string]interface{}{
+				"AppName":    terminal.EntityNameColor(appName),
+				"OldAppName": terminal.EntityNameColor(oldAppName)}))
+		return
+	}
+
+	cmd.ui.Say(T("Deleting the old version of {{.AppName}}...",
+		map[string]interface{}{"AppName": terminal.EntityNameColor(appName)}))
+	err = cmd.appRepo.Delete(liveApp.GUID)
+	if err != nil {
+		cmd.ui.Failed(T("The new version of {{.AppName}} is live, but the old version could not be deleted: {{.Err}}\nIt has been left as {{.OldAppName}}.",
+			map[string]interface{}{"AppName": appName, "OldAppName": oldAppName, "Err": err.Error()}))
+	}
+	cmd.ui.Ok()
+}
+
+// switchToNewApp moves the routes of liveApp to newApp and gives newApp its
+// name, adding how to undo each step to undo.
+func (cmd *Push) switchToNewApp(liveApp models.Application, newApp models.Application, appParams models.AppParams, routeActor actors.RouteActor, oldAppName string, undo *[]func()) {
+	if !appParams.NoRoute {
+		*undo = append(*undo, func() {
+			cmd.unbindAllRoutes(newApp)
+		})
+
+		for _, summary := range liveApp.Routes {
+			routeActor.BindRoute(newApp, models.Route{
+				GUID:   summary.GUID,
+				Host:   summary.Host,
+				Domain: summary.Domain,
+				Path:   summary.Path,
+				Port:   summary.Port,
+			})
+		}
+
+		routedApp := newApp
+		routedApp.Name = liveApp.Name
+		routedApp.Routes = liveApp.Routes
+		cmd.updateRoutes(routeActor, routedApp, appParams)
+
+		for _, route := range liveApp.Routes {
+			route := route
+			cmd.ui.Say(T("Unbinding {{.URL}} from {{.AppName}}...",
 				map[string]interface{}{
-					"AppName":   terminal.EntityNameColor(*appParams.Name),
-					"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
-					"SpaceName": terminal.EntityNameColor(cmd.config.SpaceFields().Name),
-					"Username":  terminal.EntityNameColor(cmd.config.Username())}))
+					"URL":     terminal.EntityNameColor(route.URL()),
+					"AppName": terminal.EntityNameColor(liveApp.Name)}))
 
-			app, err = cmd.appRepo.Create(appParams)
+			err := cmd.routeRepo.Unbind(route.GUID, liveApp.GUID)
 			if err != nil {
 				cmd.ui.Failed(err.Error())
 			}
-		default:
-			cmd.ui.Failed(err.Error())
-		}
 
+			*undo = append(*undo, func() {
+				err := cmd.routeRepo.Bind(route.GUID, liveApp.GUID)
+				if err != nil {
+					cmd.ui.Warn(T("Could not bind {{.URL}} back to {{.AppName}}: {{.Err}}",
+						map[string]interface{}{"URL": route.URL(), "AppName": liveApp.Name, "Err": err.Error()}))
+				}
+			})
+		}
 		cmd.ui.Ok()
 		cmd.ui.Say("")
+	}
 
-		cmd.updateRoutes(routeActor, app, appParams)
+	cmd.renameApp(liveApp, oldAppName)
+	*undo = append(*undo, func() {
+		cmd.renameAppBack(liveApp, oldAppName)
+	})
 
-		if c.String("docker-image") == "" {
-			err := cmd.actor.ProcessPath(*appParams.Path, cmd.processPathCallback(*appParams.Path, app))
-			if err != nil {
-				cmd.ui.Failed(
-					T("Error processing app files: {{.Error}}",
-						map[string]interface{}{
-							"Error": err.Error(),
-						}),
-				)
-				return
-			}
+	cmd.renameApp(newApp, liveApp.Name)
+	cmd.ui.Say("")
+}
+
+func (cmd *Push) renameApp(app models.Application, newName string) {
+	cmd.ui.Say(T("Renaming app {{.AppName}} to {{.NewName}}...",
+		map[string]interface{}{
+			"AppName": terminal.EntityNameColor(app.Name),
+			"NewName": terminal.EntityNameColor(newName)}))
+
+	_, err := cmd.appRepo.Update(app.GUID, models.AppParams{Name: &newName})
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+	cmd.ui.Ok()
+}
+
+func (cmd *Push) renameAppBack(app models.Application, currentName string) {
+	_, err := cmd.appRepo.Update(app.GUID, models.AppParams{Name: &app.Name})
+	if err != nil {
+		cmd.ui.Warn(T("Could not rename {{.CurrentName}} back to {{.AppName}}: {{.Err}}",
+			map[string]interface{}{"CurrentName": currentName, "AppName": app.Name, "Err": err.Error()}))
+	}
+}
+
+func (cmd *Push) deleteApp(app models.Application) {
+	err := cmd.appRepo.Delete(app.GUID)
+	if err != nil {
+		cmd.ui.Warn(T("Could not delete {{.AppName}}: {{.Err}}",
+			map[string]interface{}{"AppName": app.Name, "Err": err.Error()}))
+	}
+}
+
+func (cmd *Push) unbindAllRoutes(app models.Application) {
+	summary, err := cmd.appSummaryRepo.GetSummary(app.GUID)
+	if err != nil {
+		cmd.ui.Warn(T("Could not remove the routes from {{.AppName}}: {{.Err}}",
+			map[string]interface{}{"AppName": app.Name, "Err": err.Error()}))
+		return
+	}
+
+	for _, route := range summary.Routes {
+		err = cmd.routeRepo.Unbind(route.GUID, app.GUID)
+		if err != nil {
+			cmd.ui.Warn(T("Could not unbind {{.URL}} from {{.AppName}}: {{.Err}}",
+				map[string]interface{}{"URL": route.URL(), "AppName": app.Name, "Err": err.Error()}))
+		}
+	}
+}
+
+// blueGreenAppParams returns the params for pushing the new version of
+// liveApp as an app called name. Properties not given in appParams are
+// carried over from liveApp, as are its environment variables and service
+// bindings, just as they would be by pushing over liveApp itself.
+func blueGreenAppParams(liveApp models.Application, liveServices []models.ServicePlanSummary, appParams models.AppParams, name string) models.AppParams {
+	params := models.AppParams{
+		BuildpackURL:  &liveApp.BuildpackURL,
+		Command:       &liveApp.Command,
+		DiskQuota:     &liveApp.DiskQuota,
+		InstanceCount: &liveApp.InstanceCount,
+		Memory:        &liveApp.Memory,
+	}
+	if liveApp.HealthCheckType != "" {
+		params.HealthCheckType = &liveApp.HealthCheckType
+	}
+	if liveApp.StackGUID != "" {
+		params.StackGUID = &liveApp.StackGUID
+	}
+	if liveApp.DockerImage != "" {
+		params.DockerImage = &liveApp.DockerImage
+	}
+
+	params.Merge(&appParams)
+	params.Name = &name
+
+	envVars := map[string]interface{}{}
+	for key, value := range liveApp.EnvironmentVars {
+		envVars[key] = value
+	}
+	if appParams.EnvironmentVars != nil {
+		for key, value := range *appParams.EnvironmentVars {
+			envVars[key] = value
 		}
+	}
+	params.EnvironmentVars = &envVars
 
-		if appParams.ServicesToBind != nil {
-			cmd.bindAppToServices(*appParams.ServicesToBind, app)
+	var services []string
+	for _, service := range liveServices {
+		services = append(services, service.Name)
+	}
+	if appParams.ServicesToBind != nil {
+		for _, serviceName := range *appParams.ServicesToBind {
+			if !stringInSlice(serviceName, services) {
+				services = append(services, serviceName)
+			}
 		}
+	}
+	params.ServicesToBind = &services
 
-		cmd.restart(app, appParams, c)
+	return params
+}
+
+func stringInSlice(value string, slice []string) bool {
+	for _, item := range slice {
+		if item == value {
+			return true
+		}
 	}
+	return false
 }
 
-func (cmd *Push) processPathCallback(path string, app models.Application) func(string) {
+func (cmd *Push) processPathCallback(path string, app models.Application, c flags.FlagContext) func(string) {
 	return func(appDir string) {
-		localFiles, err := cmd.appfiles.AppFilesInDir(appDir)
+		var cache *appfiles.AppResourceCache
+		if cmd.resourceCache != nil && !c.Bool("no-cache") {
+			cache = cmd.resourceCache.ForApp(app.GUID)
+		}
+
+		appFiles := cmd.appfiles
+		if c.Bool("use-gitignore") {
+			appFiles = appFiles.WithGitignore()
+		}
+
+		if c.Bool("show-ignored") {
+			cmd.showIgnoredFiles(appFiles, appDir)
+		}
+
+		localFiles, err := appFiles.AppFilesInDir(appDir, cache)
 		if err != nil {
 			cmd.ui.Failed(
 				T("Error processing app files in '{{.Path}}': {{.Error}}",
@@ -294,7 +776,7 @@ func (cmd *Push) processPathCallback(path string, app models.Application) func(s
 		cmd.ui.Say(T("Uploading {{.AppName}}...",
 			map[string]interface{}{"AppName": terminal.EntityNameColor(app.Name)}))
 
-		err = cmd.uploadApp(app.GUID, appDir, path, localFiles)
+		err = cmd.uploadApp(app.GUID, appDir, path, localFiles, cache)
 		if err != nil {
 			cmd.ui.Failed(fmt.Sprintf(T("Error uploading application.\n{{.APIErr}}",
 				map[string]interface{}{"APIErr": err.Error()})))
@@ -304,6 +786,34 @@ func (cmd *Push) processPathCallback(path string, app models.Application) func(s
 	}
 }
 
+// showIgnoredFiles lists the files in appDir that an ignore file rule
+// matches, whether the rule leaves them out of the upload or not.
+func (cmd *Push) showIgnoredFiles(appFiles appfiles.AppFiles, appDir string) {
+	ignoredFiles, err := appFiles.IgnoredFilesInDir(appDir)
+	if err != nil {
+		cmd.ui.Warn(T("Could not list the ignored files: {{.Err}}", map[string]interface{}{"Err": err.Error()}))
+		return
+	}
+
+	if len(ignoredFiles) == 0 {
+		cmd.ui.Say(T("No app files are ignored"))
+		cmd.ui.Say("")
+		return
+	}
+
+	table := cmd.ui.Table([]string{T("path"), T("status"), T("rule")})
+	table.SetFieldNames("path", "status", "rule")
+	for _, ignoredFile := range ignoredFiles {
+		status := T("ignored")
+		if !ignoredFile.Ignored {
+			status = T("included")
+		}
+		table.Add(ignoredFile.Path, status, ignoredFile.Rule.String())
+	}
+	table.Print()
+	cmd.ui.Say("")
+}
+
 func (cmd *Push) updateRoutes(routeActor actors.RouteActor, app models.Application, appParams models.AppParams) {
 	defaultRouteAcceptable := len(app.Routes) == 0
 	routeDefined := appParams.Domains != nil || !appParams.IsHostEmpty() || appParams.NoHostname
@@ -382,19 +892,7 @@ func (cmd *Push) createAndBindRoute(
 	domain models.DomainFields,
 	routePath *string,
 ) {
-	var hostname string
-	if !noHostName {
-		switch {
-		case host != nil:
-			hostname = *host
-		case UseRandomPort:
-			//do nothing
-		case UseRandomRoute:
-			hostname = hostNameForString(app.Name) + "-" + cmd.wordGenerator.Babble()
-		default:
-			hostname = hostNameForString(app.Name)
-		}
-	}
+	hostname := cmd.routeHostname(host, UseRandomRoute, UseRandomPort, app, noHostName)
 
 	var route models.Route
 	if routePath != nil {
@@ -405,6 +903,23 @@ func (cmd *Push) createAndBindRoute(
 	routeActor.BindRoute(app, route)
 }
 
+func (cmd *Push) routeHostname(host *string, useRandomRoute bool, useRandomPort bool, app models.Application, noHostName bool) string {
+	if noHostName {
+		return ""
+	}
+
+	switch {
+	case host != nil:
+		return *host
+	case useRandomPort:
+		return ""
+	case useRandomRoute:
+		return hostNameForString(app.Name) + "-" + cmd.wordGenerator.Babble()
+	default:
+		return hostNameForString(app.Name)
+	}
+}
+
 var forbiddenHostCharRegex = regexp.MustCompile("[^a-z0-9-]")
 var whitespaceRegex = regexp.MustCompile(`[\s_]+`)
 
@@ -498,31 +1013,256 @@ func (cmd *Push) restart(app models.Application, params models.AppParams, c flag
 	cmd.appStarter.ApplicationStart(app, cmd.config.OrganizationFields().Name, cmd.config.SpaceFields().Name)
 }
 
-func (cmd *Push) getAppParamsFromManifest(c flags.FlagContext) []models.AppParams {
-	if c.Bool("no-manifest") {
-		return []models.AppParams{}
-	}
+const (
+	planCreate = "+"
+	planUpdate = "~"
+	planDelete = "-"
+)
+
+type pushPlanChange struct {
+	action   string
+	property string
+	value    string
+	newValue string
+}
 
-	var path string
-	if c.String("f") != "" {
-		path = c.String("f")
+func (change pushPlanChange) String() string {
+	var line string
+	if change.newValue == "" {
+		line = fmt.Sprintf("%s %s: %s", change.action, change.property, change.value)
 	} else {
-		var err error
-		path, err = os.Getwd()
+		line = fmt.Sprintf("%s %s: %s -> %s", change.action, change.property, change.value, change.newValue)
+	}
+
+	switch change.action {
+	case planCreate:
+		return terminal.SuccessColor(line)
+	case planDelete:
+		return terminal.FailureColor(line)
+	default:
+		return terminal.WarningColor(line)
+	}
+}
+
+// showPushPlan prints the changes pushing appParams would make to the app,
+// reading the app's current state but changing nothing.
+func (cmd *Push) showPushPlan(appParams models.AppParams) {
+	if appParams.Name == nil {
+		cmd.ui.Failed(T("Error: No name found for app"))
+	}
+
+	app, err := cmd.appRepo.Read(*appParams.Name)
+	exists := true
+	switch err.(type) {
+	case nil:
+		summary, err := cmd.appSummaryRepo.GetSummary(app.GUID)
 		if err != nil {
-			cmd.ui.Failed(T("Could not determine the current working directory!"), err)
+			cmd.ui.Failed(err.Error())
+		}
+		app.Routes = summary.Routes
+		app.Services = summary.Services
+
+		cmd.ui.Say(T("App {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} would be updated:",
+			map[string]interface{}{
+				"AppName":   terminal.EntityNameColor(app.Name),
+				"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+				"SpaceName": terminal.EntityNameColor(cmd.config.SpaceFields().Name)}))
+	case *errors.ModelNotFoundError:
+		exists = false
+		app = models.Application{}
+		app.Name = *appParams.Name
+
+		cmd.ui.Say(T("App {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} would be created:",
+			map[string]interface{}{
+				"AppName":   terminal.EntityNameColor(app.Name),
+				"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+				"SpaceName": terminal.EntityNameColor(cmd.config.SpaceFields().Name)}))
+	default:
+		cmd.ui.Failed(err.Error())
+	}
+
+	changes := cmd.planAppChanges(app, appParams, exists)
+	if len(changes) == 0 {
+		cmd.ui.Say(T("  no changes"))
+	}
+	for _, change := range changes {
+		cmd.ui.Say("  %s", change)
+	}
+	cmd.ui.Say("")
+}
+
+// planAppChanges compares app with what push would make of it. Like push
+// itself, it only adds environment variables, routes and service bindings,
+// except for removing all routes when no-route is set.
+func (cmd *Push) planAppChanges(app models.Application, appParams models.AppParams, exists bool) []pushPlanChange {
+	var changes []pushPlanChange
+
+	compare := func(property string, current string, desired string) {
+		switch {
+		case !exists:
+			changes = append(changes, pushPlanChange{action: planCreate, property: property, value: desired})
+		case current != desired:
+			changes = append(changes, pushPlanChange{action: planUpdate, property: property, value: current, newValue: desired})
+		}
+	}
+
+	if appParams.Memory != nil {
+		compare(T("memory"), formatMegabytes(app.Memory), formatMegabytes(*appParams.Memory))
+	}
+	if appParams.DiskQuota != nil {
+		compare(T("disk"), formatMegabytes(app.DiskQuota), formatMegabytes(*appParams.DiskQuota))
+	}
+	if appParams.InstanceCount != nil {
+		compare(T("instances"), strconv.Itoa(app.InstanceCount), strconv.Itoa(*appParams.InstanceCount))
+	}
+	if appParams.Command != nil {
+		compare(T("command"), orDefault(app.Command), orDefault(*appParams.Command))
+	}
+	if appParams.BuildpackURL != nil {
+		compare(T("buildpack"), orDefault(app.BuildpackURL), orDefault(*appParams.BuildpackURL))
+	}
+	if appParams.StackName != nil {
+		currentStack := ""
+		if app.Stack != nil {
+			currentStack = app.Stack.Name
+		}
+		compare(T("stack"), orDefault(currentStack), *appParams.StackName)
+	}
+
+	if appParams.EnvironmentVars != nil {
+		keys := make([]string, 0, len(*appParams.EnvironmentVars))
+		for key := range *appParams.EnvironmentVars {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			current, found := app.EnvironmentVars[key]
+			switch {
+			case !found:
+				changes = append(changes, pushPlanChange{action: planCreate, property: T("env"), value: key})
+			case fmt.Sprint(current) != fmt.Sprint((*appParams.EnvironmentVars)[key]):
+				changes = append(changes, pushPlanChange{action: planUpdate, property: T("env"), value: key})
+			}
+		}
+	}
+
+	changes = append(changes, cmd.planRouteChanges(app, appParams)...)
+
+	if appParams.ServicesToBind != nil {
+		for _, serviceName := range *appParams.ServicesToBind {
+			bound := false
+			for _, service := range app.Services {
+				if service.Name == serviceName {
+					bound = true
+				}
+			}
+			if !bound {
+				changes = append(changes, pushPlanChange{action: planCreate, property: T("service binding"), value: serviceName})
+			}
+		}
+	}
+
+	return changes
+}
+
+func (cmd *Push) planRouteChanges(app models.Application, appParams models.AppParams) []pushPlanChange {
+	var changes []pushPlanChange
+
+	if appParams.NoRoute {
+		for _, route := range app.Routes {
+			changes = append(changes, pushPlanChange{action: planDelete, property: T("route"), value: route.URL()})
+		}
+		return changes
+	}
+
+	defaultRouteAcceptable := len(app.Routes) == 0
+	routeDefined := appParams.Domains != nil || !appParams.IsHostEmpty() || appParams.NoHostname
+	if !routeDefined && !defaultRouteAcceptable {
+		return changes
+	}
+
+	var domains []models.DomainFields
+	if appParams.Domains == nil {
+		domains = append(domains, cmd.findDomain(nil))
+	} else {
+		for _, d := range *appParams.Domains {
+			domains = append(domains, cmd.findDomain(&d))
+		}
+	}
+
+	hosts := []*string{nil}
+	if !appParams.IsHostEmpty() {
+		hosts = nil
+		for i := range *appParams.Hosts {
+			hosts = append(hosts, &(*appParams.Hosts)[i])
 		}
 	}
 
-	m, err := cmd.manifestRepo.ReadManifest(path)
+	routePath := ""
+	if appParams.RoutePath != nil {
+		routePath = *appParams.RoutePath
+	}
+
+	for _, domain := range domains {
+		for _, host := range hosts {
+			hostname := cmd.routeHostname(host, appParams.UseRandomRoute, isTcp(domain), app, appParams.NoHostname)
+			url := domain.URLForHostAndPath(hostname, routePath, 0)
+			if isTcp(domain) {
+				url = T("{{.Domain}} with a random port", map[string]interface{}{"Domain": url})
+			}
+
+			bound := false
+			for _, route := range app.Routes {
+				if route.URL() == url {
+					bound = true
+				}
+			}
+			if !bound {
+				changes = append(changes, pushPlanChange{action: planCreate, property: T("route"), value: url})
+			}
+		}
+	}
+
+	return changes
+}
+
+func formatMegabytes(megabytes int64) string {
+	return formatters.ByteSize(megabytes * formatters.MEGABYTE)
+}
+
+func orDefault(value string) string {
+	if value == "" {
+		return T("default")
+	}
+	return value
+}
+
+func (cmd *Push) getAppParamsFromManifest(c flags.FlagContext) []models.AppParams {
+	if c.Bool("no-manifest") {
+		return []models.AppParams{}
+	}
+
+	paths := c.StringSlice("f")
+	m, err := readManifest(cmd.manifestRepo, paths)
 
 	if err != nil {
-		if m.Path == "" && c.String("f") == "" {
+		if m.Path == "" && len(paths) == 0 {
 			return []models.AppParams{}
 		}
 		cmd.ui.Failed(T("Error reading manifest file:\n{{.Err}}", map[string]interface{}{"Err": err.Error()}))
 	}
 
+	m.Variables, err = getManifestVariables(c)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	err = m.Validate()
+	if err != nil {
+		cmd.ui.Failed(T("Error validating manifest file:\n{{.Err}}", map[string]interface{}{"Err": err.Error()}))
+	}
+
 	apps, err := m.Applications()
 	if err != nil {
 		cmd.ui.Failed("Error reading manifest file:\n%s", err)
@@ -533,6 +1273,43 @@ func (cmd *Push) getAppParamsFromManifest(c flags.FlagContext) []models.AppParam
 	return apps
 }
 
+func readManifest(repo manifest.ManifestRepository, paths []string) (*manifest.Manifest, error) {
+	switch len(paths) {
+	case 0:
+		cwd, err := os.Getwd()
+		if err != nil {
+			return manifest.NewEmptyManifest(), errors.New(T("Could not determine the current working directory!"))
+		}
+		return repo.ReadManifest(cwd)
+	case 1:
+		return repo.ReadManifest(paths[0])
+	default:
+		return repo.ReadManifests(paths)
+	}
+}
+
+func getManifestVariables(c flags.FlagContext) (manifest.Variables, error) {
+	vars := manifest.NewVariables()
+
+	for _, path := range c.StringSlice("vars-file") {
+		fileVars, err := manifest.ReadVariablesFile(path)
+		if err != nil {
+			return nil, errors.New(T("Error reading vars file:\n{{.Err}}", map[string]interface{}{"Err": err.Error()}))
+		}
+		vars = vars.Merge(fileVars)
+	}
+
+	for _, pair := range c.StringSlice("var") {
+		key, value, err := manifest.ParseVariable(pair)
+		if err != nil {
+			return nil, err
+		}
+		vars[key] = value
+	}
+
+	return vars, nil
+}
+
 func (cmd *Push) createAppSetFromContextAndManifest(contextApp models.AppParams, manifestApps []models.AppParams) []models.AppParams {
 	var err error
 	var apps []models.AppParams
@@ -724,50 +1501,78 @@ func (cmd *Push) getAppParamsFromContext(c flags.FlagContext) models.AppParams {
 	return appParams
 }
 
-func (cmd *Push) uploadApp(appGUID, appDir, appDirOrZipFile string, localFiles []models.AppFileFields) error {
-	uploadDir, err := ioutil.TempDir("", "apps")
-	if err != nil {
-		return err
+func (cmd *Push) uploadApp(appGUID, appDir, appDirOrZipFile string, localFiles []models.AppFileFields, cache *appfiles.AppResourceCache) error {
+	err := cmd.uploadAppFiles(appGUID, appDir, localFiles, cache)
+	if err != nil && cache != nil && cache.HasMatches() {
+		// one of the cached matches may no longer be on the server, so the
+		// files are matched again
+		cache.ForgetMatches()
+		cmd.ui.Say(T("Retrying upload without the cached resource matches..."))
+		err = cmd.uploadAppFiles(appGUID, appDir, localFiles, cache)
 	}
-	defer os.RemoveAll(uploadDir)
 
-	remoteFiles, hasFileToUpload, err := cmd.actor.GatherFiles(localFiles, appDir, uploadDir)
+	if cache != nil {
+		if err != nil {
+			cache.ForgetMatches()
+		}
+		cmd.saveResourceCache()
+	}
+	return err
+}
+
+func (cmd *Push) uploadAppFiles(appGUID, appDir string, localFiles []models.AppFileFields, cache *appfiles.AppResourceCache) error {
+	uploadDir, err := ioutil.TempDir("", "apps")
 	if err != nil {
 		return err
 	}
+	defer os.RemoveAll(uploadDir)
 
-	zipFile, err := ioutil.TempFile("", "uploads")
+	remoteFiles, hasFileToUpload, err := cmd.actor.GatherFiles(localFiles, appDir, uploadDir, cache)
 	if err != nil {
 		return err
 	}
-	defer func() {
-		zipFile.Close()
-		os.Remove(zipFile.Name())
-	}()
 
+	dirToUpload := ""
 	if hasFileToUpload {
-		err = cmd.zipper.Zip(uploadDir, zipFile)
-		if err != nil {
-			if emptyDirErr, ok := err.(*errors.EmptyDirError); ok {
-				return emptyDirErr
-			}
-			return fmt.Errorf("%s: %s", T("Error zipping application"), err.Error())
-		}
+		dirToUpload = uploadDir
 
-		zipFileSize, err := cmd.zipper.GetZipSize(zipFile)
-		if err != nil {
-			return err
-		}
-
-		zipFileCount := cmd.appfiles.CountFiles(uploadDir)
-		if zipFileCount > 0 {
+		fileCount := cmd.appfiles.CountFiles(uploadDir)
+		if fileCount > 0 {
 			cmd.ui.Say(T("Uploading app files from: {{.Path}}", map[string]interface{}{"Path": appDir}))
 			cmd.ui.Say(T("Uploading {{.ZipFileBytes}}, {{.FileCount}} files",
 				map[string]interface{}{
-					"ZipFileBytes": formatters.ByteSize(zipFileSize),
-					"FileCount":    zipFileCount}))
+					"ZipFileBytes": formatters.ByteSize(sizeOfFilesToUpload(localFiles, remoteFiles)),
+					"FileCount":    fileCount}))
 		}
 	}
 
-	return cmd.actor.UploadApp(appGUID, zipFile, remoteFiles)
+	return cmd.actor.UploadApp(appGUID, dirToUpload, remoteFiles, func(attempt int, maxAttempts int) {
+		cmd.ui.Say(T("retrying upload (attempt {{.Attempt}}/{{.MaxAttempts}})",
+			map[string]interface{}{"Attempt": attempt, "MaxAttempts": maxAttempts}))
+	})
+}
+
+// sizeOfFilesToUpload adds up the sizes of the local files the server does
+// not have. The zip they are uploaded in is only made while it is uploaded,
+// so its size is not known beforehand.
+func sizeOfFilesToUpload(localFiles []models.AppFileFields, remoteFiles []resources.AppFileResource) int64 {
+	present := map[string]bool{}
+	for _, file := range remoteFiles {
+		present[file.Path] = true
+	}
+
+	var size int64
+	for _, file := range localFiles {
+		if !present[file.Path] {
+			size += file.Size
+		}
+	}
+	return size
+}
+
+func (cmd *Push) saveResourceCache() {
+	err := cmd.resourceCache.Save()
+	if err != nil {
+		cmd.ui.Warn(T("Could not save the resource cache: {{.Err}}", map[string]interface{}{"Err": err.Error()}))
+	}
 }
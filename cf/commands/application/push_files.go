@@ -0,0 +1,187 @@
+package application
+
+import (
+	"github.com/cloudfoundry/cli/cf/actors"
+	"github.com/cloudfoundry/cli/cf/api/applicationbits"
+	"github.com/cloudfoundry/cli/cf/api/resources"
+	"github.com/cloudfoundry/cli/cf/appfiles"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/formatters"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/manifest"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/flags"
+)
+
+type PushFiles struct {
+	ui           terminal.UI
+	config       coreconfig.Reader
+	manifestRepo manifest.ManifestRepository
+	appfiles     appfiles.AppFiles
+	actor        actors.PushActor
+	appBitsRepo  applicationbits.ApplicationBitsRepository
+}
+
+func init() {
+	commandregistry.Register(&PushFiles{})
+}
+
+func (cmd *PushFiles) MetaData() commandregistry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["p"] = &flags.StringFlag{ShortName: "p", Usage: T("Path to app directory or to a zip file of the contents of the app directory (Default: the app's path in the manifest, or the current directory)")}
+	fs["use-gitignore"] = &flags.BoolFlag{Name: "use-gitignore", Usage: T("Also ignore the files listed in .gitignore files. Patterns in .cfignore files take precedence")}
+	fs["var"] = &flags.StringSliceFlag{Name: "var", Usage: T("Variable key value pair for variable substitution in the manifest (e.g. name=app1). This flag can be defined more than once.")}
+	fs["vars-file"] = &flags.StringSliceFlag{Name: "vars-file", Usage: T("Path to a YAML file of variables for variable substitution in the manifest. This flag can be defined more than once.")}
+
+	return commandregistry.CommandMetadata{
+		Name:        "push-files",
+		Description: T("List the files push would upload for an app, with their sizes and SHA1s and whether the server already has them"),
+		Usage: []string{
+			T("CF_NAME push-files APP_NAME [-p PATH] [--use-gitignore] [--var KEY=VALUE] [--vars-file VARS_FILE_PATH]"),
+		},
+		Flags: fs,
+	}
+}
+
+func (cmd *PushFiles) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
+	if len(fc.Args()) != 1 {
+		cmd.ui.Failed(T("Incorrect Usage. Requires an argument\n\n") + commandregistry.Commands.CommandUsage("push-files"))
+	}
+
+	reqs := []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+	}
+
+	return reqs
+}
+
+func (cmd *PushFiles) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	cmd.ui = deps.UI
+	cmd.config = deps.Config
+	cmd.manifestRepo = deps.ManifestRepo
+	cmd.appfiles = deps.AppFiles
+	cmd.actor = deps.PushActor
+	cmd.appBitsRepo = deps.RepoLocator.GetApplicationBitsRepository()
+	return cmd
+}
+
+func (cmd *PushFiles) Execute(c flags.FlagContext) {
+	appName := c.Args()[0]
+
+	path := c.String("p")
+	if path == "" {
+		path = cmd.manifestAppPath(c, appName)
+	}
+
+	appFiles := cmd.appfiles
+	if c.Bool("use-gitignore") {
+		appFiles = appFiles.WithGitignore()
+	}
+
+	cmd.ui.Say(T("Listing files to upload for app {{.AppName}} from {{.Path}} as {{.Username}}...",
+		map[string]interface{}{
+			"AppName":  terminal.EntityNameColor(appName),
+			"Path":     terminal.EntityNameColor(path),
+			"Username": terminal.EntityNameColor(cmd.config.Username()),
+		}))
+
+	err := cmd.actor.ProcessPath(path, func(appDir string) {
+		cmd.listFiles(appFiles, appDir)
+	})
+	if err != nil {
+		cmd.ui.Failed(T("Error processing app files: {{.Error}}", map[string]interface{}{"Error": err.Error()}))
+	}
+}
+
+// manifestAppPath returns the path the manifest in the current directory
+// gives for the app, or the current directory when there is none. Its
+// variables are substituted as push does.
+func (cmd *PushFiles) manifestAppPath(c flags.FlagContext, appName string) string {
+	m, err := readManifest(cmd.manifestRepo, nil)
+	if err != nil {
+		if m.Path == "" {
+			return "."
+		}
+		cmd.ui.Failed(T("Error reading manifest file:\n{{.Err}}", map[string]interface{}{"Err": err.Error()}))
+	}
+
+	m.Variables, err = getManifestVariables(c)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	apps, err := m.Applications()
+	if err != nil {
+		cmd.ui.Failed(T("Error reading manifest file:\n{{.Err}}", map[string]interface{}{"Err": err.Error()}))
+	}
+
+	for _, app := range apps {
+		if app.Name != nil && *app.Name == appName && app.Path != nil {
+			return *app.Path
+		}
+	}
+	return "."
+}
+
+func (cmd *PushFiles) listFiles(appFiles appfiles.AppFiles, appDir string) {
+	localFiles, err := appFiles.AppFilesInDir(appDir, nil)
+	if err != nil {
+		cmd.ui.Failed(T("Error processing app files in '{{.Path}}': {{.Error}}",
+			map[string]interface{}{"Path": appDir, "Error": err.Error()}))
+	}
+
+	files := []models.AppFileFields{}
+	fileResources := []resources.AppFileResource{}
+	for _, file := range localFiles {
+		// directories are listed with a SHA1 of "0"
+		if file.Sha1 == "0" {
+			continue
+		}
+		files = append(files, file)
+		fileResources = append(fileResources, resources.AppFileResource{Path: file.Path, Sha1: file.Sha1, Size: file.Size})
+	}
+
+	if len(files) == 0 {
+		cmd.ui.Failed(T("No app files found in '{{.Path}}'", map[string]interface{}{"Path": appDir}))
+	}
+
+	matchedFiles, err := cmd.appBitsRepo.GetApplicationFiles(fileResources)
+	if err != nil {
+		cmd.ui.Failed(T("Could not find out which files the server has: {{.Err}}", map[string]interface{}{"Err": err.Error()}))
+	}
+
+	onServer := map[string]bool{}
+	for _, file := range matchedFiles {
+		onServer[file.Path] = true
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+
+	var totalBytes, newBytes int64
+	table := cmd.ui.Table([]string{T("path"), T("size"), T("sha1"), T("on server")})
+	table.SetFieldNames("path", "size", "sha1", "on_server")
+	for _, file := range files {
+		status := T("no")
+		if onServer[file.Path] {
+			status = T("yes")
+		} else {
+			newBytes += file.Size
+		}
+		totalBytes += file.Size
+
+		table.Add(file.Path, formatters.ByteSize(file.Size), file.Sha1, status)
+	}
+	table.Print()
+
+	cmd.ui.Say("")
+	cmd.ui.Say(T("{{.FileCount}} files, {{.TotalBytes}} in total, {{.NewBytes}} not on the server yet",
+		map[string]interface{}{
+			"FileCount":  len(files),
+			"TotalBytes": formatters.ByteSize(totalBytes),
+			"NewBytes":   formatters.ByteSize(newBytes),
+		}))
+}
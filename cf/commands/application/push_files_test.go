@@ -0,0 +1,173 @@
+package application_test
+
+import (
+	"errors"
+	"path/filepath"
+
+	"github.com/cloudfoundry/cli/cf/actors/actorsfakes"
+	"github.com/cloudfoundry/cli/cf/api/applicationbits/applicationbitsfakes"
+	"github.com/cloudfoundry/cli/cf/api/resources"
+	"github.com/cloudfoundry/cli/cf/appfiles/appfilesfakes"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/generic"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testmanifest "github.com/cloudfoundry/cli/testhelpers/manifest"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("push-files", func() {
+	var (
+		ui                  *testterm.FakeUI
+		requirementsFactory *testreq.FakeReqFactory
+		manifestRepo        *testmanifest.FakeManifestRepository
+		appFiles            *appfilesfakes.FakeAppFiles
+		actor               *actorsfakes.FakePushActor
+		appBitsRepo         *applicationbitsfakes.FakeApplicationBitsRepository
+		deps                commandregistry.Dependency
+	)
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true}
+		manifestRepo = &testmanifest.FakeManifestRepository{}
+
+		appFiles = new(appfilesfakes.FakeAppFiles)
+		appFiles.AppFilesInDirReturns([]models.AppFileFields{
+			{Path: "app.rb", Sha1: "app-sha", Size: 2048},
+			{Path: "node_modules", Sha1: "0", Size: 0},
+			{Path: "node_modules/left-pad.js", Sha1: "left-pad-sha", Size: 1024},
+		}, nil)
+
+		actor = new(actorsfakes.FakePushActor)
+		actor.ProcessPathStub = func(dirOrZipFile string, f func(string)) error {
+			f(dirOrZipFile)
+			return nil
+		}
+
+		appBitsRepo = new(applicationbitsfakes.FakeApplicationBitsRepository)
+		appBitsRepo.GetApplicationFilesReturns([]resources.AppFileResource{
+			{Path: "node_modules/left-pad.js", Sha1: "left-pad-sha", Size: 1024},
+		}, nil)
+	})
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.UI = ui
+		deps.Config = testconfig.NewRepositoryWithDefaults()
+		deps.ManifestRepo = manifestRepo
+		deps.AppFiles = appFiles
+		deps.PushActor = actor
+		deps.RepoLocator = deps.RepoLocator.SetApplicationBitsRepository(appBitsRepo)
+		commandregistry.Commands.SetCommand(commandregistry.Commands.FindCommand("push-files").SetDependency(deps, pluginCall))
+	}
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCLICommand("push-files", args, requirementsFactory, updateCommandDependency, false)
+	}
+
+	Describe("requirements", func() {
+		It("fails when not logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand("my-app")).To(BeFalse())
+		})
+
+		It("fails when there is not exactly one argument", func() {
+			Expect(runCommand()).To(BeFalse())
+			Expect(runCommand("my-app", "other-app")).To(BeFalse())
+		})
+	})
+
+	It("lists the files that would be uploaded and whether the server has them", func() {
+		runCommand("-p", "some/dir", "my-app")
+
+		Expect(actor.ProcessPathCallCount()).To(Equal(1))
+		path, _ := actor.ProcessPathArgsForCall(0)
+		Expect(path).To(Equal("some/dir"))
+
+		Expect(appBitsRepo.GetApplicationFilesCallCount()).To(Equal(1))
+		Expect(appBitsRepo.GetApplicationFilesArgsForCall(0)).To(Equal([]resources.AppFileResource{
+			{Path: "app.rb", Sha1: "app-sha", Size: 2048},
+			{Path: "node_modules/left-pad.js", Sha1: "left-pad-sha", Size: 1024},
+		}))
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Listing files to upload for app", "my-app", "some/dir", "my-user"},
+			[]string{"OK"},
+			[]string{"path", "size", "sha1", "on server"},
+			[]string{"app.rb", "2K", "app-sha", "no"},
+			[]string{"node_modules/left-pad.js", "1K", "left-pad-sha", "yes"},
+			[]string{"2 files, 3K in total, 2K not on the server yet"},
+		))
+		Expect(ui.Outputs).ToNot(ContainSubstrings([]string{"node_modules", "0"}))
+	})
+
+	It("uses the app's path from the manifest when no path is given", func() {
+		manifestRepo.ReadManifestReturns.Manifest = singleAppManifest()
+
+		runCommand("manifest-app-name")
+
+		path, _ := actor.ProcessPathArgsForCall(0)
+		Expect(path).To(HaveSuffix(filepath.Clean("some/path/from/manifest")))
+	})
+
+	It("substitutes the variables of the manifest from --var", func() {
+		m := singleAppManifest()
+		app := m.Data.Get("applications").([]interface{})[0].(generic.Map)
+		app.Set("path", "((path))")
+		manifestRepo.ReadManifestReturns.Manifest = m
+
+		runCommand("--var", "path=var/path", "manifest-app-name")
+
+		path, _ := actor.ProcessPathArgsForCall(0)
+		Expect(path).To(HaveSuffix(filepath.Clean("var/path")))
+	})
+
+	It("uses the current directory when the manifest does not list the app", func() {
+		manifestRepo.ReadManifestReturns.Manifest = singleAppManifest()
+
+		runCommand("my-app")
+
+		path, _ := actor.ProcessPathArgsForCall(0)
+		Expect(path).To(Equal("."))
+	})
+
+	It("also honors .gitignore files when --use-gitignore is given", func() {
+		gitignoreFiles := new(appfilesfakes.FakeAppFiles)
+		gitignoreFiles.AppFilesInDirReturns([]models.AppFileFields{{Path: "app.rb", Sha1: "app-sha", Size: 2048}}, nil)
+		appFiles.WithGitignoreReturns(gitignoreFiles)
+
+		runCommand("--use-gitignore", "my-app")
+
+		Expect(gitignoreFiles.AppFilesInDirCallCount()).To(Equal(1))
+		Expect(appFiles.AppFilesInDirCallCount()).To(BeZero())
+	})
+
+	It("fails when the server cannot be asked which files it has", func() {
+		appBitsRepo.GetApplicationFilesReturns(nil, errors.New("resource match failed"))
+
+		runCommand("my-app")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"resource match failed"},
+		))
+	})
+
+	It("fails when there are no files to upload", func() {
+		appFiles.AppFilesInDirReturns([]models.AppFileFields{}, nil)
+
+		runCommand("my-app")
+
+		Expect(appBitsRepo.GetApplicationFilesCallCount()).To(BeZero())
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"No app files found"},
+		))
+	})
+})
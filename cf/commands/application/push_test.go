@@ -2,6 +2,7 @@ package application_test
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"syscall"
@@ -12,6 +13,7 @@ import (
 	"github.com/cloudfoundry/cli/cf/api/authentication/authenticationfakes"
 	"github.com/cloudfoundry/cli/cf/api/resources"
 	"github.com/cloudfoundry/cli/cf/api/stacks/stacksfakes"
+	cfappfiles "github.com/cloudfoundry/cli/cf/appfiles"
 	"github.com/cloudfoundry/cli/cf/appfiles/appfilesfakes"
 	"github.com/cloudfoundry/cli/cf/commandregistry"
 	"github.com/cloudfoundry/cli/cf/commands/application"
@@ -47,6 +49,7 @@ var _ = Describe("Push Command", func() {
 		stopper                    *applicationfakes.FakeApplicationStopper
 		serviceBinder              *servicefakes.OldFakeAppBinder
 		appRepo                    *applicationsfakes.FakeApplicationRepository
+		appSummaryRepo             *apifakes.FakeAppSummaryRepository
 		domainRepo                 *apifakes.FakeDomainRepository
 		routeRepo                  *apifakes.FakeRouteRepository
 		stackRepo                  *stacksfakes.FakeStackRepository
@@ -68,6 +71,7 @@ var _ = Describe("Push Command", func() {
 		deps.Config = configRepo
 		deps.ManifestRepo = manifestRepo
 		deps.RepoLocator = deps.RepoLocator.SetApplicationRepository(appRepo)
+		deps.RepoLocator = deps.RepoLocator.SetAppSummaryRepository(appSummaryRepo)
 		deps.RepoLocator = deps.RepoLocator.SetDomainRepository(domainRepo)
 		deps.RepoLocator = deps.RepoLocator.SetRouteRepository(routeRepo)
 		deps.RepoLocator = deps.RepoLocator.SetServiceRepository(serviceRepo)
@@ -105,6 +109,7 @@ var _ = Describe("Push Command", func() {
 		stopper.MetaDataReturns(commandregistry.CommandMetadata{Name: "stop"})
 
 		appRepo = new(applicationsfakes.FakeApplicationRepository)
+		appSummaryRepo = new(apifakes.FakeAppSummaryRepository)
 
 		domainRepo = new(apifakes.FakeDomainRepository)
 		sharedDomain := maker.NewSharedDomainFields(maker.Overrides{"name": "foo.cf-app.com", "guid": "foo-domain-guid"})
@@ -467,7 +472,7 @@ var _ = Describe("Push Command", func() {
 				Expect(boundAppGUID).To(Equal("app-name-guid"))
 				Expect(boundRouteGUID).To(Equal("app-name-route-guid"))
 
-				appGUID, _, _ := actor.UploadAppArgsForCall(0)
+				appGUID, _, _, _ := actor.UploadAppArgsForCall(0)
 				Expect(appGUID).To(Equal("app-name-guid"))
 
 				Expect(ui.Outputs).To(ContainSubstrings(
@@ -577,7 +582,7 @@ var _ = Describe("Push Command", func() {
 				Expect(boundAppGUID).To(Equal("app-name-guid"))
 				Expect(boundRouteGUID).To(Equal("my-hostname-route-guid"))
 
-				appGUID, _, _ := actor.UploadAppArgsForCall(0)
+				appGUID, _, _, _ := actor.UploadAppArgsForCall(0)
 				Expect(appGUID).To(Equal("app-name-guid"))
 
 				Expect(starter.ApplicationStartCallCount()).To(Equal(0))
@@ -842,7 +847,7 @@ var _ = Describe("Push Command", func() {
 				appfiles.AppFilesInDirReturns(expectedLocalFiles, nil)
 				callPush("-p", "../some/path-to/an-app/file.zip", "app-with-path")
 
-				actualLocalFiles, _, _ := actor.GatherFilesArgsForCall(0)
+				actualLocalFiles, _, _, _ := actor.GatherFilesArgsForCall(0)
 				Expect(actualLocalFiles).To(Equal(expectedLocalFiles))
 			})
 
@@ -867,7 +872,7 @@ var _ = Describe("Push Command", func() {
 			It("pushes the contents of the app directory or zip file specified using the -p flag", func() {
 				callPush("-p", "../some/path-to/an-app/file.zip", "app-with-path")
 
-				_, appDir, _ := actor.GatherFilesArgsForCall(0)
+				_, appDir, _, _ := actor.GatherFilesArgsForCall(0)
 				Expect(appDir).To(Equal("../some/path-to/an-app/file.zip"))
 			})
 
@@ -875,10 +880,69 @@ var _ = Describe("Push Command", func() {
 				callPush("app-with-default-path")
 				dir, _ := os.Getwd()
 
-				_, appDir, _ := actor.GatherFilesArgsForCall(0)
+				_, appDir, _, _ := actor.GatherFilesArgsForCall(0)
 				Expect(appDir).To(Equal(dir))
 			})
 
+			Context("when there is a resource cache", func() {
+				var cacheDir string
+
+				BeforeEach(func() {
+					var err error
+					cacheDir, err = ioutil.TempDir("", "resource-cache")
+					Expect(err).NotTo(HaveOccurred())
+					deps.ResourceCache = cfappfiles.NewResourceCache(filepath.Join(cacheDir, "resource_cache.json"))
+				})
+
+				AfterEach(func() {
+					deps.ResourceCache = nil
+					os.RemoveAll(cacheDir)
+				})
+
+				It("hashes and matches the app files using the cache and saves it after uploading", func() {
+					callPush("app-with-default-path")
+
+					_, hashCache := appfiles.AppFilesInDirArgsForCall(0)
+					Expect(hashCache).NotTo(BeNil())
+					_, _, _, matchCache := actor.GatherFilesArgsForCall(0)
+					Expect(matchCache == hashCache).To(BeTrue())
+
+					Expect(filepath.Join(cacheDir, "resource_cache.json")).To(BeARegularFile())
+				})
+
+				It("retries the upload once without the cached matches when it fails", func() {
+					actor.GatherFilesStub = func(localFiles []models.AppFileFields, appDir string, uploadDir string, cache *cfappfiles.AppResourceCache) ([]resources.AppFileResource, bool, error) {
+						// the second time, the match must have been forgotten
+						Expect(cache.HasMatches()).To(BeFalse())
+						cache.SetMatched([]string{"stale-sha"})
+						return []resources.AppFileResource{}, false, nil
+					}
+					actor.UploadAppStub = func(appGUID string, uploadDir string, presentFiles []resources.AppFileResource, onRetry func(attempt int, maxAttempts int)) error {
+						if actor.UploadAppCallCount() == 1 {
+							return errors.New("resource not found")
+						}
+						return nil
+					}
+
+					callPush("app-with-default-path")
+
+					Expect(actor.UploadAppCallCount()).To(Equal(2))
+					Expect(ui.Outputs).To(ContainSubstrings([]string{"Retrying upload without the cached resource matches"}))
+					Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"FAILED"}))
+				})
+
+				It("does not use the cache when --no-cache is given", func() {
+					callPush("--no-cache", "app-with-default-path")
+
+					_, hashCache := appfiles.AppFilesInDirArgsForCall(0)
+					Expect(hashCache).To(BeNil())
+					_, _, _, matchCache := actor.GatherFilesArgsForCall(0)
+					Expect(matchCache).To(BeNil())
+
+					Expect(filepath.Join(cacheDir, "resource_cache.json")).NotTo(BeAnExistingFile())
+				})
+			})
+
 			It("fails when given a bad manifest path", func() {
 				manifestRepo.ReadManifestReturns.Manifest = manifest.NewEmptyManifest()
 				manifestRepo.ReadManifestReturns.Error = errors.New("read manifest error")
@@ -919,6 +983,16 @@ var _ = Describe("Push Command", func() {
 				Expect(manifestRepo.ReadManifestArgs.Path).To(Equal(cwd))
 			})
 
+			It("reads and merges all of the manifests when -f is given more than once", func() {
+				manifestRepo.ReadManifestReturns.Manifest = singleAppManifest()
+
+				callPush("-f", "base.yml", "-f", "overlay.yml")
+
+				Expect(manifestRepo.ReadManifestsArgs.Paths).To(Equal([]string{"base.yml", "overlay.yml"}))
+				params := appRepo.CreateArgsForCall(0)
+				Expect(*params.Name).To(Equal("manifest-app-name"))
+			})
+
 			It("does not use a manifest if the 'no-manifest' flag is passed", func() {
 				callPush("--no-route", "--no-manifest", "app-name")
 
@@ -964,6 +1038,42 @@ var _ = Describe("Push Command", func() {
 				}))
 			})
 
+			Context("when the manifest contains variables", func() {
+				BeforeEach(func() {
+					m := singleAppManifest()
+					app := m.Data.Get("applications").([]interface{})[0].(generic.Map)
+					app.Set("instances", "((instances))")
+					app.Set("host", "((host))")
+					manifestRepo.ReadManifestReturns.Manifest = m
+				})
+
+				It("substitutes the values from --var and --vars-file", func() {
+					callPush("--vars-file", filepath.Join("..", "..", "..", "fixtures", "manifests", "vars.yml"), "--var", "host=var-host")
+
+					params := appRepo.CreateArgsForCall(0)
+					Expect(*params.InstanceCount).To(Equal(3))
+					Expect(*params.Hosts).To(Equal([]string{"var-host"}))
+				})
+
+				It("fails listing the variables that were not provided", func() {
+					callPush("--var", "host=var-host")
+
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"FAILED"},
+						[]string{"((instances)) at applications[0].instances"},
+					))
+				})
+
+				It("fails when a --var is malformed", func() {
+					callPush("--var", "no-equals-sign")
+
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"FAILED"},
+						[]string{"Invalid variable 'no-equals-sign'"},
+					))
+				})
+			})
+
 			It("pushes an app with multiple routes when multiple hosts are provided", func() {
 				domainRepo.FindByNameInOrgReturns(models.DomainFields{
 					Name: "manifest-example.com",
@@ -985,6 +1095,22 @@ var _ = Describe("Push Command", func() {
 				))
 			})
 
+			It("fails without making any API calls when the manifest is invalid", func() {
+				m := singleAppManifest()
+				m.Data.Get("applications").([]interface{})[0].(generic.Map).Set("instance", 3)
+				manifestRepo.ReadManifestReturns.Manifest = m
+
+				callPush()
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"FAILED"},
+					[]string{"Error validating manifest file"},
+					[]string{"Unknown property 'applications[0].instance'"},
+				))
+				Expect(authRepo.RefreshAuthTokenCallCount()).To(Equal(0))
+				Expect(appRepo.ReadCallCount()).To(Equal(0))
+			})
+
 			It("fails when parsing the manifest has errors", func() {
 				manifestRepo.ReadManifestReturns.Manifest = &manifest.Manifest{Path: "/some-path/"}
 				manifestRepo.ReadManifestReturns.Error = errors.New("buildpack should not be null")
@@ -1096,10 +1222,318 @@ var _ = Describe("Push Command", func() {
 					Expect(ui.Outputs).To(ContainSubstrings([]string{"FAILED"}))
 					Expect(appRepo.CreateCallCount()).To(BeZero())
 				})
+
+				Context("when --parallel is given", func() {
+					It("pushes the apps at the same time and summarizes the results", func() {
+						callPush("--parallel", "2")
+
+						Expect(ui.Outputs).To(ContainSubstrings(
+							[]string{"app1 |", "Creating", "app1"},
+							[]string{"app2 |", "Creating", "app2"},
+							[]string{"app", "status", "details"},
+							[]string{"app1", "pushed"},
+							[]string{"app2", "pushed"},
+						))
+						Expect(ui.Outputs).ToNot(ContainSubstrings([]string{"FAILED"}))
+						Expect(appRepo.CreateCallCount()).To(Equal(2))
+						Expect(starter.ApplicationStartCallCount()).To(Equal(2))
+					})
+
+					It("pushes the remaining apps when one fails, then fails", func() {
+						appRepo.CreateStub = func(params models.AppParams) (models.Application, error) {
+							if *params.Name == "app1" {
+								return models.Application{}, errors.New("app1 could not be created")
+							}
+							return maker.NewApp(maker.Overrides{"name": *params.Name}), nil
+						}
+
+						callPush("--parallel", "2")
+
+						Expect(ui.Outputs).To(ContainSubstrings(
+							[]string{"app1 |", "FAILED"},
+							[]string{"app1", "failed", "app1 could not be created"},
+							[]string{"app2", "pushed"},
+							[]string{"FAILED"},
+							[]string{"1 of 2 apps failed to push"},
+						))
+						Expect(starter.ApplicationStartCallCount()).To(Equal(1))
+					})
+
+					It("fails when the count is not a positive integer", func() {
+						callPush("--parallel", "0")
+
+						Expect(ui.Outputs).To(ContainSubstrings(
+							[]string{"FAILED"},
+							[]string{"Invalid parallel count: 0"},
+						))
+						Expect(appRepo.CreateCallCount()).To(BeZero())
+					})
+				})
 			})
 		})
 	})
 
+	Describe("--dry-run", func() {
+		var existingApp models.Application
+
+		BeforeEach(func() {
+			existingApp = models.Application{}
+			existingApp.Name = "existing-app"
+			existingApp.GUID = "existing-app-guid"
+			existingApp.Memory = 256
+			existingApp.InstanceCount = 2
+			existingApp.EnvironmentVars = map[string]interface{}{"FOO": "bar"}
+
+			appSummaryRepo.GetSummaryReturns(models.Application{
+				Routes: []models.RouteSummary{
+					{Host: "existing-app", Domain: models.DomainFields{Name: "foo.cf-app.com"}},
+				},
+			}, nil)
+		})
+
+		It("shows the changes to an existing app without making them", func() {
+			appRepo.ReadReturns(existingApp, nil)
+
+			callPush("--dry-run", "--no-manifest", "-m", "512M", "-i", "2", "-n", "new-host", "existing-app")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Dry run"},
+				[]string{"existing-app", "would be updated"},
+				[]string{"~ memory: 256M -> 512M"},
+				[]string{"+ route: new-host.foo.cf-app.com"},
+			))
+			Expect(ui.Outputs).ToNot(ContainSubstrings([]string{"instances"}))
+			Expect(ui.Outputs).ToNot(ContainSubstrings([]string{"route: existing-app.foo.cf-app.com"}))
+
+			Expect(appSummaryRepo.GetSummaryArgsForCall(0)).To(Equal("existing-app-guid"))
+			Expect(appRepo.UpdateCallCount()).To(BeZero())
+			Expect(routeRepo.CreateCallCount()).To(BeZero())
+			Expect(actor.ProcessPathCallCount()).To(BeZero())
+			Expect(stopper.ApplicationStopCallCount()).To(BeZero())
+			Expect(starter.ApplicationStartCallCount()).To(BeZero())
+		})
+
+		It("shows the routes that would be removed by --no-route", func() {
+			appRepo.ReadReturns(existingApp, nil)
+
+			callPush("--dry-run", "--no-manifest", "--no-route", "existing-app")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"- route: existing-app.foo.cf-app.com"},
+			))
+			Expect(routeRepo.UnbindCallCount()).To(BeZero())
+		})
+
+		It("shows the properties of an app that would be created", func() {
+			appRepo.ReadReturns(models.Application{}, errors.NewModelNotFoundError("App", "new-app"))
+
+			callPush("--dry-run", "--no-manifest", "-m", "512M", "new-app")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"new-app", "would be created"},
+				[]string{"+ memory: 512M"},
+				[]string{"+ route: new-app.foo.cf-app.com"},
+			))
+			Expect(appSummaryRepo.GetSummaryCallCount()).To(BeZero())
+			Expect(appRepo.CreateCallCount()).To(BeZero())
+		})
+
+		It("shows the env vars and service bindings that would be added", func() {
+			manifestRepo.ReadManifestReturns.Manifest = manifestWithServicesAndEnv()
+			appRepo.ReadReturns(existingApp, nil)
+
+			callPush("--dry-run", "app1")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"+ env: SOMETHING"},
+				[]string{"+ service binding: app1-service"},
+				[]string{"+ service binding: global-service"},
+			))
+			Expect(serviceBinder.AppsToBind).To(BeEmpty())
+		})
+	})
+
+	Describe("--strategy blue-green", func() {
+		var liveApp models.Application
+
+		BeforeEach(func() {
+			liveApp = models.Application{}
+			liveApp.Name = "live-app"
+			liveApp.GUID = "live-app-guid"
+			liveApp.Memory = 256
+			liveApp.EnvironmentVars = map[string]interface{}{"FOO": "bar"}
+
+			appRepo.ReadStub = func(name string) (models.Application, error) {
+				if name == "live-app" {
+					return liveApp, nil
+				}
+				return models.Application{}, errors.NewModelNotFoundError("App", name)
+			}
+			appRepo.CreateStub = func(params models.AppParams) (models.Application, error) {
+				app := models.Application{}
+				app.Name = *params.Name
+				app.GUID = "new-app-guid"
+				app.State = "stopped"
+				return app, nil
+			}
+			appSummaryRepo.GetSummaryStub = func(appGUID string) (models.Application, error) {
+				summary := models.Application{
+					Routes: []models.RouteSummary{
+						{GUID: "route-guid", Host: "live-app", Domain: models.DomainFields{Name: "foo.cf-app.com"}},
+					},
+				}
+				if appGUID == "live-app-guid" {
+					summary.Services = []models.ServicePlanSummary{{Name: "live-service"}}
+				}
+				return summary, nil
+			}
+		})
+
+		It("pushes a new app, moves the routes to it and deletes the old app", func() {
+			callPush("--no-manifest", "--strategy", "blue-green", "-i", "3", "live-app")
+
+			Expect(appRepo.CreateCallCount()).To(Equal(1))
+			params := appRepo.CreateArgsForCall(0)
+			Expect(*params.Name).To(Equal("live-app-new"))
+			Expect(*params.Memory).To(Equal(int64(256)))
+			Expect(*params.InstanceCount).To(Equal(3))
+			Expect(*params.EnvironmentVars).To(Equal(map[string]interface{}{"FOO": "bar"}))
+			Expect(*params.ServicesToBind).To(Equal([]string{"live-service"}))
+
+			Expect(starter.SetWaitForAllInstancesCallCount()).To(Equal(2))
+			Expect(starter.SetWaitForAllInstancesArgsForCall(0)).To(BeTrue())
+			Expect(starter.SetWaitForAllInstancesArgsForCall(1)).To(BeFalse())
+			Expect(starter.ApplicationStartCallCount()).To(Equal(1))
+
+			Expect(routeRepo.BindCallCount()).To(Equal(1))
+			routeGUID, appGUID := routeRepo.BindArgsForCall(0)
+			Expect(routeGUID).To(Equal("route-guid"))
+			Expect(appGUID).To(Equal("new-app-guid"))
+
+			Expect(routeRepo.UnbindCallCount()).To(Equal(1))
+			routeGUID, appGUID = routeRepo.UnbindArgsForCall(0)
+			Expect(routeGUID).To(Equal("route-guid"))
+			Expect(appGUID).To(Equal("live-app-guid"))
+
+			Expect(appRepo.UpdateCallCount()).To(Equal(2))
+			appGUID, params = appRepo.UpdateArgsForCall(0)
+			Expect(appGUID).To(Equal("live-app-guid"))
+			Expect(*params.Name).To(Equal("live-app-old"))
+			appGUID, params = appRepo.UpdateArgsForCall(1)
+			Expect(appGUID).To(Equal("new-app-guid"))
+			Expect(*params.Name).To(Equal("live-app"))
+
+			Expect(appRepo.DeleteCallCount()).To(Equal(1))
+			Expect(appRepo.DeleteArgsForCall(0)).To(Equal("live-app-guid"))
+		})
+
+		It("keeps the old app when --keep-old-app is given", func() {
+			callPush("--no-manifest", "--strategy", "blue-green", "--keep-old-app", "live-app")
+
+			Expect(appRepo.UpdateCallCount()).To(Equal(2))
+			Expect(appRepo.DeleteCallCount()).To(BeZero())
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"kept as", "live-app-old"}))
+		})
+
+		It("rolls back the route changes when a later step fails", func() {
+			appRepo.UpdateStub = func(appGUID string, params models.AppParams) (models.Application, error) {
+				if params.Name != nil && *params.Name == "live-app-old" {
+					return models.Application{}, errors.New("rename failed")
+				}
+				return models.Application{}, nil
+			}
+
+			callPush("--no-manifest", "--strategy", "blue-green", "live-app")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"rename failed"},
+				[]string{"Rolling back"},
+			))
+
+			Expect(routeRepo.BindCallCount()).To(Equal(2))
+			routeGUID, appGUID := routeRepo.BindArgsForCall(1)
+			Expect(routeGUID).To(Equal("route-guid"))
+			Expect(appGUID).To(Equal("live-app-guid"))
+
+			Expect(routeRepo.UnbindCallCount()).To(Equal(2))
+			routeGUID, appGUID = routeRepo.UnbindArgsForCall(1)
+			Expect(routeGUID).To(Equal("route-guid"))
+			Expect(appGUID).To(Equal("new-app-guid"))
+
+			Expect(appRepo.DeleteCallCount()).To(Equal(1))
+			Expect(appRepo.DeleteArgsForCall(0)).To(Equal("new-app-guid"))
+		})
+
+		It("deletes the new app when it cannot be started", func() {
+			starter.ApplicationStartStub = func(app models.Application, orgName string, spaceName string) (models.Application, error) {
+				ui.Failed("start failed")
+				return models.Application{}, nil
+			}
+
+			callPush("--no-manifest", "--strategy", "blue-green", "live-app")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Rolling back"},
+			))
+			Expect(appRepo.DeleteCallCount()).To(Equal(1))
+			Expect(appRepo.DeleteArgsForCall(0)).To(Equal("new-app-guid"))
+			Expect(routeRepo.BindCallCount()).To(BeZero())
+		})
+
+		It("restores whether starting apps waits for all instances", func() {
+			starter.SetWaitForAllInstancesReturns(true)
+
+			callPush("--no-manifest", "--strategy", "blue-green", "live-app")
+
+			Expect(starter.SetWaitForAllInstancesCallCount()).To(Equal(2))
+			Expect(starter.SetWaitForAllInstancesArgsForCall(1)).To(BeTrue())
+		})
+
+		It("refuses to start when an app with the old app's name exists", func() {
+			appRepo.ReadStub = func(name string) (models.Application, error) {
+				return liveApp, nil
+			}
+
+			callPush("--no-manifest", "--strategy", "blue-green", "live-app")
+
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"App live-app-old already exists"}))
+			Expect(appRepo.CreateCallCount()).To(BeZero())
+		})
+
+		It("refuses to start when an app with the new app's name exists", func() {
+			appRepo.ReadStub = func(name string) (models.Application, error) {
+				if name == "live-app" || name == "live-app-new" {
+					return models.Application{Name: name, GUID: name + "-guid"}, nil
+				}
+				return models.Application{}, errors.NewModelNotFoundError("App", name)
+			}
+
+			callPush("--no-manifest", "--strategy", "blue-green", "live-app")
+
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"App live-app-new already exists"}))
+			Expect(appRepo.CreateCallCount()).To(BeZero())
+			Expect(appRepo.UpdateCallCount()).To(BeZero())
+			Expect(appRepo.DeleteCallCount()).To(BeZero())
+		})
+
+		It("pushes as usual when the app does not exist yet", func() {
+			callPush("--no-manifest", "--strategy", "blue-green", "other-app")
+
+			Expect(appRepo.CreateCallCount()).To(Equal(1))
+			Expect(*appRepo.CreateArgsForCall(0).Name).To(Equal("other-app"))
+			Expect(appRepo.DeleteCallCount()).To(BeZero())
+		})
+
+		It("fails with an unknown strategy", func() {
+			callPush("--no-manifest", "--strategy", "sideways", "live-app")
+
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"Invalid strategy: sideways"}))
+			Expect(appRepo.CreateCallCount()).To(BeZero())
+		})
+	})
+
 	Describe("re-pushing an existing app", func() {
 		var existingApp models.Application
 
@@ -1166,7 +1600,7 @@ var _ = Describe("Push Command", func() {
 			Expect(orgName).To(Equal(configRepo.OrganizationFields().Name))
 			Expect(spaceName).To(Equal(configRepo.SpaceFields().Name))
 
-			appGUID, _, _ := actor.UploadAppArgsForCall(0)
+			appGUID, _, _, _ := actor.UploadAppArgsForCall(0)
 			Expect(appGUID).To(Equal(existingApp.GUID))
 		})
 
@@ -1260,7 +1694,7 @@ var _ = Describe("Push Command", func() {
 					It("does not add a route to the app", func() {
 						callPush("existing-app")
 
-						appGUID, _, _ := actor.UploadAppArgsForCall(0)
+						appGUID, _, _, _ := actor.UploadAppArgsForCall(0)
 						Expect(appGUID).To(Equal("existing-app-guid"))
 						Expect(domainRepo.FindByNameInOrgCallCount()).To(BeZero())
 						Expect(routeRepo.FindCallCount()).To(BeZero())
@@ -1336,7 +1770,7 @@ var _ = Describe("Push Command", func() {
 			It("removes the route when the --no-route flag is given", func() {
 				callPush("--no-route", "existing-app")
 
-				appGUID, _, _ := actor.UploadAppArgsForCall(0)
+				appGUID, _, _, _ := actor.UploadAppArgsForCall(0)
 				Expect(appGUID).To(Equal("existing-app-guid"))
 
 				Expect(domainRepo.FindByNameInOrgCallCount()).To(BeZero())
@@ -1472,8 +1906,12 @@ var _ = Describe("Push Command", func() {
 	Describe("displaying information about files being uploaded", func() {
 		It("displays information about the files being uploaded", func() {
 			appfiles.CountFilesReturns(11)
-			zipper.ZipReturns(nil)
-			zipper.GetZipSizeReturns(6100000, nil)
+			appfiles.AppFilesInDirReturns([]models.AppFileFields{
+				{Path: "path/to/app", Size: 100},
+				{Path: "bar", Size: 200},
+				{Path: "big-file", Size: 6000000},
+				{Path: "other-file", Size: 100000},
+			}, nil)
 			actor.GatherFilesReturns([]resources.AppFileResource{resources.AppFileResource{Path: "path/to/app"}, resources.AppFileResource{Path: "bar"}}, true, nil)
 
 			curDir, err := os.Getwd()
@@ -1487,6 +1925,69 @@ var _ = Describe("Push Command", func() {
 		})
 	})
 
+	It("uploads the directory of files the server does not have", func() {
+		actor.GatherFilesReturns([]resources.AppFileResource{{Path: "bar"}}, true, nil)
+
+		callPush("appName")
+
+		_, uploadDir, presentFiles, _ := actor.UploadAppArgsForCall(0)
+		Expect(uploadDir).NotTo(BeEmpty())
+		Expect(presentFiles).To(Equal([]resources.AppFileResource{{Path: "bar"}}))
+	})
+
+	It("uploads no files when the server has all of them", func() {
+		actor.GatherFilesReturns([]resources.AppFileResource{{Path: "bar"}}, false, nil)
+
+		callPush("appName")
+
+		_, uploadDir, _, _ := actor.UploadAppArgsForCall(0)
+		Expect(uploadDir).To(BeEmpty())
+	})
+
+	It("lists the ignored files when --show-ignored is given", func() {
+		appfiles.IgnoredFilesInDirReturns([]cfappfiles.IgnoredFile{
+			{Path: "tmp/", Ignored: true, Rule: cfappfiles.IgnoreRule{Source: ".cfignore", Line: 2, Pattern: "tmp/"}},
+			{Path: "tmp.rb", Ignored: false, Rule: cfappfiles.IgnoreRule{Source: ".cfignore", Line: 3, Pattern: "!tmp.rb"}},
+		}, nil)
+
+		callPush("--show-ignored", "appName")
+
+		Expect(appfiles.IgnoredFilesInDirCallCount()).To(Equal(1))
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"path", "status", "rule"},
+			[]string{"tmp/", "ignored", ".cfignore:2: tmp/"},
+			[]string{"tmp.rb", "included", ".cfignore:3: !tmp.rb"},
+		))
+	})
+
+	It("does not list the ignored files by default", func() {
+		callPush("appName")
+
+		Expect(appfiles.IgnoredFilesInDirCallCount()).To(BeZero())
+	})
+
+	It("also honors .gitignore files when --use-gitignore is given", func() {
+		gitignoreFiles := new(appfilesfakes.FakeAppFiles)
+		gitignoreFiles.AppFilesInDirReturns([]models.AppFileFields{{Path: "app.rb", Size: 10}}, nil)
+		appfiles.WithGitignoreReturns(gitignoreFiles)
+
+		callPush("--use-gitignore", "appName")
+
+		Expect(gitignoreFiles.AppFilesInDirCallCount()).To(Equal(1))
+		Expect(appfiles.AppFilesInDirCallCount()).To(BeZero())
+	})
+
+	It("tells the user when the upload is retried", func() {
+		actor.UploadAppStub = func(appGUID string, uploadDir string, presentFiles []resources.AppFileResource, onRetry func(attempt int, maxAttempts int)) error {
+			onRetry(2, 5)
+			return nil
+		}
+
+		callPush("appName")
+
+		Expect(ui.Outputs).To(ContainSubstrings([]string{"retrying upload (attempt 2/5)"}))
+	})
+
 	It("fails when the app can't be uploaded", func() {
 		actor.UploadAppReturns(errors.New("Boom!"))
 
@@ -0,0 +1,221 @@
+package application
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf/api/appinstances"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/flags"
+)
+
+const DefaultRollingRestartWaitTimeout = 5 * time.Minute
+
+type RollingRestart struct {
+	ui               terminal.UI
+	config           coreconfig.Reader
+	appReq           requirements.ApplicationRequirement
+	appInstancesRepo appinstances.AppInstancesRepository
+
+	PingerThrottle time.Duration
+}
+
+func init() {
+	commandregistry.Register(&RollingRestart{})
+}
+
+func (cmd *RollingRestart) MetaData() commandregistry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["batch-size"] = &flags.IntFlag{Name: "batch-size", Usage: T("Number of instances to restart at a time (Default: 1)")}
+	fs["wait-timeout"] = &flags.IntFlag{Name: "wait-timeout", Usage: T("Maximum time (in seconds) to wait for a batch of restarted instances to be running (Default: 300)")}
+
+	return commandregistry.CommandMetadata{
+		Name:        "rolling-restart",
+		Description: T("Restart the instances of an app in batches, waiting for each batch to be running before restarting the next"),
+		Usage: []string{
+			T("CF_NAME rolling-restart APP_NAME [--batch-size NUM_INSTANCES] [--wait-timeout TIMEOUT]"),
+		},
+		Flags: fs,
+	}
+}
+
+func (cmd *RollingRestart) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
+	if len(fc.Args()) != 1 {
+		cmd.ui.Failed(T("Incorrect Usage. Requires an argument\n\n") + commandregistry.Commands.CommandUsage("rolling-restart"))
+	}
+
+	cmd.appReq = requirementsFactory.NewApplicationRequirement(fc.Args()[0])
+
+	reqs := []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+		requirementsFactory.NewTargetedSpaceRequirement(),
+		cmd.appReq,
+	}
+
+	return reqs
+}
+
+func (cmd *RollingRestart) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	cmd.ui = deps.UI
+	cmd.config = deps.Config
+	cmd.appInstancesRepo = deps.RepoLocator.GetAppInstancesRepository()
+	cmd.PingerThrottle = DefaultPingerThrottle
+	return cmd
+}
+
+func (cmd *RollingRestart) Execute(fc flags.FlagContext) {
+	app := cmd.appReq.GetApplication()
+
+	batchSize := 1
+	if fc.IsSet("batch-size") {
+		batchSize = fc.Int("batch-size")
+		if batchSize < 1 {
+			cmd.ui.Failed(T("Invalid batch size: {{.BatchSize}}\nBatch size must be a positive integer",
+				map[string]interface{}{"BatchSize": batchSize}))
+		}
+	}
+
+	waitTimeout := DefaultRollingRestartWaitTimeout
+	if fc.IsSet("wait-timeout") {
+		seconds := fc.Int("wait-timeout")
+		if seconds < 1 {
+			cmd.ui.Failed(T("Invalid wait timeout: {{.Timeout}}\nWait timeout must be a positive number of seconds",
+				map[string]interface{}{"Timeout": seconds}))
+		}
+		waitTimeout = time.Duration(seconds) * time.Second
+	}
+
+	instances, err := cmd.appInstancesRepo.GetInstances(app.GUID)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	cmd.ui.Say(T("Restarting app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}, {{.BatchSize}} instance(s) at a time...",
+		map[string]interface{}{
+			"AppName":   terminal.EntityNameColor(app.Name),
+			"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+			"SpaceName": terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+			"Username":  terminal.EntityNameColor(cmd.config.Username()),
+			"BatchSize": batchSize,
+		}))
+	cmd.ui.Say("")
+
+	total := len(instances)
+	for first := 0; first < total; first += batchSize {
+		last := first + batchSize
+		if last > total {
+			last = total
+		}
+
+		batch := make([]int, 0, last-first)
+		for index := first; index < last; index++ {
+			batch = append(batch, index)
+		}
+
+		cmd.ui.Say(T("Restarting instance(s) {{.Instances}}...",
+			map[string]interface{}{"Instances": formatInstanceIndexes(batch)}))
+
+		for _, index := range batch {
+			err = cmd.appInstancesRepo.DeleteInstance(app.GUID, index)
+			if err != nil {
+				cmd.ui.Failed(T("Could not restart instance {{.Instance}}: {{.Err}}\n\n{{.Report}}",
+					map[string]interface{}{
+						"Instance": index,
+						"Err":      err.Error(),
+						"Report":   rollingRestartReport(first, total),
+					}))
+			}
+		}
+
+		failed := cmd.waitForBatch(app, batch, instances, waitTimeout)
+		if len(failed) > 0 {
+			lines := []string{T("Instance(s) {{.Instances}} of {{.AppName}} did not come up after restarting:",
+				map[string]interface{}{"Instances": formatInstanceIndexes(batch), "AppName": app.Name})}
+			lines = append(lines, failed...)
+			lines = append(lines, "", rollingRestartReport(first, total))
+
+			cmd.ui.Failed(strings.Join(lines, "\n"))
+		}
+
+		cmd.ui.Ok()
+		cmd.ui.Say("")
+	}
+
+	cmd.ui.Say(T("Restarted all {{.Count}} instance(s) of {{.AppName}}",
+		map[string]interface{}{"Count": total, "AppName": terminal.EntityNameColor(app.Name)}))
+}
+
+// waitForBatch polls the instances of app until each one in batch has been
+// replaced, i.e. started since the time given in previous, and is running.
+// It returns a description of each instance that is not, if one of them
+// crashes or the timeout passes first.
+func (cmd *RollingRestart) waitForBatch(app models.Application, batch []int, previous []models.AppInstanceFields, timeout time.Duration) []string {
+	deadline := time.Now().Add(timeout)
+	pending := []string{T("  the instances could not be fetched")}
+
+	for {
+		instances, err := cmd.appInstancesRepo.GetInstances(app.GUID)
+		if err != nil {
+			cmd.ui.Warn(T("Could not fetch instances: {{.Err}}", map[string]interface{}{"Err": err.Error()}))
+		} else {
+			pending = nil
+			crashed := false
+			for _, index := range batch {
+				status, isRunning, isCrashed := replacedInstanceStatus(index, instances, previous)
+				if !isRunning {
+					pending = append(pending, status)
+				}
+				crashed = crashed || isCrashed
+			}
+
+			if len(pending) == 0 || crashed {
+				return pending
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return pending
+		}
+
+		time.Sleep(cmd.PingerThrottle)
+	}
+}
+
+func replacedInstanceStatus(index int, instances []models.AppInstanceFields, previous []models.AppInstanceFields) (status string, running bool, crashed bool) {
+	if index >= len(instances) {
+		return fmt.Sprintf("  #%d: %s", index, T("not reported")), false, false
+	}
+
+	instance := instances[index]
+	if index < len(previous) && !instance.Since.After(previous[index].Since) {
+		return fmt.Sprintf("  #%d: %s", index, T("not replaced yet")), false, false
+	}
+
+	status = fmt.Sprintf("  #%d: %s", index, instance.State)
+	if instance.Details != "" {
+		status = fmt.Sprintf("%s (%s)", status, instance.Details)
+	}
+
+	crashed = instance.State == models.InstanceCrashed || instance.State == models.InstanceFlapping
+	return status, instance.State == models.InstanceRunning, crashed
+}
+
+func rollingRestartReport(restarted int, total int) string {
+	return T("Rolling restart aborted: {{.Restarted}} of {{.Total}} instance(s) were restarted before this batch.",
+		map[string]interface{}{"Restarted": restarted, "Total": total})
+}
+
+func formatInstanceIndexes(indexes []int) string {
+	formatted := make([]string, len(indexes))
+	for i, index := range indexes {
+		formatted[i] = strconv.Itoa(index)
+	}
+	return strings.Join(formatted, ", ")
+}
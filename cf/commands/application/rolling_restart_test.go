@@ -0,0 +1,213 @@
+package application_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf/api/appinstances/appinstancesfakes"
+	. "github.com/cloudfoundry/cli/cf/commands/application"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/models"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("rolling-restart", func() {
+	var (
+		ui                  *testterm.FakeUI
+		config              coreconfig.Repository
+		appInstancesRepo    *appinstancesfakes.FakeAppInstancesRepository
+		requirementsFactory *testreq.FakeReqFactory
+		application         models.Application
+		deps                commandregistry.Dependency
+
+		startedAt time.Time
+		restarted map[int]models.InstanceState
+	)
+
+	BeforeEach(func() {
+		application = models.Application{}
+		application.Name = "my-app"
+		application.GUID = "my-app-guid"
+		application.InstanceCount = 3
+
+		ui = &testterm.FakeUI{}
+		config = testconfig.NewRepositoryWithDefaults()
+		requirementsFactory = &testreq.FakeReqFactory{
+			LoginSuccess:         true,
+			TargetedSpaceSuccess: true,
+			Application:          application,
+		}
+
+		startedAt = time.Now().Add(-time.Hour)
+		restarted = map[int]models.InstanceState{}
+
+		appInstancesRepo = new(appinstancesfakes.FakeAppInstancesRepository)
+		appInstancesRepo.GetInstancesStub = func(appGUID string) ([]models.AppInstanceFields, error) {
+			instances := make([]models.AppInstanceFields, application.InstanceCount)
+			for index := range instances {
+				instances[index] = models.AppInstanceFields{State: models.InstanceRunning, Since: startedAt}
+				if state, ok := restarted[index]; ok {
+					instances[index] = models.AppInstanceFields{State: state, Since: startedAt.Add(time.Minute)}
+				}
+			}
+			return instances, nil
+		}
+		appInstancesRepo.DeleteInstanceStub = func(appGUID string, index int) error {
+			restarted[index] = models.InstanceRunning
+			return nil
+		}
+	})
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.UI = ui
+		deps.Config = config
+		deps.RepoLocator = deps.RepoLocator.SetAppInstancesRepository(appInstancesRepo)
+		commandregistry.Commands.SetCommand(commandregistry.Commands.FindCommand("rolling-restart").SetDependency(deps, pluginCall))
+		commandregistry.Commands.FindCommand("rolling-restart").(*RollingRestart).PingerThrottle = time.Millisecond
+	}
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCLICommand("rolling-restart", args, requirementsFactory, updateCommandDependency, false)
+	}
+
+	Describe("requirements", func() {
+		It("fails if not logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand("my-app")).To(BeFalse())
+		})
+
+		It("fails if a space is not targeted", func() {
+			requirementsFactory.TargetedSpaceSuccess = false
+			Expect(runCommand("my-app")).To(BeFalse())
+		})
+
+		It("fails when there is not exactly one argument", func() {
+			Expect(runCommand()).To(BeFalse())
+			Expect(runCommand("my-app", "other-app")).To(BeFalse())
+		})
+	})
+
+	It("restarts one instance at a time by default", func() {
+		runCommand("my-app")
+
+		Expect(appInstancesRepo.DeleteInstanceCallCount()).To(Equal(3))
+		for i := 0; i < 3; i++ {
+			appGUID, index := appInstancesRepo.DeleteInstanceArgsForCall(i)
+			Expect(appGUID).To(Equal("my-app-guid"))
+			Expect(index).To(Equal(i))
+		}
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Restarting app", "my-app", "1 instance(s) at a time"},
+			[]string{"Restarting instance(s) 0..."},
+			[]string{"OK"},
+			[]string{"Restarting instance(s) 1..."},
+			[]string{"OK"},
+			[]string{"Restarting instance(s) 2..."},
+			[]string{"OK"},
+			[]string{"Restarted all 3 instance(s) of my-app"},
+		))
+	})
+
+	It("restarts the instances in batches of the given size", func() {
+		runCommand("--batch-size", "2", "my-app")
+
+		Expect(appInstancesRepo.DeleteInstanceCallCount()).To(Equal(3))
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Restarting instance(s) 0, 1..."},
+			[]string{"Restarting instance(s) 2..."},
+			[]string{"Restarted all 3 instance(s) of my-app"},
+		))
+	})
+
+	It("waits for the restarted instances to be running before restarting the next batch", func() {
+		polls := 0
+		appInstancesRepo.DeleteInstanceStub = func(appGUID string, index int) error {
+			restarted[index] = models.InstanceStarting
+			return nil
+		}
+		getInstances := appInstancesRepo.GetInstancesStub
+		appInstancesRepo.GetInstancesStub = func(appGUID string) ([]models.AppInstanceFields, error) {
+			polls++
+			if polls%3 == 0 {
+				for index := range restarted {
+					restarted[index] = models.InstanceRunning
+				}
+			}
+			return getInstances(appGUID)
+		}
+
+		runCommand("my-app")
+
+		Expect(appInstancesRepo.DeleteInstanceCallCount()).To(Equal(3))
+		Expect(polls).To(BeNumerically(">", 3))
+		Expect(ui.Outputs).To(ContainSubstrings([]string{"Restarted all 3 instance(s) of my-app"}))
+	})
+
+	It("aborts with a report when a restarted instance crashes", func() {
+		appInstancesRepo.DeleteInstanceStub = func(appGUID string, index int) error {
+			restarted[index] = models.InstanceRunning
+			if index == 1 {
+				restarted[index] = models.InstanceCrashed
+			}
+			return nil
+		}
+
+		runCommand("my-app")
+
+		Expect(appInstancesRepo.DeleteInstanceCallCount()).To(Equal(2))
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"Instance(s) 1 of my-app did not come up after restarting"},
+			[]string{"#1: crashed"},
+			[]string{"Rolling restart aborted: 1 of 3 instance(s) were restarted before this batch."},
+		))
+		Expect(ui.Outputs).ToNot(ContainSubstrings([]string{"Restarted all"}))
+	})
+
+	It("aborts when the restarted instances are not running within the wait timeout", func() {
+		appInstancesRepo.DeleteInstanceStub = func(appGUID string, index int) error {
+			return nil
+		}
+
+		runCommand("--wait-timeout", "1", "my-app")
+
+		Expect(appInstancesRepo.DeleteInstanceCallCount()).To(Equal(1))
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"#0: not replaced yet"},
+			[]string{"Rolling restart aborted: 0 of 3 instance(s) were restarted before this batch."},
+		))
+	})
+
+	It("aborts when an instance cannot be restarted", func() {
+		appInstancesRepo.DeleteInstanceStub = nil
+		appInstancesRepo.DeleteInstanceReturns(errors.New("deletion failed"))
+
+		runCommand("my-app")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"Could not restart instance 0: deletion failed"},
+			[]string{"Rolling restart aborted: 0 of 3 instance(s) were restarted before this batch."},
+		))
+	})
+
+	It("fails when the batch size is not a positive number", func() {
+		runCommand("--batch-size", "0", "my-app")
+
+		Expect(appInstancesRepo.DeleteInstanceCallCount()).To(Equal(0))
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"Invalid batch size: 0"},
+		))
+	})
+})
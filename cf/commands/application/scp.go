@@ -0,0 +1,173 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/commands"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/net"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	sshCmd "github.com/cloudfoundry/cli/cf/ssh"
+	"github.com/cloudfoundry/cli/cf/ssh/options"
+	"github.com/cloudfoundry/cli/cf/ssh/terminal"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/flags"
+)
+
+type SCP struct {
+	ui            terminal.UI
+	config        coreconfig.Reader
+	gateway       net.Gateway
+	appReq        requirements.ApplicationRequirement
+	sshCodeGetter commands.SSHCodeGetter
+	opts          *options.SCPOptions
+	secureShell   sshCmd.SecureShell
+}
+
+func init() {
+	commandregistry.Register(&SCP{})
+}
+
+func (cmd *SCP) MetaData() commandregistry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["recursive"] = &flags.BoolFlag{Name: "recursive", ShortName: "r", Usage: T("Copy directories and their contents")}
+	fs["preserve"] = &flags.BoolFlag{Name: "preserve", ShortName: "p", Usage: T("Preserve the exact permissions and modification times of the files")}
+	fs["skip-host-validation"] = &flags.BoolFlag{Name: "skip-host-validation", ShortName: "k", Usage: T("Skip host key validation")}
+
+	return commandregistry.CommandMetadata{
+		Name:        "scp",
+		Description: T("Copy files between the local machine and an application container instance"),
+		Usage: []string{
+			T("CF_NAME scp APP_NAME[:INDEX]:REMOTE_PATH LOCAL_PATH [-r] [-p] [--skip-host-validation]\n"),
+			T("   CF_NAME scp LOCAL_PATH APP_NAME[:INDEX]:REMOTE_PATH [-r] [-p] [--skip-host-validation]"),
+		},
+		Examples: []string{
+			"CF_NAME scp my-app:1:/home/vcap/app/heap.hprof heap.hprof",
+			"CF_NAME scp -r config my-app:app/config",
+		},
+		Flags: fs,
+	}
+}
+
+func (cmd *SCP) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
+	if len(fc.Args()) != 2 {
+		cmd.ui.Failed(T("Incorrect Usage. Requires SOURCE and TARGET as arguments") + "\n\n" + commandregistry.Commands.CommandUsage("scp"))
+	}
+
+	var err error
+	cmd.opts, err = options.NewSCPOptions(fc)
+	if err != nil {
+		cmd.ui.Failed(fmt.Sprintf(T("Incorrect Usage:")+" %s\n\n%s", err.Error(), commandregistry.Commands.CommandUsage("scp")))
+	}
+
+	cmd.appReq = requirementsFactory.NewApplicationRequirement(cmd.opts.AppName)
+
+	reqs := []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+		requirementsFactory.NewTargetedSpaceRequirement(),
+		cmd.appReq,
+	}
+
+	return reqs
+}
+
+func (cmd *SCP) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	cmd.ui = deps.UI
+	cmd.config = deps.Config
+	cmd.gateway = deps.Gateways["cloud-controller"]
+
+	if deps.WildcardDependency != nil {
+		cmd.secureShell = deps.WildcardDependency.(sshCmd.SecureShell)
+	}
+
+	//get ssh-code for dependency
+	sshCodeGetter := commandregistry.Commands.FindCommand("ssh-code")
+	sshCodeGetter = sshCodeGetter.SetDependency(deps, false)
+	cmd.sshCodeGetter = sshCodeGetter.(commands.SSHCodeGetter)
+
+	return cmd
+}
+
+func (cmd *SCP) Execute(fc flags.FlagContext) {
+	app := cmd.appReq.GetApplication()
+
+	info := sshInfo{}
+	err := cmd.gateway.GetResource(cmd.config.APIEndpoint()+"/v2/info", &info)
+	if err != nil {
+		cmd.ui.Failed(T("Error getting SSH info:") + err.Error())
+	}
+
+	sshAuthCode, err := cmd.sshCodeGetter.Get()
+	if err != nil {
+		cmd.ui.Failed(T("Error getting one time auth code: ") + err.Error())
+	}
+
+	//init secureShell if it is not already set by SetDependency() with fakes
+	if cmd.secureShell == nil {
+		cmd.secureShell = sshCmd.NewSecureShell(
+			sshCmd.DefaultSecureDialer(),
+			sshTerminal.DefaultHelper(),
+			sshCmd.DefaultListenerFactory(),
+			30*time.Second,
+			app,
+			info.SSHEndpointFingerprint,
+			info.SSHEndpoint,
+			sshAuthCode,
+		)
+	}
+
+	remote := fmt.Sprintf("%s:%d:%s", cmd.opts.AppName, cmd.opts.Index, cmd.opts.RemotePath)
+	source, target := remote, cmd.opts.LocalPath
+	if cmd.opts.Upload {
+		source, target = target, source
+	}
+	cmd.ui.Say(T("Copying {{.Source}} to {{.Target}}...", map[string]interface{}{
+		"Source": terminal.EntityNameColor(source),
+		"Target": terminal.EntityNameColor(target),
+	}))
+
+	err = cmd.secureShell.Connect(cmd.opts.SSHOptions())
+	if err != nil {
+		cmd.ui.Failed(T("Error opening SSH connection: ") + err.Error())
+	}
+	defer cmd.secureShell.Close()
+
+	err = cmd.secureShell.SecureCopy(cmd.opts, cmd.showProgress)
+	if err != nil {
+		cmd.ui.Failed(T("Error copying files: ") + err.Error())
+	}
+
+	cmd.ui.Ok()
+}
+
+// showProgress shows how much of a file has been copied while it is copied.
+func (cmd *SCP) showProgress(name string, content io.Reader, size int64) io.Reader {
+	readSeeker, ok := content.(io.ReadSeeker)
+	if !ok {
+		readSeeker = unseekableReader{content}
+	}
+
+	progressReader := net.NewProgressReader(readSeeker, cmd.ui, 5*time.Second)
+	progressReader.SetTotalSize(size)
+	progressReader.SetMessages(
+		T("Copying {{.Name}}...", map[string]interface{}{"Name": strings.Replace(name, "%", "%%", -1)})+" %s",
+		T("Copied {{.Name}}", map[string]interface{}{"Name": name}),
+	)
+	return progressReader
+}
+
+// unseekableReader lets a stream, like a file being downloaded, be read by a
+// ProgressReader.
+type unseekableReader struct {
+	io.Reader
+}
+
+func (r unseekableReader) Seek(offset int64, whence int) (int64, error) {
+	return 0, errors.New("cannot seek in a stream")
+}
@@ -0,0 +1,172 @@
+package application_test
+
+import (
+	"errors"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry/cli/cf/api/apifakes"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/commands/commandsfakes"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/net"
+	"github.com/cloudfoundry/cli/cf/ssh/sshfakes"
+	"github.com/cloudfoundry/cli/testhelpers/cloudcontrollergateway"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testnet "github.com/cloudfoundry/cli/testhelpers/net"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("scp command", func() {
+	var (
+		ui *testterm.FakeUI
+
+		sshCodeGetter         *commandsfakes.FakeSSHCodeGetter
+		originalSSHCodeGetter commandregistry.Command
+
+		requirementsFactory *testreq.FakeReqFactory
+		configRepo          coreconfig.Repository
+		deps                commandregistry.Dependency
+
+		fakeSecureShell *sshfakes.FakeSecureShell
+		testServer      *httptest.Server
+	)
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		configRepo = testconfig.NewRepositoryWithDefaults()
+		requirementsFactory = &testreq.FakeReqFactory{
+			LoginSuccess:         true,
+			TargetedSpaceSuccess: true,
+			Application: models.Application{
+				ApplicationFields: models.ApplicationFields{
+					Name:  "my-app",
+					GUID:  "my-app-guid",
+					State: "started",
+					Diego: true,
+				},
+			},
+		}
+		deps.Gateways = make(map[string]net.Gateway)
+
+		originalSSHCodeGetter = commandregistry.Commands.FindCommand("ssh-code")
+
+		sshCodeGetter = new(commandsfakes.FakeSSHCodeGetter)
+		sshCodeGetter.SetDependencyStub = func(_ commandregistry.Dependency, _ bool) commandregistry.Command {
+			return sshCodeGetter
+		}
+		sshCodeGetter.MetaDataReturns(commandregistry.CommandMetadata{Name: "ssh-code"})
+
+		fakeSecureShell = new(sshfakes.FakeSecureShell)
+		deps.WildcardDependency = fakeSecureShell
+
+		getRequest := apifakes.NewCloudControllerTestRequest(testnet.TestRequest{
+			Method: "GET",
+			Path:   "/v2/info",
+			Response: testnet.TestResponse{
+				Status: 200,
+				Body:   getInfoResponseBody,
+			},
+		})
+
+		testServer, _ = testnet.NewServer([]testnet.TestRequest{getRequest})
+		configRepo.SetAPIEndpoint(testServer.URL)
+		deps.Gateways["cloud-controller"] = cloudcontrollergateway.NewTestCloudControllerGateway(configRepo)
+	})
+
+	AfterEach(func() {
+		testServer.Close()
+		commandregistry.Register(originalSSHCodeGetter)
+	})
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.UI = ui
+		deps.Config = configRepo
+
+		commandregistry.Register(sshCodeGetter)
+
+		commandregistry.Commands.SetCommand(commandregistry.Commands.FindCommand("scp").SetDependency(deps, pluginCall))
+	}
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCLICommand("scp", args, requirementsFactory, updateCommandDependency, false)
+	}
+
+	Describe("Requirements", func() {
+		It("fails with usage when not provided a source and a target", func() {
+			Expect(runCommand("my-app:heap.hprof")).To(BeFalse())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires SOURCE and TARGET"},
+			))
+		})
+
+		It("fails with usage when neither path is on an app instance", func() {
+			Expect(runCommand("heap.hprof", "./dumps")).To(BeFalse())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "must be on an app instance"},
+				[]string{"USAGE:"},
+			))
+		})
+
+		It("fails requirements when not logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand("my-app:heap.hprof", "heap.hprof")).To(BeFalse())
+		})
+
+		It("fails if the application is not found", func() {
+			requirementsFactory.ApplicationFails = true
+			Expect(runCommand("my-app:heap.hprof", "heap.hprof")).To(BeFalse())
+		})
+	})
+
+	It("connects to the app instance and copies the files", func() {
+		Expect(runCommand("-r", "my-app:2:/home/vcap/app/config", "config")).To(BeTrue())
+
+		Expect(fakeSecureShell.ConnectCallCount()).To(Equal(1))
+		sshOpts := fakeSecureShell.ConnectArgsForCall(0)
+		Expect(sshOpts.AppName).To(Equal("my-app"))
+		Expect(sshOpts.Index).To(Equal(uint(2)))
+
+		Expect(fakeSecureShell.SecureCopyCallCount()).To(Equal(1))
+		opts, progress := fakeSecureShell.SecureCopyArgsForCall(0)
+		Expect(opts.RemotePath).To(Equal("/home/vcap/app/config"))
+		Expect(opts.LocalPath).To(Equal("config"))
+		Expect(opts.Recursive).To(BeTrue())
+		Expect(opts.Upload).To(BeFalse())
+		Expect(progress).NotTo(BeNil())
+
+		Expect(fakeSecureShell.CloseCallCount()).To(Equal(1))
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Copying", "my-app:2:/home/vcap/app/config", "to", "config"},
+			[]string{"OK"},
+		))
+	})
+
+	It("notifies users when connecting fails", func() {
+		fakeSecureShell.ConnectReturns(errors.New("dial error"))
+
+		runCommand("heap.hprof", "my-app:heap.hprof")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Error opening SSH connection", "dial error"},
+		))
+		Expect(fakeSecureShell.SecureCopyCallCount()).To(Equal(0))
+	})
+
+	It("notifies users when copying fails", func() {
+		fakeSecureShell.SecureCopyReturns(errors.New("scp: heap.hprof: Permission denied"))
+
+		runCommand("heap.hprof", "my-app:heap.hprof")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"Error copying files", "Permission denied"},
+		))
+	})
+})
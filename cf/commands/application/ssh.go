@@ -1,16 +1,22 @@
 package application
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"sync"
 	"time"
 
 	"golang.org/x/crypto/ssh"
 
+	"github.com/cloudfoundry/cli/cf/api/appinstances"
 	"github.com/cloudfoundry/cli/cf/commandregistry"
 	"github.com/cloudfoundry/cli/cf/commands"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
 	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
 	"github.com/cloudfoundry/cli/cf/net"
 	"github.com/cloudfoundry/cli/cf/requirements"
 	sshCmd "github.com/cloudfoundry/cli/cf/ssh"
@@ -21,13 +27,15 @@ import (
 )
 
 type SSH struct {
-	ui            terminal.UI
-	config        coreconfig.Reader
-	gateway       net.Gateway
-	appReq        requirements.ApplicationRequirement
-	sshCodeGetter commands.SSHCodeGetter
-	opts          *options.SSHOptions
-	secureShell   sshCmd.SecureShell
+	ui               terminal.UI
+	config           coreconfig.Reader
+	gateway          net.Gateway
+	appReq           requirements.ApplicationRequirement
+	appInstancesRepo appinstances.AppInstancesRepository
+	sshCodeGetter    commands.SSHCodeGetter
+	opts             *options.SSHOptions
+	allInstances     bool
+	secureShell      sshCmd.SecureShell
 }
 
 type sshInfo struct {
@@ -42,8 +50,11 @@ func init() {
 func (cmd *SSH) MetaData() commandregistry.CommandMetadata {
 	fs := make(map[string]flags.FlagSet)
 	fs["L"] = &flags.StringSliceFlag{ShortName: "L", Usage: T("Local port forward specification. This flag can be defined more than once.")}
+	fs["R"] = &flags.StringSliceFlag{ShortName: "R", Usage: T("Remote port forward specification, listening in the app container. This flag can be defined more than once.")}
+	fs["D"] = &flags.StringSliceFlag{ShortName: "D", Usage: T("Local port of a SOCKS5 proxy connecting from the app container. This flag can be defined more than once.")}
 	fs["command"] = &flags.StringSliceFlag{Name: "command", ShortName: "c", Usage: T("Command to run. This flag can be defined more than once.")}
 	fs["app-instance-index"] = &flags.IntFlag{Name: "app-instance-index", ShortName: "i", Usage: T("Application instance index")}
+	fs["all-instances"] = &flags.BoolFlag{Name: "all-instances", Usage: T("Run the command on every instance of the application, prefixing each line of output with the instance index")}
 	fs["skip-host-validation"] = &flags.BoolFlag{Name: "skip-host-validation", ShortName: "k", Usage: T("Skip host key validation")}
 	fs["skip-remote-execution"] = &flags.BoolFlag{Name: "skip-remote-execution", ShortName: "N", Usage: T("Do not execute a remote command")}
 	fs["request-pseudo-tty"] = &flags.BoolFlag{Name: "request-pseudo-tty", ShortName: "t", Usage: T("Request pseudo-tty allocation")}
@@ -54,7 +65,11 @@ func (cmd *SSH) MetaData() commandregistry.CommandMetadata {
 		Name:        "ssh",
 		Description: T("SSH to an application container instance"),
 		Usage: []string{
-			T("CF_NAME ssh APP_NAME [-i app-instance-index] [-c command] [-L [bind_address:]port:host:hostport] [--skip-host-validation] [--skip-remote-execution] [--request-pseudo-tty] [--force-pseudo-tty] [--disable-pseudo-tty]"),
+			T("CF_NAME ssh APP_NAME [-i app-instance-index] [-c command] [-L [bind_address:]port:host:hostport] [-R [bind_address:]port:host:hostport] [-D [bind_address:]port] [--skip-host-validation] [--skip-remote-execution] [--request-pseudo-tty] [--force-pseudo-tty] [--disable-pseudo-tty]\n"),
+			T("   CF_NAME ssh APP_NAME --all-instances -c command [--skip-host-validation]"),
+		},
+		Examples: []string{
+			"CF_NAME ssh my-app --all-instances -c \"du -sh /tmp\"",
 		},
 		Flags: fs,
 	}
@@ -69,6 +84,18 @@ func (cmd *SSH) Requirements(requirementsFactory requirements.Factory, fc flags.
 		cmd.ui.Failed(fmt.Sprintf(T("Incorrect Usage:")+" %s\n\n%s", T("Value for flag 'app-instance-index' cannot be negative"), commandregistry.Commands.CommandUsage("ssh")))
 	}
 
+	cmd.allInstances = fc.Bool("all-instances")
+	if cmd.allInstances {
+		if !fc.IsSet("c") {
+			cmd.ui.Failed(fmt.Sprintf(T("Incorrect Usage:")+" %s\n\n%s", T("--all-instances requires a command to run, given with -c"), commandregistry.Commands.CommandUsage("ssh")))
+		}
+		for _, flag := range []string{"i", "L", "R", "D", "N", "t", "tt"} {
+			if fc.IsSet(flag) {
+				cmd.ui.Failed(fmt.Sprintf(T("Incorrect Usage:")+" %s\n\n%s", T("--all-instances cannot be used with -{{.Flag}}", map[string]interface{}{"Flag": flag}), commandregistry.Commands.CommandUsage("ssh")))
+			}
+		}
+	}
+
 	var err error
 	cmd.opts, err = options.NewSSHOptions(fc)
 
@@ -91,6 +118,7 @@ func (cmd *SSH) SetDependency(deps commandregistry.Dependency, pluginCall bool)
 	cmd.ui = deps.UI
 	cmd.config = deps.Config
 	cmd.gateway = deps.Gateways["cloud-controller"]
+	cmd.appInstancesRepo = deps.RepoLocator.GetAppInstancesRepository()
 
 	if deps.WildcardDependency != nil {
 		cmd.secureShell = deps.WildcardDependency.(sshCmd.SecureShell)
@@ -111,24 +139,17 @@ func (cmd *SSH) Execute(fc flags.FlagContext) {
 		cmd.ui.Failed(T("Error getting SSH info:") + err.Error())
 	}
 
+	if cmd.allInstances {
+		cmd.runOnAllInstances(app, info)
+		return
+	}
+
 	sshAuthCode, err := cmd.sshCodeGetter.Get()
 	if err != nil {
 		cmd.ui.Failed(T("Error getting one time auth code: ") + err.Error())
 	}
 
-	//init secureShell if it is not already set by SetDependency() with fakes
-	if cmd.secureShell == nil {
-		cmd.secureShell = sshCmd.NewSecureShell(
-			sshCmd.DefaultSecureDialer(),
-			sshTerminal.DefaultHelper(),
-			sshCmd.DefaultListenerFactory(),
-			30*time.Second,
-			app,
-			info.SSHEndpointFingerprint,
-			info.SSHEndpoint,
-			sshAuthCode,
-		)
-	}
+	cmd.secureShell = cmd.newSecureShell(app, info, sshAuthCode)
 
 	err = cmd.secureShell.Connect(cmd.opts)
 	if err != nil {
@@ -141,6 +162,11 @@ func (cmd *SSH) Execute(fc flags.FlagContext) {
 		cmd.ui.Failed(T("Error forwarding port: ") + err.Error())
 	}
 
+	err = cmd.secureShell.RemotePortForward()
+	if err != nil {
+		cmd.ui.Failed(T("Error forwarding remote port: ") + err.Error())
+	}
+
 	if cmd.opts.SkipRemoteExecution {
 		err = cmd.secureShell.Wait()
 	} else {
@@ -162,6 +188,159 @@ func (cmd *SSH) Execute(fc flags.FlagContext) {
 	}
 }
 
+// newSecureShell returns the secure shell set by SetDependency() with fakes,
+// or a new one for the app.
+func (cmd *SSH) newSecureShell(app models.Application, info sshInfo, sshAuthCode string) sshCmd.SecureShell {
+	if cmd.secureShell != nil {
+		return cmd.secureShell
+	}
+
+	return sshCmd.NewSecureShell(
+		sshCmd.DefaultSecureDialer(),
+		sshTerminal.DefaultHelper(),
+		sshCmd.DefaultListenerFactory(),
+		30*time.Second,
+		app,
+		info.SSHEndpointFingerprint,
+		info.SSHEndpoint,
+		sshAuthCode,
+	)
+}
+
+type instanceResult struct {
+	index      int
+	exitStatus int
+	err        error
+}
+
+// runOnAllInstances runs the command on every instance of the app at the same
+// time, each over its own connection, and prints a summary of how the command
+// exited on each of them.
+func (cmd *SSH) runOnAllInstances(app models.Application, info sshInfo) {
+	instances, err := cmd.appInstancesRepo.GetInstances(app.GUID)
+	if err != nil {
+		cmd.ui.Failed(T("Error getting instances of app {{.AppName}}: ", map[string]interface{}{"AppName": app.Name}) + err.Error())
+	}
+
+	// each connection needs its own one time auth code
+	sshAuthCodes := make([]string, len(instances))
+	for index := range instances {
+		sshAuthCodes[index], err = cmd.sshCodeGetter.Get()
+		if err != nil {
+			cmd.ui.Failed(T("Error getting one time auth code: ") + err.Error())
+		}
+	}
+
+	cmd.ui.Say(T("Running command on {{.Count}} instances of app {{.AppName}}...\n", map[string]interface{}{
+		"Count":   len(instances),
+		"AppName": terminal.EntityNameColor(app.Name),
+	}))
+
+	outputLock := &sync.Mutex{}
+	results := make([]instanceResult, len(instances))
+	wg := &sync.WaitGroup{}
+	wg.Add(len(instances))
+
+	for index := range instances {
+		go func(index int) {
+			defer wg.Done()
+
+			prefix := terminal.LogPrefixColor(strconv.Itoa(index), fmt.Sprintf("[%d] ", index))
+			stdout := &linePrefixWriter{ui: cmd.ui, lock: outputLock, prefix: prefix}
+			stderr := &linePrefixWriter{ui: cmd.ui, lock: outputLock, prefix: prefix}
+
+			results[index] = cmd.runOnInstance(app, info, sshAuthCodes[index], index, stdout, stderr)
+
+			stdout.Flush()
+			stderr.Flush()
+		}(index)
+	}
+	wg.Wait()
+
+	cmd.ui.Say("")
+	table := cmd.ui.Table([]string{T("instance"), T("exit status")})
+	failed := 0
+	for _, result := range results {
+		status := strconv.Itoa(result.exitStatus)
+		if result.err != nil {
+			status = result.err.Error()
+		}
+		if result.err != nil || result.exitStatus != 0 {
+			failed++
+		}
+		table.Add(fmt.Sprintf("#%d", result.index), status)
+	}
+	table.Print()
+
+	if failed > 0 {
+		cmd.ui.Failed(T("Command failed on {{.Failed}} of {{.Count}} instances", map[string]interface{}{
+			"Failed": failed,
+			"Count":  len(instances),
+		}))
+	}
+}
+
+func (cmd *SSH) runOnInstance(app models.Application, info sshInfo, sshAuthCode string, index int, stdout *linePrefixWriter, stderr *linePrefixWriter) instanceResult {
+	result := instanceResult{index: index}
+
+	opts := *cmd.opts
+	opts.Index = uint(index)
+
+	secureShell := cmd.newSecureShell(app, info, sshAuthCode)
+	err := secureShell.Connect(&opts)
+	if err != nil {
+		result.err = errors.New(T("Error opening SSH connection: ") + err.Error())
+		return result
+	}
+	defer secureShell.Close()
+
+	err = secureShell.RunCommand(stdout, stderr)
+	if exitError, ok := err.(*ssh.ExitError); ok {
+		result.exitStatus = exitError.ExitStatus()
+	} else if err != nil {
+		result.err = err
+	}
+	return result
+}
+
+// linePrefixWriter says each complete line written to it with a prefix.
+// Writers sharing a lock never interleave their lines.
+type linePrefixWriter struct {
+	ui     terminal.UI
+	lock   *sync.Mutex
+	prefix string
+	buffer []byte
+}
+
+func (w *linePrefixWriter) Write(p []byte) (int, error) {
+	w.buffer = append(w.buffer, p...)
+
+	for {
+		end := bytes.IndexByte(w.buffer, '\n')
+		if end < 0 {
+			break
+		}
+		w.say(string(bytes.TrimSuffix(w.buffer[:end], []byte("\r"))))
+		w.buffer = w.buffer[end+1:]
+	}
+
+	return len(p), nil
+}
+
+// Flush says what is left of an unterminated last line.
+func (w *linePrefixWriter) Flush() {
+	if len(w.buffer) > 0 {
+		w.say(string(w.buffer))
+		w.buffer = nil
+	}
+}
+
+func (w *linePrefixWriter) say(line string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.ui.Say("%s%s", w.prefix, line)
+}
+
 func (cmd *SSH) getSSHEndpointInfo() (sshInfo, error) {
 	info := sshInfo{}
 	apiErr := cmd.gateway.GetResource(cmd.config.APIEndpoint()+"/v2/info", &info)
@@ -2,15 +2,18 @@ package application_test
 
 import (
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 
 	"github.com/cloudfoundry/cli/cf/api/apifakes"
+	"github.com/cloudfoundry/cli/cf/api/appinstances/appinstancesfakes"
 	"github.com/cloudfoundry/cli/cf/commandregistry"
 	"github.com/cloudfoundry/cli/cf/commands/commandsfakes"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
 	"github.com/cloudfoundry/cli/cf/models"
 	"github.com/cloudfoundry/cli/cf/net"
+	"github.com/cloudfoundry/cli/cf/ssh/options"
 	"github.com/cloudfoundry/cli/cf/ssh/sshfakes"
 	"github.com/cloudfoundry/cli/testhelpers/cloudcontrollergateway"
 	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
@@ -134,6 +137,27 @@ var _ = Describe("SSH command", func() {
 			})
 		})
 
+		Describe("--all-instances", func() {
+			BeforeEach(func() {
+				requirementsFactory.LoginSuccess = true
+				requirementsFactory.TargetedSpaceSuccess = true
+			})
+
+			It("requires a command", func() {
+				Expect(runCommand("my-app", "--all-instances")).To(BeFalse())
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"Incorrect Usage", "requires a command"},
+				))
+			})
+
+			It("cannot be used with an instance index", func() {
+				Expect(runCommand("my-app", "--all-instances", "-c", "ls", "-i", "1")).To(BeFalse())
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"Incorrect Usage", "cannot be used with -i"},
+				))
+			})
+		})
+
 		Describe("SSHOptions", func() {
 			Context("when an error is returned during initialization", func() {
 				It("shows error and prints command usage", func() {
@@ -289,6 +313,20 @@ var _ = Describe("SSH command", func() {
 				})
 			})
 
+			Context("Error remote port forwarding when -R is provided", func() {
+				It("notifies users", func() {
+					fakeSecureShell.RemotePortForwardReturns(errors.New("tcpip-forward request denied"))
+
+					runCommand("my-app", "-R", "8000:localhost:8000")
+
+					Expect(fakeSecureShell.RemotePortForwardCallCount()).To(Equal(1))
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"Error forwarding remote port", "tcpip-forward request denied"},
+					))
+
+				})
+			})
+
 			Context("when -N is provided", func() {
 				It("calls secureShell.Wait()", func() {
 					fakeSecureShell.ConnectReturns(nil)
@@ -311,6 +349,91 @@ var _ = Describe("SSH command", func() {
 				})
 			})
 
+			Context("when --all-instances is provided", func() {
+				var appInstancesRepo *appinstancesfakes.FakeAppInstancesRepository
+
+				BeforeEach(func() {
+					appInstancesRepo = new(appinstancesfakes.FakeAppInstancesRepository)
+					appInstancesRepo.GetInstancesReturns([]models.AppInstanceFields{
+						{State: models.InstanceRunning},
+						{State: models.InstanceRunning},
+						{State: models.InstanceRunning},
+					}, nil)
+					deps.RepoLocator = deps.RepoLocator.SetAppInstancesRepository(appInstancesRepo)
+
+					fakeSecureShell.RunCommandStub = func(stdout io.Writer, stderr io.Writer) error {
+						stdout.Write([]byte("par"))
+						stdout.Write([]byte("tial\nline two\n"))
+						stderr.Write([]byte("no newline"))
+						return nil
+					}
+				})
+
+				It("connects to every instance with its own auth code", func() {
+					runCommand("my-app", "--all-instances", "-c", "ls")
+
+					Expect(appInstancesRepo.GetInstancesArgsForCall(0)).To(Equal("my-app-guid"))
+					Expect(sshCodeGetter.GetCallCount()).To(Equal(3))
+					Expect(fakeSecureShell.ConnectCallCount()).To(Equal(3))
+					Expect(fakeSecureShell.RunCommandCallCount()).To(Equal(3))
+					Expect(fakeSecureShell.CloseCallCount()).To(Equal(3))
+
+					indexes := []uint{}
+					for i := 0; i < 3; i++ {
+						opts := fakeSecureShell.ConnectArgsForCall(i)
+						Expect(opts.Command).To(Equal([]string{"ls"}))
+						indexes = append(indexes, opts.Index)
+					}
+					Expect(indexes).To(ConsistOf(uint(0), uint(1), uint(2)))
+				})
+
+				It("prefixes each whole line of output with the instance index", func() {
+					runCommand("my-app", "--all-instances", "-c", "ls")
+
+					for _, prefix := range []string{"[0] ", "[1] ", "[2] "} {
+						Expect(ui.Outputs).To(ContainSubstrings(
+							[]string{prefix + "partial"},
+							[]string{prefix + "line two"},
+						))
+						Expect(ui.Outputs).To(ContainSubstrings([]string{prefix + "no newline"}))
+					}
+				})
+
+				It("prints the exit status of every instance", func() {
+					runCommand("my-app", "--all-instances", "-c", "ls")
+
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"instance", "exit status"},
+						[]string{"#0", "0"},
+						[]string{"#1", "0"},
+						[]string{"#2", "0"},
+					))
+					Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"FAILED"}))
+				})
+
+				Context("when the command fails on an instance", func() {
+					BeforeEach(func() {
+						fakeSecureShell.ConnectStub = func(opts *options.SSHOptions) error {
+							if opts.Index == 1 {
+								return errors.New("dial error")
+							}
+							return nil
+						}
+					})
+
+					It("reports it in the summary and fails", func() {
+						runCommand("my-app", "--all-instances", "-c", "ls")
+
+						Expect(fakeSecureShell.RunCommandCallCount()).To(Equal(2))
+						Expect(ui.Outputs).To(ContainSubstrings(
+							[]string{"#1", "Error opening SSH connection", "dial error"},
+							[]string{"FAILED"},
+							[]string{"Command failed on 1 of 3 instances"},
+						))
+					})
+				})
+			})
+
 			Context("when Wait() or InteractiveSession() returns error", func() {
 
 				It("notifities users", func() {
@@ -42,6 +42,7 @@ type ApplicationStagingWatcher interface {
 type ApplicationStarter interface {
 	commandregistry.Command
 	SetStartTimeoutInSeconds(timeout int)
+	SetWaitForAllInstances(wait bool) (previous bool)
 	ApplicationStart(app models.Application, orgName string, spaceName string) (updatedApp models.Application, err error)
 }
 
@@ -58,6 +59,7 @@ type Start struct {
 	StartupTimeout             time.Duration
 	StagingTimeout             time.Duration
 	PingerThrottle             time.Duration
+	WaitForAllInstances        bool
 }
 
 func init() {
@@ -183,7 +185,7 @@ func (cmd *Start) ApplicationWatchStaging(app models.Application, orgName, space
 		cmd.ui.Failed(fmt.Sprintf("%s failed to stage within %f minutes", app.Name, cmd.StagingTimeout.Minutes()))
 	}
 
-	cmd.waitForOneRunningInstance(updatedApp)
+	cmd.waitForRunningInstances(updatedApp)
 	cmd.ui.Say(terminal.HeaderColor(T("\nApp started\n")))
 	cmd.ui.Say("")
 	cmd.ui.Ok()
@@ -217,6 +219,15 @@ func (cmd *Start) SetStartTimeoutInSeconds(timeout int) {
 	cmd.StartupTimeout = time.Duration(timeout) * time.Second
 }
 
+// SetWaitForAllInstances makes starting an app wait until all of its
+// instances are running, rather than just one of them. It returns the
+// previous setting, so that it can be restored.
+func (cmd *Start) SetWaitForAllInstances(wait bool) (previous bool) {
+	previous = cmd.WaitForAllInstances
+	cmd.WaitForAllInstances = wait
+	return previous
+}
+
 func (cmd *Start) tailStagingLogs(app models.Application, stopChan chan bool, startWait, doneWait *sync.WaitGroup) {
 	var isConnected bool
 	var isDisconnected bool
@@ -326,7 +337,7 @@ Use '{{.Command}}' for more in depth log information.`,
 	return true
 }
 
-func (cmd *Start) waitForOneRunningInstance(app models.Application) {
+func (cmd *Start) waitForRunningInstances(app models.Application) {
 	timer := time.NewTimer(cmd.StartupTimeout)
 
 	for {
@@ -348,7 +359,7 @@ func (cmd *Start) waitForOneRunningInstance(app models.Application) {
 
 			cmd.ui.Say(instancesDetails(count))
 
-			if count.running > 0 {
+			if count.running > 0 && (!cmd.WaitForAllInstances || count.running == count.total) {
 				return
 			}
 
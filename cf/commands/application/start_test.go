@@ -608,6 +608,36 @@ var _ = Describe("start command", func() {
 			})
 		})
 
+		Context("when waiting for all instances", func() {
+			AfterEach(func() {
+				commandregistry.Commands.FindCommand("start").(*Start).SetWaitForAllInstances(false)
+			})
+
+			It("keeps polling until every instance is running", func() {
+				runningInstance := models.AppInstanceFields{
+					State: models.InstanceRunning,
+				}
+				startingInstance := models.AppInstanceFields{
+					State: models.InstanceStarting,
+				}
+
+				defaultInstanceResponses = [][]models.AppInstanceFields{
+					[]models.AppInstanceFields{runningInstance, startingInstance},
+					[]models.AppInstanceFields{runningInstance, runningInstance},
+				}
+
+				updateCommandDependency(logRepo)
+				commandregistry.Commands.FindCommand("start").(*Start).SetWaitForAllInstances(true)
+
+				ui, _, _ := startAppWithInstancesAndErrors(defaultAppForStart, requirementsFactory)
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"1 of 2 instances running", "1 starting"},
+					[]string{"2 of 2 instances running"},
+					[]string{"App started"},
+				))
+			})
+		})
+
 		It("tells the user about the failure when waiting for the app to stage times out", func() {
 			defaultInstanceErrorCodes = []string{errors.NotStaged, errors.NotStaged, errors.NotStaged}
 
@@ -0,0 +1,83 @@
+package application
+
+import (
+	"fmt"
+
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/flags"
+
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/manifest"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+)
+
+type ValidateManifest struct {
+	ui           terminal.UI
+	manifestRepo manifest.ManifestRepository
+}
+
+func init() {
+	commandregistry.Register(&ValidateManifest{})
+}
+
+func (cmd *ValidateManifest) MetaData() commandregistry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["f"] = &flags.StringSliceFlag{ShortName: "f", Usage: T("Path to manifest. This flag can be defined more than once; later manifests override earlier ones.")}
+	fs["var"] = &flags.StringSliceFlag{Name: "var", Usage: T("Variable key value pair for variable substitution in the manifest (e.g. name=app1). This flag can be defined more than once.")}
+	fs["vars-file"] = &flags.StringSliceFlag{Name: "vars-file", Usage: T("Path to a YAML file of variables for variable substitution in the manifest. This flag can be defined more than once.")}
+
+	return commandregistry.CommandMetadata{
+		Name:        "validate-manifest",
+		Description: T("Check an app manifest for unknown properties and invalid values"),
+		Usage: []string{
+			"CF_NAME validate-manifest ",
+			fmt.Sprintf("[-f %s] ", T("MANIFEST_PATH")),
+			fmt.Sprintf("[--var %s] ", T("KEY=VALUE")),
+			fmt.Sprintf("[--vars-file %s]", T("VARS_FILE_PATH")),
+		},
+		Flags: fs,
+	}
+}
+
+func (cmd *ValidateManifest) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
+	usageReq := requirements.NewUsageRequirement(commandregistry.CLICommandUsagePresenter(cmd),
+		T("No argument required"),
+		func() bool {
+			return len(fc.Args()) != 0
+		},
+	)
+
+	reqs := []requirements.Requirement{
+		usageReq,
+	}
+	return reqs
+}
+
+func (cmd *ValidateManifest) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	cmd.ui = deps.UI
+	cmd.manifestRepo = deps.ManifestRepo
+	return cmd
+}
+
+func (cmd *ValidateManifest) Execute(c flags.FlagContext) {
+	m, err := readManifest(cmd.manifestRepo, c.StringSlice("f"))
+	if err != nil {
+		cmd.ui.Failed(T("Error reading manifest file:\n{{.Err}}", map[string]interface{}{"Err": err.Error()}))
+	}
+
+	cmd.ui.Say(T("Validating manifest file {{.Path}}...",
+		map[string]interface{}{"Path": terminal.EntityNameColor(m.Path)}))
+
+	m.Variables, err = getManifestVariables(c)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	err = m.Validate()
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	cmd.ui.Ok()
+}
@@ -0,0 +1,99 @@
+package application_test
+
+import (
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/errors"
+	"github.com/cloudfoundry/cli/cf/manifest"
+	"github.com/cloudfoundry/cli/generic"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testmanifest "github.com/cloudfoundry/cli/testhelpers/manifest"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("validate-manifest command", func() {
+	var (
+		ui                  *testterm.FakeUI
+		requirementsFactory *testreq.FakeReqFactory
+		manifestRepo        *testmanifest.FakeManifestRepository
+		deps                commandregistry.Dependency
+	)
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		requirementsFactory = &testreq.FakeReqFactory{}
+		manifestRepo = &testmanifest.FakeManifestRepository{}
+	})
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.UI = ui
+		deps.ManifestRepo = manifestRepo
+		commandregistry.Commands.SetCommand(commandregistry.Commands.FindCommand("validate-manifest").SetDependency(deps, pluginCall))
+	}
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCLICommand("validate-manifest", args, requirementsFactory, updateCommandDependency, false)
+	}
+
+	It("fails requirements when given arguments", func() {
+		Expect(runCommand("extra-arg")).To(BeFalse())
+	})
+
+	It("does not require a login or a targeted space", func() {
+		manifestRepo.ReadManifestReturns.Manifest = &manifest.Manifest{
+			Data: generic.NewMap(map[interface{}]interface{}{"name": "app"}),
+		}
+
+		Expect(runCommand()).To(BeTrue())
+	})
+
+	It("says OK when the manifest is valid", func() {
+		manifestRepo.ReadManifestReturns.Manifest = &manifest.Manifest{
+			Path: "manifest.yml",
+			Data: generic.NewMap(map[interface{}]interface{}{
+				"applications": []interface{}{
+					map[interface{}]interface{}{"name": "app", "instances": 2},
+				},
+			}),
+		}
+
+		Expect(runCommand("-f", "manifest.yml")).To(BeTrue())
+		Expect(manifestRepo.ReadManifestArgs.Path).To(Equal("manifest.yml"))
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Validating manifest file", "manifest.yml"},
+			[]string{"OK"},
+		))
+	})
+
+	It("fails listing the problems when the manifest is invalid", func() {
+		manifestRepo.ReadManifestReturns.Manifest = &manifest.Manifest{
+			Path: "manifest.yml",
+			Data: generic.NewMap(map[interface{}]interface{}{
+				"applications": []interface{}{
+					map[interface{}]interface{}{"name": "app", "instance": "((count))"},
+				},
+			}),
+		}
+
+		runCommand("--var", "count=2")
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"Unknown property 'applications[0].instance'"},
+		))
+	})
+
+	It("fails when the manifest cannot be read", func() {
+		manifestRepo.ReadManifestReturns.Error = errors.New("no such file")
+
+		runCommand("-f", "missing.yml")
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"Error reading manifest file"},
+			[]string{"no such file"},
+		))
+	})
+})
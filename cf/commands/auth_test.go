@@ -87,6 +87,30 @@ var _ = Describe("auth command", func() {
 			}))
 		})
 
+		It("authenticates as a client with --client-credentials", func() {
+			testcmd.RunCLICommand("auth", []string{"ci-client", "ci-secret", "--client-credentials"}, requirementsFactory, updateCommandDependency, false)
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Authenticating..."},
+				[]string{"OK"},
+			))
+			Expect(authRepo.AuthenticateCallCount()).To(Equal(0))
+			Expect(authRepo.AuthenticateClientCallCount()).To(Equal(1))
+			clientID, clientSecret := authRepo.AuthenticateClientArgsForCall(0)
+			Expect(clientID).To(Equal("ci-client"))
+			Expect(clientSecret).To(Equal("ci-secret"))
+		})
+
+		It("fails when the client credentials are rejected", func() {
+			authRepo.AuthenticateClientReturns(errors.New("Credentials were rejected, please try again."))
+			testcmd.RunCLICommand("auth", []string{"ci-client", "ci-secret", "--client-credentials"}, requirementsFactory, updateCommandDependency, false)
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Credentials were rejected"},
+			))
+		})
+
 		It("prompts users to upgrade if CLI version < min cli version requirement", func() {
 			config.SetMinCLIVersion("5.0.0")
 			config.SetMinRecommendedCLIVersion("5.5.0")
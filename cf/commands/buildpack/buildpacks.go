@@ -58,6 +58,7 @@ func (cmd *ListBuildpacks) Execute(c flags.FlagContext) {
 	cmd.ui.Say(T("Getting buildpacks...\n"))
 
 	table := cmd.ui.Table([]string{"buildpack", T("position"), T("enabled"), T("locked"), T("filename")})
+	table.SetFieldNames("name", "position", "enabled", "locked", "filename")
 	noBuildpacks := true
 
 	apiErr := cmd.buildpackRepo.ListBuildpacks(func(buildpack models.Buildpack) bool {
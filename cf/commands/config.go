@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"fmt"
 	"sort"
 
 	"github.com/cloudfoundry/cli/cf/commandregistry"
@@ -27,12 +28,14 @@ func (cmd *ConfigCommands) MetaData() commandregistry.CommandMetadata {
 	fs["trace"] = &flags.StringFlag{Name: "trace", Usage: T("Trace HTTP requests")}
 	fs["color"] = &flags.StringFlag{Name: "color", Usage: T("Enable or disable color")}
 	fs["locale"] = &flags.StringFlag{Name: "locale", Usage: T("Set default locale. If LOCALE is 'CLEAR', previous locale is deleted.")}
+	fs["credential-store"] = &flags.StringFlag{Name: "credential-store", Usage: T("Where to keep access and refresh tokens: in a file next to the config file, in a file encrypted with a passphrase, or with the credential helper cf-credential-NAME")}
 
 	return commandregistry.CommandMetadata{
 		Name:        "config",
-		Description: T("Write default values to the config"),
+		Description: T("Write default values to the config, or show them"),
 		Usage: []string{
-			T("CF_NAME config [--async-timeout TIMEOUT_IN_MINUTES] [--trace (true | false | path/to/file)] [--color (true | false)] [--locale (LOCALE | CLEAR)]"),
+			T("CF_NAME config [--async-timeout TIMEOUT_IN_MINUTES] [--trace (true | false | path/to/file)] [--color (true | false)] [--locale (LOCALE | CLEAR)] [--credential-store (file | encrypted-file | helper:NAME)]\n"),
+			T("   CF_NAME config"),
 		},
 		Flags: fs,
 	}
@@ -50,8 +53,8 @@ func (cmd *ConfigCommands) SetDependency(deps commandregistry.Dependency, plugin
 }
 
 func (cmd *ConfigCommands) Execute(context flags.FlagContext) {
-	if !context.IsSet("trace") && !context.IsSet("async-timeout") && !context.IsSet("color") && !context.IsSet("locale") {
-		cmd.ui.Failed(T("Incorrect Usage") + "\n\n" + commandregistry.Commands.CommandUsage("config"))
+	if !context.IsSet("trace") && !context.IsSet("async-timeout") && !context.IsSet("color") && !context.IsSet("locale") && !context.IsSet("credential-store") {
+		cmd.showConfig()
 		return
 	}
 
@@ -80,6 +83,13 @@ func (cmd *ConfigCommands) Execute(context flags.FlagContext) {
 		}
 	}
 
+	if context.IsSet("credential-store") {
+		err := cmd.config.SetCredentialStore(context.String("credential-store"))
+		if err != nil {
+			cmd.ui.Failed(err.Error())
+		}
+	}
+
 	if context.IsSet("locale") {
 		locale := context.String("locale")
 
@@ -105,3 +115,29 @@ func (cmd *ConfigCommands) Execute(context flags.FlagContext) {
 		cmd.ui.Failed(unsupportedLocaleMessage)
 	}
 }
+
+// showConfig shows the values of the settings and where each comes from: the
+// config file or an environment variable.
+func (cmd *ConfigCommands) showConfig() {
+	if cmd.config.IsReadOnly() {
+		cmd.ui.Say(T("Changes to the config are not saved, because {{.EnvVar}} is set.",
+			map[string]interface{}{"EnvVar": coreconfig.ReadOnlyEnvVar}))
+		cmd.ui.Say("")
+	}
+
+	table := cmd.ui.Table([]string{T("setting"), T("value"), T("source")})
+	table.SetFieldNames("setting", "value", "source")
+	table.Add("async-timeout", fmt.Sprintf("%d", cmd.config.AsyncTimeout()), cmd.valueSource("AsyncTimeout"))
+	table.Add("trace", cmd.config.Trace(), cmd.valueSource("Trace"))
+	table.Add("color", cmd.config.ColorEnabled(), cmd.valueSource("ColorEnabled"))
+	table.Add("locale", cmd.config.Locale(), cmd.valueSource("Locale"))
+	table.Add("credential-store", cmd.config.CredentialStore(), cmd.valueSource("CredentialStore"))
+	table.Print()
+}
+
+func (cmd *ConfigCommands) valueSource(field string) string {
+	if envVar := cmd.config.ValueSource(field); envVar != "" {
+		return envVar
+	}
+	return T("config file")
+}
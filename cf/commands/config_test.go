@@ -1,6 +1,8 @@
 package commands_test
 
 import (
+	"os"
+
 	"github.com/cloudfoundry/cli/cf/commandregistry"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
 	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
@@ -36,11 +38,36 @@ var _ = Describe("config command", func() {
 	runCommand := func(args ...string) {
 		testcmd.RunCLICommand("config", args, requirementsFactory, updateCommandDependency, false)
 	}
-	It("fails requirements when no flags are provided", func() {
-		runCommand()
-		Expect(ui.Outputs).To(ContainSubstrings(
-			[]string{"Incorrect Usage"},
-		))
+	Context("when no flags are provided", func() {
+		BeforeEach(func() {
+			configRepo.SetAsyncTimeout(12)
+			configRepo.SetLocale("fr-FR")
+		})
+
+		AfterEach(func() {
+			os.Unsetenv("CF_ASYNC_TIMEOUT")
+		})
+
+		It("shows the settings and that they come from the config file", func() {
+			runCommand()
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"setting", "value", "source"},
+				[]string{"async-timeout", "12", "config file"},
+				[]string{"locale", "fr-FR", "config file"},
+				[]string{"credential-store", "file", "config file"},
+			))
+			Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"Incorrect Usage"}))
+		})
+
+		It("shows the environment variable a setting comes from", func() {
+			os.Setenv("CF_ASYNC_TIMEOUT", "30")
+			configRepo = testconfig.NewRepositoryWithDefaults()
+
+			runCommand()
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"async-timeout", "30", "CF_ASYNC_TIMEOUT"},
+			))
+		})
 	})
 
 	Context("--async-timeout flag", func() {
@@ -96,6 +123,22 @@ var _ = Describe("config command", func() {
 		})
 	})
 
+	Context("--credential-store flag", func() {
+		It("stores the kind of credential store", func() {
+			runCommand("--credential-store", "helper:osxkeychain")
+			Expect(configRepo.CredentialStore()).To(Equal("helper:osxkeychain"))
+		})
+
+		It("fails when the kind of credential store is unknown", func() {
+			runCommand("--credential-store", "vault")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Unknown credential store"},
+			))
+			Expect(configRepo.CredentialStore()).To(Equal("file"))
+		})
+	})
+
 	Context("--locale flag", func() {
 		It("stores the locale value when --locale [locale] is provided", func() {
 			runCommand("--locale", "zh-Hans")
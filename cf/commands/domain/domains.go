@@ -72,6 +72,7 @@ func (cmd *ListDomains) Execute(c flags.FlagContext) {
 	}
 
 	table := cmd.ui.Table([]string{T("name"), T("status"), T("type")})
+	table.SetFieldNames("name", "status", "type")
 
 	for _, domain := range domains {
 		if domain.Shared {
@@ -65,6 +65,7 @@ func (cmd *StagingEnvironmentVariableGroup) Execute(c flags.FlagContext) {
 	cmd.ui.Ok()
 
 	table := cmd.ui.Table([]string{T("Variable Name"), T("Assigned Value")})
+	table.SetFieldNames("name", "value")
 	for _, envVar := range stagingEnvVars {
 		table.Add(envVar.Name, envVar.Value)
 	}
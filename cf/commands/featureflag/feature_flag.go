@@ -66,6 +66,7 @@ func (cmd *ShowFeatureFlag) Execute(c flags.FlagContext) {
 	cmd.ui.Say("")
 
 	table := cmd.ui.Table([]string{T("Features"), T("State")})
+	table.SetFieldNames("name", "state")
 	table.Add(flag.Name, cmd.flagBoolToString(flag.Enabled))
 
 	table.Print()
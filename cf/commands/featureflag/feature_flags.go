@@ -67,6 +67,7 @@ func (cmd *ListFeatureFlags) Execute(c flags.FlagContext) {
 	cmd.ui.Say("")
 
 	table := cmd.ui.Table([]string{T("Features"), T("State")})
+	table.SetFieldNames("name", "state")
 
 	for _, flag := range flags {
 		table.Add(
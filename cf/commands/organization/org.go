@@ -79,6 +79,13 @@ func (cmd *ShowOrg) Execute(c flags.FlagContext) {
 		cmd.ui.Say("")
 
 		table := cmd.ui.Table([]string{terminal.EntityNameColor(org.Name) + ":", "", ""})
+		table.SetFieldNames("org", "property", "value")
+
+		// records do not have the header that names the org
+		orgName := ""
+		if table.OutputFormat.IsStructured() {
+			orgName = org.Name
+		}
 
 		domains := []string{}
 		for _, domain := range org.Domains {
@@ -115,10 +122,10 @@ func (cmd *ShowOrg) Execute(c flags.FlagContext) {
 		if cmd.pluginCall {
 			cmd.populatePluginModel(org, quota)
 		} else {
-			table.Add("", T("domains:"), terminal.EntityNameColor(strings.Join(domains, ", ")))
-			table.Add("", T("quota:"), terminal.EntityNameColor(orgQuota))
-			table.Add("", T("spaces:"), terminal.EntityNameColor(strings.Join(spaces, ", ")))
-			table.Add("", T("space quotas:"), terminal.EntityNameColor(strings.Join(spaceQuotas, ", ")))
+			table.Add(orgName, T("domains:"), terminal.EntityNameColor(strings.Join(domains, ", ")))
+			table.Add(orgName, T("quota:"), terminal.EntityNameColor(orgQuota))
+			table.Add(orgName, T("spaces:"), terminal.EntityNameColor(strings.Join(spaces, ", ")))
+			table.Add(orgName, T("space quotas:"), terminal.EntityNameColor(strings.Join(spaceQuotas, ", ")))
 
 			table.Print()
 		}
@@ -68,6 +68,7 @@ func (cmd ListOrgs) Execute(fc flags.FlagContext) {
 
 	noOrgs := true
 	table := cmd.ui.Table([]string{T("name")})
+	table.SetFieldNames("name")
 
 	orgs, apiErr := cmd.orgRepo.ListOrgs(orgLimit)
 	if apiErr != nil {
@@ -66,8 +66,10 @@ func (cmd *Plugins) Execute(c flags.FlagContext) {
 	if c.Bool("checksum") {
 		cmd.ui.Say(T("Computing sha1 for installed plugins, this may take a while ..."))
 		table = cmd.ui.Table([]string{T("Plugin Name"), T("Version"), T("Command Name"), "sha1", T("Command Help")})
+		table.SetFieldNames("plugin_name", "version", "command_name", "sha1", "command_help")
 	} else {
 		table = cmd.ui.Table([]string{T("Plugin Name"), T("Version"), T("Command Name"), T("Command Help")})
+		table.SetFieldNames("plugin_name", "version", "command_name", "command_help")
 	}
 
 	for pluginName, metadata := range plugins {
@@ -53,6 +53,7 @@ func (cmd *ListPluginRepos) Execute(c flags.FlagContext) {
 	repos := cmd.config.PluginRepos()
 
 	table := cmd.ui.Table([]string{T("Repo Name"), T("URL")})
+	table.SetFieldNames("name", "url")
 
 	for _, repo := range repos {
 		table.Add(repo.Name, repo.URL)
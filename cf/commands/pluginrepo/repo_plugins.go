@@ -91,6 +91,7 @@ func (cmd RepoPlugins) printTable(repoPlugins map[string][]clipr.Plugin) {
 	for k, plugins := range repoPlugins {
 		cmd.ui.Say(terminal.ColorizeBold(T("Repository: ")+k, 33))
 		table := cmd.ui.Table([]string{T("name"), T("version"), T("description")})
+		table.SetFieldNames("name", "version", "description")
 		for _, p := range plugins {
 			table.Add(p.Name, p.Version, p.Description)
 		}
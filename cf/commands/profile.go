@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"github.com/cloudfoundry/cli/cf"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/flags"
+)
+
+type Profile struct {
+	ui     terminal.UI
+	config coreconfig.Repository
+}
+
+func init() {
+	commandregistry.Register(&Profile{})
+}
+
+func (cmd *Profile) MetaData() commandregistry.CommandMetadata {
+	return commandregistry.CommandMetadata{
+		Name:        "profile",
+		Description: T("Save the current target as a named profile, or switch to a saved one"),
+		Usage: []string{
+			T("CF_NAME profile save NAME\n"),
+			T("   CF_NAME profile use NAME\n"),
+			T("   CF_NAME --profile NAME COMMAND [ARGS...]"),
+		},
+		Examples: []string{
+			"CF_NAME profile save staging",
+			"CF_NAME profile use prod",
+			"CF_NAME --profile dev apps",
+		},
+	}
+}
+
+func (cmd *Profile) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
+	usageReq := requirements.NewUsageRequirement(commandregistry.CLICommandUsagePresenter(cmd),
+		T("Requires save or use, and a profile name, as arguments"),
+		func() bool {
+			args := fc.Args()
+			return len(args) != 2 || (args[0] != "save" && args[0] != "use") || args[1] == ""
+		},
+	)
+
+	reqs := []requirements.Requirement{
+		usageReq,
+	}
+
+	return reqs
+}
+
+func (cmd *Profile) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	cmd.ui = deps.UI
+	cmd.config = deps.Config
+	return cmd
+}
+
+func (cmd *Profile) Execute(c flags.FlagContext) {
+	name := c.Args()[1]
+
+	if c.Args()[0] == "save" {
+		cmd.ui.Say(T("Saving target as profile {{.Name}}...", map[string]interface{}{"Name": terminal.EntityNameColor(name)}))
+		cmd.config.SaveProfile(name)
+		cmd.ui.Ok()
+		return
+	}
+
+	cmd.ui.Say(T("Switching to profile {{.Name}}...", map[string]interface{}{"Name": terminal.EntityNameColor(name)}))
+
+	_, found := cmd.config.Profile(name)
+	if !found {
+		cmd.ui.Failed(T("Profile {{.Name}} not found. Use '{{.Command}}' to list the saved profiles.", map[string]interface{}{
+			"Name":    name,
+			"Command": terminal.CommandColor(cf.Name + " profiles"),
+		}))
+	}
+
+	err := cmd.config.UseProfile(name)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+	cmd.ui.ShowConfiguration(cmd.config)
+}
@@ -0,0 +1,87 @@
+package commands_test
+
+import (
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("profile command", func() {
+	var (
+		config coreconfig.Repository
+		ui     *testterm.FakeUI
+		deps   commandregistry.Dependency
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.UI = ui
+		deps.Config = config
+		commandregistry.Commands.SetCommand(commandregistry.Commands.FindCommand("profile").SetDependency(deps, pluginCall))
+	}
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCLICommand("profile", args, &testreq.FakeReqFactory{}, updateCommandDependency, false)
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		config = testconfig.NewRepositoryWithDefaults()
+	})
+
+	It("fails with usage when not given save or use and a name", func() {
+		Expect(runCommand("dev")).To(BeFalse())
+		Expect(runCommand("delete", "dev")).To(BeFalse())
+		Expect(runCommand("use", "dev", "prod")).To(BeFalse())
+	})
+
+	Describe("save", func() {
+		It("saves the target as a profile that becomes the current one", func() {
+			Expect(runCommand("save", "staging")).To(BeTrue())
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Saving target as profile", "staging"},
+				[]string{"OK"},
+			))
+			Expect(config.ProfileName()).To(Equal("staging"))
+			Expect(config.ProfileNames()).To(Equal([]string{"default", "staging"}))
+		})
+	})
+
+	Describe("use", func() {
+		BeforeEach(func() {
+			config.SetAPIEndpoint("https://api.dev.example.com")
+			config.SetAPIVersion("2.35.0")
+			config.SaveProfile("staging")
+			config.SetAPIEndpoint("https://api.staging.example.com")
+		})
+
+		It("switches to the profile and shows its target", func() {
+			Expect(runCommand("use", "default")).To(BeTrue())
+
+			Expect(config.ProfileName()).To(Equal("default"))
+			Expect(config.APIEndpoint()).To(Equal("https://api.dev.example.com"))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Switching to profile", "default"},
+				[]string{"OK"},
+			))
+			Expect(ui.ShowConfigurationCalled).To(BeTrue())
+		})
+
+		It("fails when the profile does not exist", func() {
+			runCommand("use", "prod")
+
+			Expect(config.ProfileName()).To(Equal("staging"))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Profile prod not found"},
+			))
+		})
+	})
+})
@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/flags"
+)
+
+type ListProfiles struct {
+	ui     terminal.UI
+	config coreconfig.Repository
+}
+
+func init() {
+	commandregistry.Register(&ListProfiles{})
+}
+
+func (cmd *ListProfiles) MetaData() commandregistry.CommandMetadata {
+	return commandregistry.CommandMetadata{
+		Name:        "profiles",
+		Description: T("List target profiles"),
+		Usage: []string{
+			T("CF_NAME profiles"),
+		},
+	}
+}
+
+func (cmd *ListProfiles) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
+	usageReq := requirements.NewUsageRequirement(commandregistry.CLICommandUsagePresenter(cmd),
+		T("No argument required"),
+		func() bool {
+			return len(fc.Args()) != 0
+		},
+	)
+
+	reqs := []requirements.Requirement{
+		usageReq,
+	}
+
+	return reqs
+}
+
+func (cmd *ListProfiles) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	cmd.ui = deps.UI
+	cmd.config = deps.Config
+	return cmd
+}
+
+func (cmd *ListProfiles) Execute(c flags.FlagContext) {
+	cmd.ui.Say(T("Getting target profiles..."))
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+
+	table := cmd.ui.Table([]string{"", T("name"), T("api endpoint"), T("user"), T("org"), T("space")})
+	table.SetFieldNames("current", "name", "api_endpoint", "user", "org", "space")
+
+	inUse := cmd.config.ProfileName()
+	for _, name := range cmd.config.ProfileNames() {
+		target, _ := cmd.config.Profile(name)
+
+		current := ""
+		if name == inUse {
+			current = "*"
+		}
+
+		user := coreconfig.NewTokenInfo(target.AccessToken).Username
+		if target.UAAGrantType == coreconfig.ClientCredentialsGrantType {
+			user = target.UAAOAuthClient
+		}
+
+		table.Add(
+			current,
+			name,
+			target.Target,
+			user,
+			target.OrganizationFields.Name,
+			target.SpaceFields.Name,
+		)
+	}
+
+	table.Print()
+}
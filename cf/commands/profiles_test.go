@@ -0,0 +1,68 @@
+package commands_test
+
+import (
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/models"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("profiles command", func() {
+	var (
+		config coreconfig.Repository
+		ui     *testterm.FakeUI
+		deps   commandregistry.Dependency
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.UI = ui
+		deps.Config = config
+		commandregistry.Commands.SetCommand(commandregistry.Commands.FindCommand("profiles").SetDependency(deps, pluginCall))
+	}
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCLICommand("profiles", args, &testreq.FakeReqFactory{}, updateCommandDependency, false)
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		config = testconfig.NewRepositoryWithDefaults()
+
+		config.SaveProfile("dev")
+		config.SaveProfile("prod")
+		config.SetAPIEndpoint("https://api.prod.example.com")
+		config.SetOrganizationFields(models.OrganizationFields{Name: "prod-org", GUID: "prod-org-guid"})
+		config.SetSpaceFields(models.SpaceFields{Name: "prod-space", GUID: "prod-space-guid"})
+	})
+
+	It("fails with usage when given arguments", func() {
+		Expect(runCommand("dev")).To(BeFalse())
+	})
+
+	It("lists the profiles and their targets, marking the one in use", func() {
+		Expect(runCommand()).To(BeTrue())
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"name", "api endpoint", "user", "org", "space"},
+			[]string{"default", "my-org", "my-space"},
+			[]string{"dev", "my-org", "my-space"},
+			[]string{"*", "prod", "https://api.prod.example.com", "prod-org", "prod-space"},
+		))
+	})
+
+	It("shows the client of profiles authenticated as a client", func() {
+		config.SetUAAGrantType("client_credentials")
+		config.SetUAAOAuthClient("ci-client")
+
+		Expect(runCommand()).To(BeTrue())
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"*", "prod", "ci-client", "prod-org"},
+		))
+	})
+})
@@ -84,6 +84,7 @@ func (cmd *showQuota) Execute(c flags.FlagContext) {
 	}
 
 	table := cmd.ui.Table([]string{"", ""})
+	table.SetFieldNames("setting", "value")
 	table.Add(T("Total Memory"), formatters.ByteSize(quota.MemoryLimit*formatters.MEGABYTE))
 	table.Add(T("Instance Memory"), megabytes)
 	table.Add(T("Routes"), fmt.Sprint(quota.RoutesLimit))
@@ -9,6 +9,7 @@ import (
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
 	"github.com/cloudfoundry/cli/cf/errors"
 	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/terminal"
 	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
 	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
 	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
@@ -93,6 +94,17 @@ var _ = Describe("quota", func() {
 						[]string{"App instance limit", "7"},
 					))
 				})
+
+				It("names the fields of its records independently of the locale", func() {
+					ui.OutputFormat = terminal.OutputJSON
+
+					runCommand("muh-muh-muh-my-qua-quota")
+
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{`"setting": "Total Memory"`},
+						[]string{`"value": "512M"`},
+					))
+				})
 			})
 
 			Context("when the app instance limit is -1", func() {
@@ -72,6 +72,7 @@ func (cmd *ListQuotas) Execute(c flags.FlagContext) {
 	cmd.ui.Say("")
 
 	table := cmd.ui.Table([]string{T("name"), T("total memory limit"), T("instance memory limit"), T("routes"), T("service instances"), T("paid service plans"), T("app instance limit")})
+	table.SetFieldNames("name", "total_memory_limit", "instance_memory_limit", "routes", "service_instances", "paid_service_plans", "app_instance_limit")
 
 	var megabytes string
 	for _, quota := range quotas {
@@ -85,6 +85,7 @@ func (cmd *ListRoutes) Execute(c flags.FlagContext) {
 	}
 
 	table := cmd.ui.Table([]string{T("space"), T("host"), T("domain"), T("port"), T("path"), T("type"), T("apps"), T("service")})
+	table.SetFieldNames("space", "host", "domain", "port", "path", "type", "apps", "service")
 
 	d := make(map[string]models.DomainFields)
 	cmd.domainRepo.ListDomainsForOrg(cmd.config.OrganizationFields().GUID, func(domain models.DomainFields) bool {
@@ -57,6 +57,7 @@ func (cmd *RouterGroups) Execute(c flags.FlagContext) {
 		map[string]interface{}{"Username": terminal.EntityNameColor(cmd.config.Username())}))
 
 	table := cmd.ui.Table([]string{T("name"), T("type")})
+	table.SetFieldNames("name", "type")
 
 	noRouterGroups := true
 	cb := func(group models.RouterGroup) bool {
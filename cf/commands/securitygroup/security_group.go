@@ -74,6 +74,7 @@ func (cmd *ShowSecurityGroup) Execute(c flags.FlagContext) {
 
 	cmd.ui.Ok()
 	table := cmd.ui.Table([]string{"", ""})
+	table.SetFieldNames("setting", "value")
 	table.Add(T("Name"), securityGroup.Name)
 	table.Add(T("Rules"), "")
 	table.Print()
@@ -83,6 +84,7 @@ func (cmd *ShowSecurityGroup) Execute(c flags.FlagContext) {
 
 	if len(securityGroup.Spaces) > 0 {
 		table = cmd.ui.Table([]string{"", T("Organization"), T("Space")})
+		table.SetFieldNames("index", "organization", "space")
 
 		for index, space := range securityGroup.Spaces {
 			table.Add(fmt.Sprintf("#%d", index), space.Organization.Name, space.Name)
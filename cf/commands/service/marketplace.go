@@ -108,6 +108,7 @@ func (cmd MarketplaceServices) marketplaceByService(serviceName string) {
 	}
 
 	table := cmd.ui.Table([]string{T("service plan"), T("description"), T("free or paid")})
+	table.SetFieldNames("plan", "description", "free_or_paid")
 	for _, plan := range serviceOffering.Plans {
 		var freeOrPaid string
 		if plan.Free {
@@ -150,13 +151,17 @@ func (cmd MarketplaceServices) marketplace() {
 	cmd.ui.Ok()
 	cmd.ui.Say("")
 
+	table := cmd.ui.Table([]string{T("service"), T("plans"), T("description")})
+	table.SetFieldNames("service", "plans", "description")
+
 	if len(serviceOfferings) == 0 {
+		if table.OutputFormat.IsStructured() {
+			table.Print()
+		}
 		cmd.ui.Say(T("No service offerings found"))
 		return
 	}
 
-	table := cmd.ui.Table([]string{T("service"), T("plans"), T("description")})
-
 	sort.Sort(serviceOfferings)
 	var paidPlanExists bool
 	for _, offering := range serviceOfferings {
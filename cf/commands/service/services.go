@@ -81,13 +81,17 @@ func (cmd *ListServices) Execute(fc flags.FlagContext) {
 	cmd.ui.Ok()
 	cmd.ui.Say("")
 
+	table := cmd.ui.Table([]string{T("name"), T("service"), T("plan"), T("bound apps"), T("last operation")})
+	table.SetFieldNames("name", "service", "plan", "bound_apps", "last_operation")
+
 	if len(serviceInstances) == 0 {
+		if table.OutputFormat.IsStructured() {
+			table.Print()
+		}
 		cmd.ui.Say(T("No services found"))
 		return
 	}
 
-	table := cmd.ui.Table([]string{T("name"), T("service"), T("plan"), T("bound apps"), T("last operation")})
-
 	for _, instance := range serviceInstances {
 		var serviceColumn string
 		var serviceStatus string
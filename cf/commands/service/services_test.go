@@ -13,6 +13,7 @@ import (
 
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
 	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/terminal"
 
 	. "github.com/cloudfoundry/cli/testhelpers/matchers"
 
@@ -165,6 +166,15 @@ var _ = Describe("services", func() {
 		))
 	})
 
+	It("prints an empty list with the json output format when no services are found", func() {
+		serviceSummaryRepo.GetSummariesInCurrentSpaceInstances = []models.ServiceInstance{}
+		ui.OutputFormat = terminal.OutputJSON
+
+		runCommand()
+
+		Expect(ui.Outputs).To(ContainElement("[]"))
+	})
+
 	Describe("when invoked by a plugin", func() {
 
 		var (
@@ -127,13 +127,21 @@ func (cmd ServiceAccess) printTable(brokers []models.ServiceBroker) {
 		cmd.ui.Say(fmt.Sprintf(T("broker: {{.Name}}", map[string]interface{}{"Name": serviceBroker.Name})))
 
 		table := cmd.ui.Table([]string{"", T("service"), T("plan"), T("access"), T("orgs")})
+		table.SetFieldNames("broker", "service", "plan", "access", "orgs")
+
+		// records are printed without the broker line above them
+		broker := ""
+		if table.OutputFormat.IsStructured() {
+			broker = serviceBroker.Name
+		}
+
 		for _, service := range serviceBroker.Services {
 			if len(service.Plans) > 0 {
 				for _, plan := range service.Plans {
-					table.Add("", service.Label, plan.Name, cmd.formatAccess(plan.Public, plan.OrgNames), strings.Join(plan.OrgNames, ","))
+					table.Add(broker, service.Label, plan.Name, cmd.formatAccess(plan.Public, plan.OrgNames), strings.Join(plan.OrgNames, ","))
 				}
 			} else {
-				table.Add("", service.Label, "", "", "")
+				table.Add(broker, service.Label, "", "", "")
 			}
 		}
 		table.Print()
@@ -74,6 +74,7 @@ func (cmd *ListServiceAuthTokens) Execute(c flags.FlagContext) {
 	cmd.ui.Say("")
 
 	table := cmd.ui.Table([]string{T("label"), T("provider")})
+	table.SetFieldNames("label", "provider")
 
 	for _, authToken := range authTokens {
 		table.Add(authToken.Label, authToken.Provider)
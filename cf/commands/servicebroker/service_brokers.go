@@ -72,6 +72,7 @@ func (cmd *ListServiceBrokers) Execute(c flags.FlagContext) {
 		}))
 
 	table := cmd.ui.Table([]string{T("name"), T("url")})
+	table.SetFieldNames("name", "url")
 	foundBrokers := false
 	apiErr := cmd.repo.ListServiceBrokers(func(serviceBroker models.ServiceBroker) bool {
 		sbTable = append(sbTable, serviceBrokerRow{
@@ -75,6 +75,7 @@ func (cmd *ServiceKeys) Execute(c flags.FlagContext) {
 	}
 
 	table := cmd.ui.Table([]string{T("name")})
+	table.SetFieldNames("name")
 
 	for _, serviceKey := range serviceKeys {
 		table.Add(serviceKey.Fields.Name)
@@ -89,33 +89,41 @@ func (cmd *ShowSpace) Execute(c flags.FlagContext) {
 		cmd.ui.Ok()
 		cmd.ui.Say("")
 		table := cmd.ui.Table([]string{terminal.EntityNameColor(space.Name), "", ""})
-		table.Add("", T("Org:"), terminal.EntityNameColor(space.Organization.Name))
+		table.SetFieldNames("space", "property", "value")
+
+		// records do not have the header that names the space
+		spaceName := ""
+		if table.OutputFormat.IsStructured() {
+			spaceName = space.Name
+		}
+
+		table.Add(spaceName, T("Org:"), terminal.EntityNameColor(space.Organization.Name))
 
 		apps := []string{}
 		for _, app := range space.Applications {
 			apps = append(apps, terminal.EntityNameColor(app.Name))
 		}
-		table.Add("", T("Apps:"), strings.Join(apps, ", "))
+		table.Add(spaceName, T("Apps:"), strings.Join(apps, ", "))
 
 		domains := []string{}
 		for _, domain := range space.Domains {
 			domains = append(domains, terminal.EntityNameColor(domain.Name))
 		}
-		table.Add("", T("Domains:"), strings.Join(domains, ", "))
+		table.Add(spaceName, T("Domains:"), strings.Join(domains, ", "))
 
 		services := []string{}
 		for _, service := range space.ServiceInstances {
 			services = append(services, terminal.EntityNameColor(service.Name))
 		}
-		table.Add("", T("Services:"), strings.Join(services, ", "))
+		table.Add(spaceName, T("Services:"), strings.Join(services, ", "))
 
 		securityGroups := []string{}
 		for _, group := range space.SecurityGroups {
 			securityGroups = append(securityGroups, terminal.EntityNameColor(group.Name))
 		}
-		table.Add("", T("Security Groups:"), strings.Join(securityGroups, ", "))
+		table.Add(spaceName, T("Security Groups:"), strings.Join(securityGroups, ", "))
 
-		table.Add("", T("Space Quota:"), quotaString)
+		table.Add(spaceName, T("Space Quota:"), quotaString)
 
 		table.Print()
 	}
@@ -125,11 +133,20 @@ func (cmd *ShowSpace) Execute(c flags.FlagContext) {
 			cmd.ui.Say(T("Getting rules for the security group  : {{.SecurityGroupName}}...",
 				map[string]interface{}{"SecurityGroupName": terminal.EntityNameColor(group.Name)}))
 			table := cmd.ui.Table([]string{"", "", "", ""})
+			table.SetFieldNames("security_group", "rule", "separator", "value")
+
+			groupName := ""
+			if table.OutputFormat.IsStructured() {
+				groupName = group.Name
+			}
+
 			for _, rules := range group.Rules {
 				for ruleName, ruleValue := range rules {
-					table.Add("", ruleName, ":", fmt.Sprintf("%v", ruleValue))
+					table.Add(groupName, ruleName, ":", fmt.Sprintf("%v", ruleValue))
+				}
+				if !table.OutputFormat.IsStructured() {
+					table.Add("", "", "", "")
 				}
-				table.Add("", "", "", "")
 			}
 			table.Print()
 		}
@@ -71,6 +71,7 @@ func (cmd *ListSpaces) Execute(c flags.FlagContext) {
 
 	foundSpaces := false
 	table := cmd.ui.Table([]string{T("name")})
+	table.SetFieldNames("name")
 	apiErr := cmd.spaceRepo.ListSpaces(func(space models.Space) bool {
 		table.Add(space.Name)
 		foundSpaces = true
@@ -75,6 +75,7 @@ func (cmd *SpaceQuota) Execute(c flags.FlagContext) {
 	var megabytes string
 
 	table := cmd.ui.Table([]string{"", ""})
+	table.SetFieldNames("setting", "value")
 	table.Add(T("total memory limit"), formatters.ByteSize(spaceQuota.MemoryLimit*formatters.MEGABYTE))
 	if spaceQuota.InstanceMemoryLimit == -1 {
 		megabytes = T("unlimited")
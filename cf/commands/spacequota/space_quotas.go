@@ -80,6 +80,7 @@ func (cmd *ListSpaceQuotas) Execute(c flags.FlagContext) {
 		T("paid service plans"),
 		T("app instance limit"),
 	})
+	table.SetFieldNames("name", "total_memory_limit", "instance_memory_limit", "routes", "service_instances", "paid_service_plans", "app_instance_limit")
 
 	var megabytes string
 
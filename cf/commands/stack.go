@@ -76,6 +76,7 @@ func (cmd *ListStack) Execute(c flags.FlagContext) {
 		cmd.ui.Ok()
 		cmd.ui.Say("")
 		table := cmd.ui.Table([]string{T("name"), T("description")})
+		table.SetFieldNames("name", "description")
 		table.Add(stack.Name, stack.Description)
 		table.Print()
 	}
@@ -69,6 +69,7 @@ func (cmd *ListStacks) Execute(c flags.FlagContext) {
 	cmd.ui.Say("")
 
 	table := cmd.ui.Table([]string{T("name"), T("description")})
+	table.SetFieldNames("name", "description")
 
 	for _, stack := range stacks {
 		table.Add(stack.Name, stack.Description)
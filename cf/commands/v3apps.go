@@ -86,6 +86,7 @@ func (c *V3Apps) Execute(fc flags.FlagContext) {
 	}
 
 	table := c.ui.Table([]string{T("name"), T("requested state"), T("instances"), T("memory"), T("disk"), T("urls")})
+	table.SetFieldNames("name", "requested_state", "instances", "memory", "disk", "urls")
 
 	for i := range applications {
 		c.addRow(table, applications[i], processes[i], routes[i])
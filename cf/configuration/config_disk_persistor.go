@@ -26,17 +26,26 @@ type DataInterface interface {
 	JSONUnmarshalV3([]byte) error
 }
 
+// DiskPersistor keeps the data in a JSON file, which other cf processes may
+// be using at the same time. Loading and saving hold a lock on the file, the
+// file is replaced rather than rewritten in place, and saving only changes
+// what this process changed since it loaded the file, keeping what others
+// have saved meanwhile.
 type DiskPersistor struct {
 	filePath string
+
+	// loaded is the data as this process last loaded or saved it, to tell
+	// what it has changed since
+	loaded []byte
 }
 
-func NewDiskPersistor(path string) (dp DiskPersistor) {
-	return DiskPersistor{
+func NewDiskPersistor(path string) *DiskPersistor {
+	return &DiskPersistor{
 		filePath: path,
 	}
 }
 
-func (dp DiskPersistor) Exists() bool {
+func (dp *DiskPersistor) Exists() bool {
 	_, err := os.Stat(dp.filePath)
 	if err != nil && !os.IsExist(err) {
 		return false
@@ -44,11 +53,14 @@ func (dp DiskPersistor) Exists() bool {
 	return true
 }
 
-func (dp DiskPersistor) Delete() {
+func (dp *DiskPersistor) Delete() {
 	os.Remove(dp.filePath)
 }
 
-func (dp DiskPersistor) Load(data DataInterface) error {
+func (dp *DiskPersistor) Load(data DataInterface) error {
+	unlock := dp.lock()
+	defer unlock()
+
 	err := dp.read(data)
 	if os.IsPermission(err) {
 		return err
@@ -60,28 +72,52 @@ func (dp DiskPersistor) Load(data DataInterface) error {
 	return err
 }
 
-func (dp DiskPersistor) Save(data DataInterface) (err error) {
+func (dp *DiskPersistor) Save(data DataInterface) (err error) {
+	unlock := dp.lock()
+	defer unlock()
+
 	return dp.write(data)
 }
 
-func (dp DiskPersistor) read(data DataInterface) error {
+func (dp *DiskPersistor) lock() (unlock func()) {
 	dp.makeDirectory()
+	return LockFile(dp.filePath)
+}
 
+func (dp *DiskPersistor) read(data DataInterface) error {
 	jsonBytes, err := ioutil.ReadFile(dp.filePath)
 	if err != nil {
 		return err
 	}
 
 	err = data.JSONUnmarshalV3(jsonBytes)
-	return err
+	if err != nil {
+		return err
+	}
+
+	dp.loaded = jsonBytes
+	return nil
 }
 
-func (dp DiskPersistor) write(data DataInterface) error {
+func (dp *DiskPersistor) write(data DataInterface) error {
 	bytes, err := data.JSONMarshalV3()
 	if err != nil {
 		return err
 	}
 
-	err = ioutil.WriteFile(dp.filePath, bytes, filePermissions)
-	return err
+	content := bytes
+	if dp.loaded != nil {
+		saved, readErr := ioutil.ReadFile(dp.filePath)
+		if readErr == nil {
+			content = mergeJSON(dp.loaded, bytes, saved)
+		}
+	}
+
+	err = ReplaceFile(dp.filePath, content)
+	if err != nil {
+		return err
+	}
+
+	dp.loaded = bytes
+	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 
 	. "github.com/cloudfoundry/cli/cf/configuration"
 	. "github.com/onsi/ginkgo"
@@ -14,13 +15,14 @@ var _ = Describe("DiskPersistor", func() {
 	var (
 		tmpDir        string
 		tmpFile       *os.File
-		diskPersistor DiskPersistor
+		diskPersistor *DiskPersistor
 	)
 
 	BeforeEach(func() {
 		var err error
 
-		tmpDir = os.TempDir()
+		tmpDir, err = ioutil.TempDir("", "disk_persistor")
+		Expect(err).ToNot(HaveOccurred())
 
 		tmpFile, err = ioutil.TempFile(tmpDir, "tmp_file")
 		Expect(err).ToNot(HaveOccurred())
@@ -29,7 +31,7 @@ var _ = Describe("DiskPersistor", func() {
 	})
 
 	AfterEach(func() {
-		os.Remove(tmpFile.Name())
+		os.RemoveAll(tmpDir)
 	})
 
 	Describe(".Delete", func() {
@@ -54,6 +56,152 @@ var _ = Describe("DiskPersistor", func() {
 			Expect(err).ToNot(HaveOccurred())
 			Expect(string(dataBytes)).To(ContainSubstring(d.Info))
 		})
+
+		It("replaces the file without leaving temporary files behind", func() {
+			err := diskPersistor.Save(&data{Info: "save test"})
+			Expect(err).ToNot(HaveOccurred())
+
+			info, err := os.Stat(tmpFile.Name())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+			files, err := filepath.Glob(filepath.Join(tmpDir, "*.tmp*"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(files).To(BeEmpty())
+		})
+
+		Context("when another process has saved the file since it was loaded", func() {
+			var otherPersistor *DiskPersistor
+
+			BeforeEach(func() {
+				err := ioutil.WriteFile(tmpFile.Name(), []byte(`{"Info":"loaded","Target":"loaded"}`), 0600)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = diskPersistor.Load(&data{})
+				Expect(err).ToNot(HaveOccurred())
+
+				otherPersistor = NewDiskPersistor(tmpFile.Name())
+				err = otherPersistor.Load(&data{})
+				Expect(err).ToNot(HaveOccurred())
+
+				err = otherPersistor.Save(&data{Info: "loaded", Target: "changed by the other"})
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("keeps what the other process changed", func() {
+				err := diskPersistor.Save(&data{Info: "changed", Target: "loaded"})
+				Expect(err).ToNot(HaveOccurred())
+
+				d := &data{}
+				err = NewDiskPersistor(tmpFile.Name()).Load(d)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(d.Info).To(Equal("changed"))
+				Expect(d.Target).To(Equal("changed by the other"))
+			})
+
+			It("keeps its own change of the same value", func() {
+				err := diskPersistor.Save(&data{Info: "loaded", Target: "changed"})
+				Expect(err).ToNot(HaveOccurred())
+
+				d := &data{}
+				err = NewDiskPersistor(tmpFile.Name()).Load(d)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(d.Target).To(Equal("changed"))
+			})
+		})
+
+		Context("when both processes change fields that go together", func() {
+			var otherPersistor *DiskPersistor
+
+			BeforeEach(func() {
+				err := ioutil.WriteFile(tmpFile.Name(), []byte(`{"Info":"loaded","AccessToken":"access","RefreshToken":"refresh"}`), 0600)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(diskPersistor.Load(&data{})).To(Succeed())
+
+				otherPersistor = NewDiskPersistor(tmpFile.Name())
+				Expect(otherPersistor.Load(&data{})).To(Succeed())
+			})
+
+			It("takes them all from the process that changed them last", func() {
+				err := otherPersistor.Save(&data{Info: "loaded", AccessToken: "other-access", RefreshToken: "other-refresh"})
+				Expect(err).ToNot(HaveOccurred())
+
+				err = diskPersistor.Save(&data{Info: "loaded", AccessToken: "access", RefreshToken: "own-refresh"})
+				Expect(err).ToNot(HaveOccurred())
+
+				d := &data{}
+				Expect(NewDiskPersistor(tmpFile.Name()).Load(d)).To(Succeed())
+				Expect(d.AccessToken).To(Equal("access"))
+				Expect(d.RefreshToken).To(Equal("own-refresh"))
+			})
+
+			It("takes them all from the other process when only it changed them", func() {
+				err := otherPersistor.Save(&data{Info: "loaded", AccessToken: "access", RefreshToken: "other-refresh"})
+				Expect(err).ToNot(HaveOccurred())
+
+				err = diskPersistor.Save(&data{Info: "changed", AccessToken: "access", RefreshToken: "refresh"})
+				Expect(err).ToNot(HaveOccurred())
+
+				d := &data{}
+				Expect(NewDiskPersistor(tmpFile.Name()).Load(d)).To(Succeed())
+				Expect(d.Info).To(Equal("changed"))
+				Expect(d.AccessToken).To(Equal("access"))
+				Expect(d.RefreshToken).To(Equal("other-refresh"))
+			})
+
+			It("does not pair a space with another org", func() {
+				err := otherPersistor.Save(&data{
+					Info:               "loaded",
+					AccessToken:        "access",
+					RefreshToken:       "refresh",
+					OrganizationFields: map[string]string{"Name": "other-org"},
+					SpaceFields:        map[string]string{"Name": "other-space"},
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				err = diskPersistor.Save(&data{
+					Info:         "loaded",
+					AccessToken:  "access",
+					RefreshToken: "refresh",
+					SpaceFields:  map[string]string{"Name": "own-space"},
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				d := &data{}
+				Expect(NewDiskPersistor(tmpFile.Name()).Load(d)).To(Succeed())
+				Expect(d.OrganizationFields).To(BeEmpty())
+				Expect(d.SpaceFields).To(Equal(map[string]string{"Name": "own-space"}))
+			})
+		})
+
+		It("keeps the changes of processes saving at the same time", func() {
+			err := diskPersistor.Load(&data{})
+			Expect(err).ToNot(HaveOccurred())
+
+			otherPersistor := NewDiskPersistor(tmpFile.Name())
+			err = otherPersistor.Load(&data{})
+			Expect(err).ToNot(HaveOccurred())
+
+			done := make(chan bool)
+			go func() {
+				defer GinkgoRecover()
+				for i := 0; i < 20; i++ {
+					Expect(otherPersistor.Save(&data{Target: "target"})).To(Succeed())
+				}
+				close(done)
+			}()
+			for i := 0; i < 20; i++ {
+				Expect(diskPersistor.Save(&data{Info: "info"})).To(Succeed())
+			}
+			<-done
+
+			d := &data{}
+			err = NewDiskPersistor(tmpFile.Name()).Load(d)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(d.Info).To(Equal("info"))
+			Expect(d.Target).To(Equal("target"))
+		})
 	})
 
 	Describe(".Load", func() {
@@ -79,7 +227,13 @@ var _ = Describe("DiskPersistor", func() {
 })
 
 type data struct {
-	Info string
+	Info   string
+	Target string
+
+	AccessToken        string            `json:",omitempty"`
+	RefreshToken       string            `json:",omitempty"`
+	OrganizationFields map[string]string `json:",omitempty"`
+	SpaceFields        map[string]string `json:",omitempty"`
 }
 
 func (d *data) JSONMarshalV3() ([]byte, error) {
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"syscall"
+	"unsafe"
 )
 
 func (dp DiskPersistor) makeDirectory() error {
@@ -28,3 +29,27 @@ func (dp DiskPersistor) makeDirectory() error {
 
 	return syscall.SetFileAttributes(p, attrs|syscall.FILE_ATTRIBUTE_HIDDEN)
 }
+
+const lockfileExclusiveLock = 0x2
+
+func lockFile(file *os.File) error {
+	dll := syscall.MustLoadDLL("kernel32")
+	proc := dll.MustFindProc("LockFileEx")
+	r, _, err := proc.Call(file.Fd(), lockfileExclusiveLock, 0, 1, 0, uintptr(unsafe.Pointer(new(syscall.Overlapped))))
+
+	if r == 0 {
+		return err
+	}
+	return nil
+}
+
+func unlockFile(file *os.File) error {
+	dll := syscall.MustLoadDLL("kernel32")
+	proc := dll.MustFindProc("UnlockFileEx")
+	r, _, err := proc.Call(file.Fd(), 0, 1, 0, uintptr(unsafe.Pointer(new(syscall.Overlapped))))
+
+	if r == 0 {
+		return err
+	}
+	return nil
+}
@@ -19,6 +19,12 @@ func DefaultFilePath() string {
 	return filepath.Join(configDir, "config.json")
 }
 
+// ResourceCacheFilePath is where push keeps the SHA1s of app files and the
+// resource matches of previous pushes, next to the config file.
+func ResourceCacheFilePath() string {
+	return filepath.Join(filepath.Dir(DefaultFilePath()), "resource_cache.json")
+}
+
 // See: http://stackoverflow.com/questions/7922270/obtain-users-home-directory
 // we can't cross compile using cgo and use user.Current()
 var userHomeDir = func() string {
@@ -10,6 +10,7 @@ type TokenInfo struct {
 	Username string `json:"user_name"`
 	Email    string `json:"email"`
 	UserGUID string `json:"user_id"`
+	ClientID string `json:"client_id"`
 }
 
 func NewTokenInfo(accessToken string) (info TokenInfo) {
@@ -2,7 +2,9 @@ package coreconfig
 
 import (
 	"encoding/json"
+	"fmt"
 
+	"github.com/cloudfoundry/cli/cf/configuration/credentials"
 	"github.com/cloudfoundry/cli/cf/models"
 )
 
@@ -30,6 +32,9 @@ type Data struct {
 	AccessToken              string
 	SSHOAuthClient           string
 	RefreshToken             string
+	UAAGrantType             string
+	UAAOAuthClient           string
+	UAAOAuthClientSecret     string
 	OrganizationFields       models.OrganizationFields
 	SpaceFields              models.SpaceFields
 	SSLDisabled              bool
@@ -40,6 +45,62 @@ type Data struct {
 	PluginRepos              []models.PluginRepo
 	MinCLIVersion            string
 	MinRecommendedCLIVersion string
+	CredentialsRef           string `json:",omitempty"`
+	CredentialStore          string `json:",omitempty"`
+
+	// The target fields above are those of Profile, which is CurrentProfile
+	// unless another profile was selected for a single invocation.
+	Profile        string `json:"-"`
+	CurrentProfile string
+	Profiles       map[string]TargetProfile
+}
+
+const DefaultProfileName = "default"
+
+// ClientCredentialsGrantType is the UAAGrantType of targets authenticated as
+// a UAA client rather than a user.
+const ClientCredentialsGrantType = "client_credentials"
+
+// TargetProfile is everything about one target: its endpoints, tokens, the
+// UAA client authenticated as with the client_credentials grant, SSL settings
+// and targeted org and space.
+type TargetProfile struct {
+	Target                   string
+	APIVersion               string
+	AuthorizationEndpoint    string
+	LoggregatorEndPoint      string
+	DopplerEndPoint          string
+	UaaEndpoint              string
+	RoutingAPIEndpoint       string
+	AccessToken              string `json:",omitempty"`
+	SSHOAuthClient           string
+	RefreshToken             string `json:",omitempty"`
+	UAAGrantType             string `json:",omitempty"`
+	UAAOAuthClient           string `json:",omitempty"`
+	UAAOAuthClientSecret     string `json:",omitempty"`
+	OrganizationFields       models.OrganizationFields
+	SpaceFields              models.SpaceFields
+	SSLDisabled              bool
+	MinCLIVersion            string
+	MinRecommendedCLIVersion string
+
+	// CredentialsRef is what the tokens are kept under in the credential
+	// store, when they are not kept in the config file.
+	CredentialsRef string `json:",omitempty"`
+}
+
+// dataV4 is the layout of version 4 config files, which keep every target in
+// a named profile.
+type dataV4 struct {
+	ConfigVersion   int
+	CurrentProfile  string
+	Profiles        map[string]TargetProfile
+	CredentialStore string `json:",omitempty"`
+	AsyncTimeout    uint
+	Trace           string
+	ColorEnabled    string
+	Locale          string
+	PluginRepos     []models.PluginRepo
 }
 
 func NewData() (data *Data) {
@@ -48,20 +109,169 @@ func NewData() (data *Data) {
 }
 
 func (d *Data) JSONMarshalV3() (output []byte, err error) {
-	d.ConfigVersion = 3
-	return json.MarshalIndent(d, "", "  ")
+	d.ConfigVersion = 4
+	d.storeProfile()
+
+	return json.MarshalIndent(dataV4{
+		ConfigVersion:   d.ConfigVersion,
+		CurrentProfile:  d.CurrentProfile,
+		Profiles:        d.Profiles,
+		CredentialStore: d.CredentialStore,
+		AsyncTimeout:    d.AsyncTimeout,
+		Trace:           d.Trace,
+		ColorEnabled:    d.ColorEnabled,
+		Locale:          d.Locale,
+		PluginRepos:     d.PluginRepos,
+	}, "", "  ")
 }
 
+// JSONUnmarshalV3 reads version 4 config files, and version 3 ones, whose
+// target becomes the default profile.
 func (d *Data) JSONUnmarshalV3(input []byte) (err error) {
-	err = json.Unmarshal(input, d)
+	version := struct{ ConfigVersion int }{}
+	err = json.Unmarshal(input, &version)
 	if err != nil {
 		return
 	}
 
-	if d.ConfigVersion != 3 {
+	switch version.ConfigVersion {
+	case 3:
+		*d = Data{}
+		err = json.Unmarshal(input, d)
+		if err != nil {
+			return
+		}
+		d.CurrentProfile = ""
+		d.Profiles = nil
+		d.storeProfile()
+	case 4:
+		v4 := dataV4{}
+		err = json.Unmarshal(input, &v4)
+		if err != nil {
+			return
+		}
+		*d = Data{
+			ConfigVersion:   v4.ConfigVersion,
+			CurrentProfile:  v4.CurrentProfile,
+			Profiles:        v4.Profiles,
+			CredentialStore: v4.CredentialStore,
+			AsyncTimeout:    v4.AsyncTimeout,
+			Trace:           v4.Trace,
+			ColorEnabled:    v4.ColorEnabled,
+			Locale:          v4.Locale,
+			PluginRepos:     v4.PluginRepos,
+		}
+		if d.CurrentProfile == "" {
+			d.CurrentProfile = DefaultProfileName
+		}
+		d.Profile = d.CurrentProfile
+		d.setTargetProfile(d.Profiles[d.Profile])
+	default:
 		*d = Data{}
-		return
 	}
 
 	return
 }
+
+// SelectProfile makes the target fields those of the named profile, after
+// keeping those of the profile in use.
+func (d *Data) SelectProfile(name string) error {
+	d.storeProfile()
+
+	target, ok := d.Profiles[name]
+	if !ok {
+		return fmt.Errorf("Profile %s does not exist", name)
+	}
+
+	d.Profile = name
+	d.setTargetProfile(target)
+	return nil
+}
+
+// SaveProfile keeps the target fields as the named profile, which they are
+// those of from then on.
+func (d *Data) SaveProfile(name string) {
+	d.storeProfile()
+	if name != d.Profile {
+		// the copied tokens are stored under a reference of their own
+		d.CredentialsRef = ""
+	}
+	d.Profile = name
+	d.storeProfile()
+}
+
+// storeProfile keeps the target fields in the profile in use.
+func (d *Data) storeProfile() {
+	if d.Profile == "" {
+		d.Profile = DefaultProfileName
+	}
+	if d.CurrentProfile == "" {
+		d.CurrentProfile = d.Profile
+	}
+	if d.Profiles == nil {
+		d.Profiles = make(map[string]TargetProfile)
+	}
+	d.Profiles[d.Profile] = d.targetProfile()
+}
+
+func (d *Data) targetProfile() TargetProfile {
+	return TargetProfile{
+		Target:                   d.Target,
+		APIVersion:               d.APIVersion,
+		AuthorizationEndpoint:    d.AuthorizationEndpoint,
+		LoggregatorEndPoint:      d.LoggregatorEndPoint,
+		DopplerEndPoint:          d.DopplerEndPoint,
+		UaaEndpoint:              d.UaaEndpoint,
+		RoutingAPIEndpoint:       d.RoutingAPIEndpoint,
+		AccessToken:              d.AccessToken,
+		SSHOAuthClient:           d.SSHOAuthClient,
+		RefreshToken:             d.RefreshToken,
+		UAAGrantType:             d.UAAGrantType,
+		UAAOAuthClient:           d.UAAOAuthClient,
+		UAAOAuthClientSecret:     d.UAAOAuthClientSecret,
+		OrganizationFields:       d.OrganizationFields,
+		SpaceFields:              d.SpaceFields,
+		SSLDisabled:              d.SSLDisabled,
+		MinCLIVersion:            d.MinCLIVersion,
+		MinRecommendedCLIVersion: d.MinRecommendedCLIVersion,
+		CredentialsRef:           d.CredentialsRef,
+	}
+}
+
+func (d *Data) setTargetProfile(target TargetProfile) {
+	d.Target = target.Target
+	d.APIVersion = target.APIVersion
+	d.AuthorizationEndpoint = target.AuthorizationEndpoint
+	d.LoggregatorEndPoint = target.LoggregatorEndPoint
+	d.DopplerEndPoint = target.DopplerEndPoint
+	d.UaaEndpoint = target.UaaEndpoint
+	d.RoutingAPIEndpoint = target.RoutingAPIEndpoint
+	d.AccessToken = target.AccessToken
+	d.SSHOAuthClient = target.SSHOAuthClient
+	d.RefreshToken = target.RefreshToken
+	d.UAAGrantType = target.UAAGrantType
+	d.UAAOAuthClient = target.UAAOAuthClient
+	d.UAAOAuthClientSecret = target.UAAOAuthClientSecret
+	d.OrganizationFields = target.OrganizationFields
+	d.SpaceFields = target.SpaceFields
+	d.SSLDisabled = target.SSLDisabled
+	d.MinCLIVersion = target.MinCLIVersion
+	d.MinRecommendedCLIVersion = target.MinRecommendedCLIVersion
+	d.CredentialsRef = target.CredentialsRef
+}
+
+// credentials are the secrets of the target, which are kept in the credential
+// store rather than in the config file when there is one.
+func (t TargetProfile) credentials() credentials.Credentials {
+	return credentials.Credentials{
+		AccessToken:  t.AccessToken,
+		RefreshToken: t.RefreshToken,
+		ClientSecret: t.UAAOAuthClientSecret,
+	}
+}
+
+func (t *TargetProfile) setCredentials(creds credentials.Credentials) {
+	t.AccessToken = creds.AccessToken
+	t.RefreshToken = creds.RefreshToken
+	t.UAAOAuthClientSecret = creds.ClientSecret
+}
@@ -57,6 +57,57 @@ var _ = Describe("V3 Config files", func() {
 		"MinRecommendedCLIVersion": "6.9.0"
 	}`
 
+	var exampleV4JSON = `
+	{
+		"ConfigVersion": 4,
+		"CurrentProfile": "default",
+		"Profiles": {
+			"default": {
+				"Target": "api.example.com",
+				"APIVersion": "3",
+				"AuthorizationEndpoint": "auth.example.com",
+				"LoggregatorEndPoint": "loggregator.example.com",
+				"DopplerEndPoint": "doppler.example.com",
+				"UaaEndpoint": "uaa.example.com",
+				"RoutingAPIEndpoint": "routing-api.example.com",
+				"AccessToken": "the-access-token",
+				"SSHOAuthClient": "ssh-oauth-client-id",
+				"RefreshToken": "the-refresh-token",
+				"OrganizationFields": {
+					"GUID": "the-org-guid",
+					"Name": "the-org",
+					"QuotaDefinition": {
+						"name":"",
+						"memory_limit":0,
+						"instance_memory_limit":0,
+						"total_routes":0,
+						"total_services":0,
+						"non_basic_services_allowed": false,
+						"app_instance_limit":0
+					}
+				},
+				"SpaceFields": {
+					"GUID": "the-space-guid",
+					"Name": "the-space",
+					"AllowSSH": false
+				},
+				"SSLDisabled": true,
+				"MinCLIVersion": "6.0.0",
+				"MinRecommendedCLIVersion": "6.9.0"
+			}
+		},
+		"AsyncTimeout": 1000,
+		"Trace": "path/to/some/file",
+		"ColorEnabled": "true",
+		"Locale": "fr_FR",
+		"PluginRepos": [
+		{
+			"Name": "repo1",
+			"URL": "http://repo.com"
+		}
+		]
+	}`
+
 	// V2 by virtue of ConfigVersion only
 	var exampleV2JSON = `
 	{
@@ -144,7 +195,7 @@ var _ = Describe("V3 Config files", func() {
 
 			re := regexp.MustCompile(`\s+`)
 			actual := re.ReplaceAll(jsonData, []byte{})
-			expected := re.ReplaceAll([]byte(exampleV3JSON), []byte{})
+			expected := re.ReplaceAll([]byte(exampleV4JSON), []byte{})
 			Expect(actual).To(Equal(expected))
 		})
 	})
@@ -156,7 +207,7 @@ var _ = Describe("V3 Config files", func() {
 			Expect(err).To(HaveOccurred())
 		})
 
-		It("creates a config object from valid V3 JSON", func() {
+		It("creates a config object from valid V3 JSON, with its target as the default profile", func() {
 			expectedData := &coreconfig.Data{
 				ConfigVersion:            3,
 				Target:                   "api.example.com",
@@ -192,6 +243,34 @@ var _ = Describe("V3 Config files", func() {
 				},
 			}
 
+			expectedData.Profile = "default"
+			expectedData.CurrentProfile = "default"
+			expectedData.Profiles = map[string]coreconfig.TargetProfile{
+				"default": {
+					Target:                   "api.example.com",
+					APIVersion:               "3",
+					AuthorizationEndpoint:    "auth.example.com",
+					LoggregatorEndPoint:      "loggregator.example.com",
+					RoutingAPIEndpoint:       "routing-api.example.com",
+					DopplerEndPoint:          "doppler.example.com",
+					UaaEndpoint:              "uaa.example.com",
+					AccessToken:              "the-access-token",
+					RefreshToken:             "the-refresh-token",
+					SSHOAuthClient:           "ssh-oauth-client-id",
+					MinCLIVersion:            "6.0.0",
+					MinRecommendedCLIVersion: "6.9.0",
+					OrganizationFields: models.OrganizationFields{
+						GUID: "the-org-guid",
+						Name: "the-org",
+					},
+					SpaceFields: models.SpaceFields{
+						GUID: "the-space-guid",
+						Name: "the-space",
+					},
+					SSLDisabled: true,
+				},
+			}
+
 			actualData := coreconfig.NewData()
 			err := actualData.JSONUnmarshalV3([]byte(exampleV3JSON))
 			Expect(err).NotTo(HaveOccurred())
@@ -199,6 +278,36 @@ var _ = Describe("V3 Config files", func() {
 			Expect(actualData).To(Equal(expectedData))
 		})
 
+		It("reads V4 JSON back the way it was written", func() {
+			v3Data := coreconfig.NewData()
+			err := v3Data.JSONUnmarshalV3([]byte(exampleV3JSON))
+			Expect(err).NotTo(HaveOccurred())
+
+			actualData := coreconfig.NewData()
+			err = actualData.JSONUnmarshalV3([]byte(exampleV4JSON))
+			Expect(err).NotTo(HaveOccurred())
+
+			v3Data.ConfigVersion = 4
+			Expect(actualData).To(Equal(v3Data))
+		})
+
+		It("uses the target of the current profile of V4 JSON", func() {
+			actualData := coreconfig.NewData()
+			err := actualData.JSONUnmarshalV3([]byte(`{
+				"ConfigVersion": 4,
+				"CurrentProfile": "staging",
+				"Profiles": {
+					"default": {"Target": "api.dev.example.com"},
+					"staging": {"Target": "api.staging.example.com", "AccessToken": "staging-token"}
+				}
+			}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(actualData.Profile).To(Equal("staging"))
+			Expect(actualData.Target).To(Equal("api.staging.example.com"))
+			Expect(actualData.AccessToken).To(Equal("staging-token"))
+		})
+
 		It("returns an empty Data object for non-V3 JSON", func() {
 			actualData := coreconfig.NewData()
 			err := actualData.JSONUnmarshalV3([]byte(exampleV2JSON))
@@ -207,4 +316,48 @@ var _ = Describe("V3 Config files", func() {
 			Expect(*actualData).To(Equal(coreconfig.Data{}))
 		})
 	})
+
+	Describe("profiles", func() {
+		var data *coreconfig.Data
+
+		BeforeEach(func() {
+			data = coreconfig.NewData()
+			err := data.JSONUnmarshalV3([]byte(exampleV3JSON))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("saves the target as a new profile that is used from then on", func() {
+			data.SaveProfile("prod")
+			data.Target = "api.prod.example.com"
+
+			_, err := data.JSONMarshalV3()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(data.Profile).To(Equal("prod"))
+			Expect(data.Profiles["prod"].Target).To(Equal("api.prod.example.com"))
+			Expect(data.Profiles["default"].Target).To(Equal("api.example.com"))
+		})
+
+		It("switches between the targets of profiles", func() {
+			data.SaveProfile("prod")
+			data.Target = "api.prod.example.com"
+			data.AccessToken = "prod-token"
+
+			err := data.SelectProfile("default")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data.Target).To(Equal("api.example.com"))
+			Expect(data.AccessToken).To(Equal("the-access-token"))
+
+			err = data.SelectProfile("prod")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data.Target).To(Equal("api.prod.example.com"))
+			Expect(data.AccessToken).To(Equal("prod-token"))
+		})
+
+		It("returns an error when selecting a profile that does not exist", func() {
+			err := data.SelectProfile("staging")
+			Expect(err).To(MatchError("Profile staging does not exist"))
+			Expect(data.Profile).To(Equal("default"))
+		})
+	})
 })
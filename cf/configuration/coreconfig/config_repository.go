@@ -1,11 +1,16 @@
 package coreconfig
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 
 	"github.com/blang/semver"
 	"github.com/cloudfoundry/cli/cf/configuration"
+	"github.com/cloudfoundry/cli/cf/configuration/credentials"
 	"github.com/cloudfoundry/cli/cf/models"
 )
 
@@ -15,6 +20,18 @@ type ConfigRepository struct {
 	initOnce  *sync.Once
 	persistor configuration.Persistor
 	onError   func(error)
+
+	// newCredentialStore makes the store that tokens are kept in instead of
+	// the config file. Without it they are kept in the config file.
+	newCredentialStore  func(kind string) (credentials.Store, error)
+	credentialStore     credentials.Store
+	credentialStoreKind string
+	storedCredentials   map[string]credentials.Credentials
+
+	// envValues override the config for this command only, and with
+	// readOnly nothing is saved.
+	envValues []*envValue
+	readOnly  bool
 }
 
 type CCInfo struct {
@@ -27,11 +44,15 @@ type CCInfo struct {
 	RoutingAPIEndpoint       string `json:"routing_endpoint"`
 }
 
-func NewRepositoryFromFilepath(filepath string, errorHandler func(error)) Repository {
+func NewRepositoryFromFilepath(path string, errorHandler func(error)) Repository {
 	if errorHandler == nil {
 		return nil
 	}
-	return NewRepositoryFromPersistor(configuration.NewDiskPersistor(filepath), errorHandler)
+	repo := NewRepositoryFromPersistor(configuration.NewDiskPersistor(path), errorHandler).(*ConfigRepository)
+	repo.newCredentialStore = func(kind string) (credentials.Store, error) {
+		return credentials.NewStore(kind, filepath.Dir(path), credentials.TerminalPassphrase)
+	}
+	return repo
 }
 
 func NewRepositoryFromPersistor(persistor configuration.Persistor, errorHandler func(error)) Repository {
@@ -50,6 +71,8 @@ func NewRepositoryFromPersistor(persistor configuration.Persistor, errorHandler
 		initOnce:  new(sync.Once),
 		persistor: persistor,
 		onError:   errorHandler,
+
+		storedCredentials: map[string]credentials.Credentials{},
 	}
 }
 
@@ -66,6 +89,10 @@ type Reader interface {
 	AccessToken() string
 	SSHOAuthClient() string
 	RefreshToken() string
+	UAAGrantType() string
+	UAAOAuthClient() string
+	UAAOAuthClientSecret() string
+	CredentialStore() string
 
 	OrganizationFields() models.OrganizationFields
 	HasOrganization() bool
@@ -91,6 +118,9 @@ type Reader interface {
 	Locale() string
 
 	PluginRepos() []models.PluginRepo
+
+	ValueSource(field string) string
+	IsReadOnly() bool
 }
 
 //go:generate counterfeiter . ReadWriter
@@ -109,6 +139,10 @@ type ReadWriter interface {
 	SetAccessToken(string)
 	SetSSHOAuthClient(string)
 	SetRefreshToken(string)
+	SetUAAGrantType(string)
+	SetUAAOAuthClient(string)
+	SetUAAOAuthClientSecret(string)
+	SetCredentialStore(string) error
 	SetOrganizationFields(models.OrganizationFields)
 	SetSpaceFields(models.SpaceFields)
 	SetSSLDisabled(bool)
@@ -120,8 +154,20 @@ type ReadWriter interface {
 	UnSetPluginRepo(int)
 }
 
+// ProfileReadWriter keeps targets as named profiles and switches between them.
+// The getters and setters of ReadWriter work on the profile in use.
+type ProfileReadWriter interface {
+	ProfileName() string
+	ProfileNames() []string
+	Profile(name string) (TargetProfile, bool)
+	SaveProfile(name string)
+	UseProfile(name string) error
+	SelectProfile(name string) error
+}
+
 type Repository interface {
 	ReadWriter
+	ProfileReadWriter
 	Close()
 }
 
@@ -133,6 +179,17 @@ func (c *ConfigRepository) init() {
 		if err != nil {
 			c.onError(err)
 		}
+
+		c.readOnly, err = isReadOnlyFromEnv()
+		if err == nil {
+			c.envValues, err = lookupEnvOverrides(c.data)
+		}
+		if err != nil {
+			c.onError(err)
+		}
+
+		c.keepFileValues()
+		c.applyEnvOverrides()
 	})
 }
 
@@ -149,14 +206,212 @@ func (c *ConfigRepository) write(cb func()) {
 	defer c.mutex.Unlock()
 	c.init()
 
+	c.revertEnvOverrides()
+	defer c.applyEnvOverrides()
+
+	profile := c.data.Profile
 	cb()
+	if c.data.Profile != profile {
+		c.keepFileValues()
+	} else {
+		c.keepEnvChanges()
+	}
+
+	if c.readOnly {
+		return
+	}
 
-	err := c.persistor.Save(c.data)
+	data, err := c.persistedData()
+	if err == nil {
+		err = c.persistor.Save(data)
+	}
 	if err != nil {
 		c.onError(err)
 	}
 }
 
+// ENVIRONMENT OVERRIDES
+
+// The fields overridden by the environment have their values from it, except
+// while writing: then they have those of the config file, which are saved.
+
+func (c *ConfigRepository) applyEnvOverrides() {
+	for _, ev := range c.envValues {
+		ev.set(c.data, ev.value)
+	}
+}
+
+func (c *ConfigRepository) revertEnvOverrides() {
+	for _, ev := range c.envValues {
+		ev.set(c.data, ev.fileValue)
+	}
+
+	for _, ev := range c.envValues {
+		if ev.isCredential() {
+			// resolve the tokens now, so that resolving them while writing is not
+			// taken for a change of the overridden ones
+			c.resolveCredentials()
+			c.keepFileValues()
+			break
+		}
+	}
+}
+
+// keepFileValues takes the values of the overridden fields as those of the
+// config file.
+func (c *ConfigRepository) keepFileValues() {
+	for _, ev := range c.envValues {
+		ev.fileValue = ev.get(c.data)
+	}
+}
+
+// keepEnvChanges keeps what was set to overridden fields as their values for
+// this command, instead of saving it.
+func (c *ConfigRepository) keepEnvChanges() {
+	for _, ev := range c.envValues {
+		if value := ev.get(c.data); value != ev.fileValue {
+			ev.value = value
+			ev.set(c.data, ev.fileValue)
+		}
+	}
+}
+
+// envValue returns the override of the field, or of the org or space it is
+// part of.
+func (c *ConfigRepository) envValue(field string) *envValue {
+	for _, ev := range c.envValues {
+		if ev.Field == field || strings.HasPrefix(field, ev.Field+".") {
+			return ev
+		}
+	}
+	return nil
+}
+
+// CREDENTIALS
+
+func (c *ConfigRepository) store() (credentials.Store, error) {
+	if c.newCredentialStore == nil {
+		return nil, nil
+	}
+
+	kind := c.data.CredentialStore
+	if ev := c.envValue("CredentialStore"); ev != nil {
+		kind = ev.value.(string)
+	}
+
+	if c.credentialStore == nil || c.credentialStoreKind != kind {
+		store, err := c.newCredentialStore(kind)
+		if err != nil {
+			return nil, err
+		}
+		c.credentialStore = store
+		c.credentialStoreKind = kind
+	}
+	return c.credentialStore, nil
+}
+
+// getCredentials returns the credentials kept under ref, asking the store
+// only the first time.
+func (c *ConfigRepository) getCredentials(ref string) (credentials.Credentials, error) {
+	if creds, ok := c.storedCredentials[ref]; ok {
+		return creds, nil
+	}
+
+	store, err := c.store()
+	if err != nil || store == nil {
+		return credentials.Credentials{}, err
+	}
+
+	creds, err := store.Get(ref)
+	if err != nil {
+		return credentials.Credentials{}, err
+	}
+	c.storedCredentials[ref] = creds
+	return creds, nil
+}
+
+// readCredentials is read for what needs the tokens of the target in use,
+// which are only taken from the credential store when they are needed.
+func (c *ConfigRepository) readCredentials(cb func()) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.init()
+	c.resolveCredentials()
+
+	cb()
+}
+
+func (c *ConfigRepository) resolveCredentials() {
+	target := c.data.targetProfile()
+	if target.CredentialsRef == "" || !target.credentials().IsEmpty() {
+		return
+	}
+
+	creds, err := c.getCredentials(target.CredentialsRef)
+	if err != nil {
+		c.onError(err)
+		return
+	}
+	target.setCredentials(creds)
+	c.data.setTargetProfile(target)
+}
+
+// persistedData is what is saved to the config file: with a credential
+// store, the tokens of every profile are stored in it and only their
+// references are kept.
+func (c *ConfigRepository) persistedData() (*Data, error) {
+	store, err := c.store()
+	if err != nil {
+		return nil, err
+	}
+	if store == nil {
+		// a copy, as the values from the environment are put back afterwards
+		data := *c.data
+		return &data, nil
+	}
+
+	c.data.storeProfile()
+
+	data := *c.data
+	data.AccessToken = ""
+	data.RefreshToken = ""
+	data.UAAOAuthClientSecret = ""
+	data.Profiles = make(map[string]TargetProfile, len(c.data.Profiles))
+
+	for name, target := range c.data.Profiles {
+		creds := target.credentials()
+		if !creds.IsEmpty() {
+			if target.CredentialsRef == "" {
+				target.CredentialsRef = newCredentialsRef()
+				c.data.Profiles[name] = target
+				if name == c.data.Profile {
+					c.data.CredentialsRef = target.CredentialsRef
+				}
+			}
+
+			if c.storedCredentials[target.CredentialsRef] != creds {
+				err = store.Store(target.CredentialsRef, creds)
+				if err != nil {
+					return nil, err
+				}
+				c.storedCredentials[target.CredentialsRef] = creds
+			}
+		}
+
+		target.setCredentials(credentials.Credentials{})
+		data.Profiles[name] = target
+	}
+	data.CredentialsRef = c.data.CredentialsRef
+
+	return &data, nil
+}
+
+func newCredentialsRef() string {
+	ref := make([]byte, 16)
+	rand.Read(ref)
+	return hex.EncodeToString(ref)
+}
+
 // CLOSERS
 
 func (c *ConfigRepository) Close() {
@@ -231,7 +486,7 @@ func (c *ConfigRepository) HasAPIEndpoint() (hasEndpoint bool) {
 }
 
 func (c *ConfigRepository) AccessToken() (accessToken string) {
-	c.read(func() {
+	c.readCredentials(func() {
 		accessToken = c.data.AccessToken
 	})
 	return
@@ -245,12 +500,43 @@ func (c *ConfigRepository) SSHOAuthClient() (clientID string) {
 }
 
 func (c *ConfigRepository) RefreshToken() (refreshToken string) {
-	c.read(func() {
+	c.readCredentials(func() {
 		refreshToken = c.data.RefreshToken
 	})
 	return
 }
 
+func (c *ConfigRepository) UAAGrantType() (grantType string) {
+	c.read(func() {
+		grantType = c.data.UAAGrantType
+	})
+	return
+}
+
+func (c *ConfigRepository) UAAOAuthClient() (clientID string) {
+	c.read(func() {
+		clientID = c.data.UAAOAuthClient
+	})
+	return
+}
+
+func (c *ConfigRepository) UAAOAuthClientSecret() (clientSecret string) {
+	c.readCredentials(func() {
+		clientSecret = c.data.UAAOAuthClientSecret
+	})
+	return
+}
+
+func (c *ConfigRepository) CredentialStore() (kind string) {
+	c.read(func() {
+		kind = c.data.CredentialStore
+	})
+	if kind == "" {
+		kind = credentials.KindFile
+	}
+	return
+}
+
 func (c *ConfigRepository) OrganizationFields() (org models.OrganizationFields) {
 	c.read(func() {
 		org = c.data.OrganizationFields
@@ -266,28 +552,33 @@ func (c *ConfigRepository) SpaceFields() (space models.SpaceFields) {
 }
 
 func (c *ConfigRepository) UserEmail() (email string) {
-	c.read(func() {
+	c.readCredentials(func() {
 		email = NewTokenInfo(c.data.AccessToken).Email
 	})
 	return
 }
 
 func (c *ConfigRepository) UserGUID() (guid string) {
-	c.read(func() {
+	c.readCredentials(func() {
 		guid = NewTokenInfo(c.data.AccessToken).UserGUID
 	})
 	return
 }
 
 func (c *ConfigRepository) Username() (name string) {
-	c.read(func() {
-		name = NewTokenInfo(c.data.AccessToken).Username
+	c.readCredentials(func() {
+		info := NewTokenInfo(c.data.AccessToken)
+		name = info.Username
+		if name == "" {
+			// tokens of the client_credentials grant only name the client
+			name = info.ClientID
+		}
 	})
 	return
 }
 
 func (c *ConfigRepository) IsLoggedIn() (loggedIn bool) {
-	c.read(func() {
+	c.readCredentials(func() {
 		loggedIn = c.data.AccessToken != ""
 	})
 	return
@@ -399,12 +690,83 @@ func (c *ConfigRepository) PluginRepos() (repos []models.PluginRepo) {
 	return
 }
 
+// ValueSource returns the environment variable that the field of Data, like
+// "SpaceFields.Name", has its value from, or "" when it is from the config
+// file.
+func (c *ConfigRepository) ValueSource(field string) (envVar string) {
+	c.read(func() {
+		if ev := c.envValue(field); ev != nil {
+			envVar = ev.Name
+		}
+	})
+	return
+}
+
+// IsReadOnly tells whether changes to the config are kept for the command
+// only, rather than saved.
+func (c *ConfigRepository) IsReadOnly() (readOnly bool) {
+	c.read(func() {
+		readOnly = c.readOnly
+	})
+	return
+}
+
+func (c *ConfigRepository) ProfileName() (name string) {
+	c.read(func() {
+		name = c.data.Profile
+	})
+	if name == "" {
+		name = DefaultProfileName
+	}
+	return
+}
+
+func (c *ConfigRepository) ProfileNames() []string {
+	names := []string{c.ProfileName()}
+	c.read(func() {
+		for name := range c.data.Profiles {
+			if name != names[0] {
+				names = append(names, name)
+			}
+		}
+	})
+	sort.Strings(names)
+	return names
+}
+
+func (c *ConfigRepository) Profile(name string) (target TargetProfile, found bool) {
+	inUse := c.ProfileName()
+	c.readCredentials(func() {
+		if name == inUse {
+			target, found = c.data.targetProfile(), true
+			return
+		}
+
+		target, found = c.data.Profiles[name]
+		if found && target.CredentialsRef != "" && target.credentials().IsEmpty() {
+			creds, err := c.getCredentials(target.CredentialsRef)
+			if err != nil {
+				c.onError(err)
+			}
+			target.setCredentials(creds)
+		}
+	})
+	return
+}
+
 // SETTERS
 
 func (c *ConfigRepository) ClearSession() {
 	c.write(func() {
+		if c.data.CredentialsRef != "" {
+			c.eraseCredentials(c.data.CredentialsRef)
+			c.data.CredentialsRef = ""
+		}
 		c.data.AccessToken = ""
 		c.data.RefreshToken = ""
+		c.data.UAAGrantType = ""
+		c.data.UAAOAuthClient = ""
+		c.data.UAAOAuthClientSecret = ""
 		c.data.OrganizationFields = models.OrganizationFields{}
 		c.data.SpaceFields = models.SpaceFields{}
 	})
@@ -466,6 +828,7 @@ func (c *ConfigRepository) SetRoutingAPIEndpoint(routingAPIEndpoint string) {
 
 func (c *ConfigRepository) SetAccessToken(token string) {
 	c.write(func() {
+		c.resolveCredentials()
 		c.data.AccessToken = token
 	})
 }
@@ -478,10 +841,101 @@ func (c *ConfigRepository) SetSSHOAuthClient(clientID string) {
 
 func (c *ConfigRepository) SetRefreshToken(token string) {
 	c.write(func() {
+		c.resolveCredentials()
 		c.data.RefreshToken = token
 	})
 }
 
+func (c *ConfigRepository) SetUAAGrantType(grantType string) {
+	c.write(func() {
+		c.data.UAAGrantType = grantType
+	})
+}
+
+func (c *ConfigRepository) SetUAAOAuthClient(clientID string) {
+	c.write(func() {
+		c.data.UAAOAuthClient = clientID
+	})
+}
+
+func (c *ConfigRepository) SetUAAOAuthClientSecret(clientSecret string) {
+	c.write(func() {
+		c.resolveCredentials()
+		c.data.UAAOAuthClientSecret = clientSecret
+	})
+}
+
+// SetCredentialStore moves the tokens of every profile to the credential
+// store of the given kind.
+func (c *ConfigRepository) SetCredentialStore(kind string) (err error) {
+	if kind == credentials.KindFile {
+		kind = ""
+	}
+
+	var oldStore credentials.Store
+	var moved []string
+
+	c.write(func() {
+		if kind == c.data.CredentialStore {
+			return
+		}
+
+		oldStore, err = c.store()
+		if err != nil {
+			return
+		}
+
+		err = credentials.ValidateKind(kind)
+		if err != nil {
+			return
+		}
+
+		c.resolveCredentials()
+		c.data.storeProfile()
+		for name, target := range c.data.Profiles {
+			if target.CredentialsRef == "" {
+				continue
+			}
+			if target.credentials().IsEmpty() {
+				var creds credentials.Credentials
+				creds, err = c.getCredentials(target.CredentialsRef)
+				if err != nil {
+					return
+				}
+				target.setCredentials(creds)
+				c.data.Profiles[name] = target
+			}
+			delete(c.storedCredentials, target.CredentialsRef)
+			moved = append(moved, target.CredentialsRef)
+		}
+
+		c.data.CredentialStore = kind
+	})
+
+	// the tokens are only erased from the old store once they are in the new one
+	if err == nil && oldStore != nil && !c.readOnly {
+		for _, ref := range moved {
+			oldStore.Erase(ref)
+		}
+	}
+	return
+}
+
+func (c *ConfigRepository) eraseCredentials(ref string) {
+	delete(c.storedCredentials, ref)
+	if c.readOnly {
+		return
+	}
+
+	store, err := c.store()
+	if err == nil && store != nil {
+		err = store.Erase(ref)
+	}
+	if err != nil {
+		c.onError(err)
+	}
+}
+
 func (c *ConfigRepository) SetOrganizationFields(org models.OrganizationFields) {
 	c.write(func() {
 		c.data.OrganizationFields = org
@@ -535,3 +989,39 @@ func (c *ConfigRepository) UnSetPluginRepo(index int) {
 		c.data.PluginRepos = append(c.data.PluginRepos[:index], c.data.PluginRepos[index+1:]...)
 	})
 }
+
+// SaveProfile keeps the target in use as the named profile, which becomes the
+// current one.
+func (c *ConfigRepository) SaveProfile(name string) {
+	c.write(func() {
+		c.resolveCredentials()
+		c.data.SaveProfile(name)
+		c.data.CurrentProfile = name
+	})
+}
+
+// UseProfile makes the named profile the current one.
+func (c *ConfigRepository) UseProfile(name string) (err error) {
+	c.write(func() {
+		err = c.data.SelectProfile(name)
+		if err == nil {
+			c.data.CurrentProfile = name
+		}
+	})
+	return
+}
+
+// SelectProfile uses the named profile until the repository is closed,
+// without making it the current one.
+func (c *ConfigRepository) SelectProfile(name string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.init()
+
+	c.revertEnvOverrides()
+	defer c.applyEnvOverrides()
+
+	err := c.data.SelectProfile(name)
+	c.keepFileValues()
+	return err
+}
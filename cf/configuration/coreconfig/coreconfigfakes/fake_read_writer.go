@@ -76,6 +76,12 @@ type FakeReadWriter struct {
 	refreshTokenReturns     struct {
 		result1 string
 	}
+	CredentialStoreStub        func() string
+	credentialStoreMutex       sync.RWMutex
+	credentialStoreArgsForCall []struct{}
+	credentialStoreReturns     struct {
+		result1 string
+	}
 	OrganizationFieldsStub        func() models.OrganizationFields
 	organizationFieldsMutex       sync.RWMutex
 	organizationFieldsArgsForCall []struct{}
@@ -251,6 +257,14 @@ type FakeReadWriter struct {
 	setRefreshTokenArgsForCall []struct {
 		arg1 string
 	}
+	SetCredentialStoreStub        func(string) error
+	setCredentialStoreMutex       sync.RWMutex
+	setCredentialStoreArgsForCall []struct {
+		arg1 string
+	}
+	setCredentialStoreReturns struct {
+		result1 error
+	}
 	SetOrganizationFieldsStub        func(models.OrganizationFields)
 	setOrganizationFieldsMutex       sync.RWMutex
 	setOrganizationFieldsArgsForCall []struct {
@@ -296,6 +310,53 @@ type FakeReadWriter struct {
 	unSetPluginRepoArgsForCall []struct {
 		arg1 int
 	}
+	UAAGrantTypeStub        func() string
+	uAAGrantTypeMutex       sync.RWMutex
+	uAAGrantTypeArgsForCall []struct{}
+	uAAGrantTypeReturns     struct {
+		result1 string
+	}
+	UAAOAuthClientStub        func() string
+	uAAOAuthClientMutex       sync.RWMutex
+	uAAOAuthClientArgsForCall []struct{}
+	uAAOAuthClientReturns     struct {
+		result1 string
+	}
+	UAAOAuthClientSecretStub        func() string
+	uAAOAuthClientSecretMutex       sync.RWMutex
+	uAAOAuthClientSecretArgsForCall []struct{}
+	uAAOAuthClientSecretReturns     struct {
+		result1 string
+	}
+	SetUAAGrantTypeStub        func(string)
+	setUAAGrantTypeMutex       sync.RWMutex
+	setUAAGrantTypeArgsForCall []struct {
+		arg1 string
+	}
+	SetUAAOAuthClientStub        func(string)
+	setUAAOAuthClientMutex       sync.RWMutex
+	setUAAOAuthClientArgsForCall []struct {
+		arg1 string
+	}
+	SetUAAOAuthClientSecretStub        func(string)
+	setUAAOAuthClientSecretMutex       sync.RWMutex
+	setUAAOAuthClientSecretArgsForCall []struct {
+		arg1 string
+	}
+	ValueSourceStub        func(field string) string
+	valueSourceMutex       sync.RWMutex
+	valueSourceArgsForCall []struct {
+		field string
+	}
+	valueSourceReturns struct {
+		result1 string
+	}
+	IsReadOnlyStub        func() bool
+	isReadOnlyMutex       sync.RWMutex
+	isReadOnlyArgsForCall []struct{}
+	isReadOnlyReturns     struct {
+		result1 bool
+	}
 }
 
 func (fake *FakeReadWriter) APIEndpoint() string {
@@ -562,6 +623,30 @@ func (fake *FakeReadWriter) RefreshTokenReturns(result1 string) {
 	}{result1}
 }
 
+func (fake *FakeReadWriter) CredentialStore() string {
+	fake.credentialStoreMutex.Lock()
+	fake.credentialStoreArgsForCall = append(fake.credentialStoreArgsForCall, struct{}{})
+	fake.credentialStoreMutex.Unlock()
+	if fake.CredentialStoreStub != nil {
+		return fake.CredentialStoreStub()
+	} else {
+		return fake.credentialStoreReturns.result1
+	}
+}
+
+func (fake *FakeReadWriter) CredentialStoreCallCount() int {
+	fake.credentialStoreMutex.RLock()
+	defer fake.credentialStoreMutex.RUnlock()
+	return len(fake.credentialStoreArgsForCall)
+}
+
+func (fake *FakeReadWriter) CredentialStoreReturns(result1 string) {
+	fake.CredentialStoreStub = nil
+	fake.credentialStoreReturns = struct {
+		result1 string
+	}{result1}
+}
+
 func (fake *FakeReadWriter) OrganizationFields() models.OrganizationFields {
 	fake.organizationFieldsMutex.Lock()
 	fake.organizationFieldsArgsForCall = append(fake.organizationFieldsArgsForCall, struct{}{})
@@ -1301,6 +1386,38 @@ func (fake *FakeReadWriter) SetRefreshTokenArgsForCall(i int) string {
 	return fake.setRefreshTokenArgsForCall[i].arg1
 }
 
+func (fake *FakeReadWriter) SetCredentialStore(arg1 string) error {
+	fake.setCredentialStoreMutex.Lock()
+	fake.setCredentialStoreArgsForCall = append(fake.setCredentialStoreArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.setCredentialStoreMutex.Unlock()
+	if fake.SetCredentialStoreStub != nil {
+		return fake.SetCredentialStoreStub(arg1)
+	} else {
+		return fake.setCredentialStoreReturns.result1
+	}
+}
+
+func (fake *FakeReadWriter) SetCredentialStoreCallCount() int {
+	fake.setCredentialStoreMutex.RLock()
+	defer fake.setCredentialStoreMutex.RUnlock()
+	return len(fake.setCredentialStoreArgsForCall)
+}
+
+func (fake *FakeReadWriter) SetCredentialStoreArgsForCall(i int) string {
+	fake.setCredentialStoreMutex.RLock()
+	defer fake.setCredentialStoreMutex.RUnlock()
+	return fake.setCredentialStoreArgsForCall[i].arg1
+}
+
+func (fake *FakeReadWriter) SetCredentialStoreReturns(result1 error) {
+	fake.SetCredentialStoreStub = nil
+	fake.setCredentialStoreReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeReadWriter) SetOrganizationFields(arg1 models.OrganizationFields) {
 	fake.setOrganizationFieldsMutex.Lock()
 	fake.setOrganizationFieldsArgsForCall = append(fake.setOrganizationFieldsArgsForCall, struct {
@@ -1508,4 +1625,201 @@ func (fake *FakeReadWriter) UnSetPluginRepoArgsForCall(i int) int {
 	return fake.unSetPluginRepoArgsForCall[i].arg1
 }
 
+func (fake *FakeReadWriter) UAAGrantType() string {
+	fake.uAAGrantTypeMutex.Lock()
+	fake.uAAGrantTypeArgsForCall = append(fake.uAAGrantTypeArgsForCall, struct{}{})
+	fake.uAAGrantTypeMutex.Unlock()
+	if fake.UAAGrantTypeStub != nil {
+		return fake.UAAGrantTypeStub()
+	} else {
+		return fake.uAAGrantTypeReturns.result1
+	}
+}
+
+func (fake *FakeReadWriter) UAAGrantTypeCallCount() int {
+	fake.uAAGrantTypeMutex.RLock()
+	defer fake.uAAGrantTypeMutex.RUnlock()
+	return len(fake.uAAGrantTypeArgsForCall)
+}
+
+func (fake *FakeReadWriter) UAAGrantTypeReturns(result1 string) {
+	fake.UAAGrantTypeStub = nil
+	fake.uAAGrantTypeReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeReadWriter) UAAOAuthClient() string {
+	fake.uAAOAuthClientMutex.Lock()
+	fake.uAAOAuthClientArgsForCall = append(fake.uAAOAuthClientArgsForCall, struct{}{})
+	fake.uAAOAuthClientMutex.Unlock()
+	if fake.UAAOAuthClientStub != nil {
+		return fake.UAAOAuthClientStub()
+	} else {
+		return fake.uAAOAuthClientReturns.result1
+	}
+}
+
+func (fake *FakeReadWriter) UAAOAuthClientCallCount() int {
+	fake.uAAOAuthClientMutex.RLock()
+	defer fake.uAAOAuthClientMutex.RUnlock()
+	return len(fake.uAAOAuthClientArgsForCall)
+}
+
+func (fake *FakeReadWriter) UAAOAuthClientReturns(result1 string) {
+	fake.UAAOAuthClientStub = nil
+	fake.uAAOAuthClientReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeReadWriter) UAAOAuthClientSecret() string {
+	fake.uAAOAuthClientSecretMutex.Lock()
+	fake.uAAOAuthClientSecretArgsForCall = append(fake.uAAOAuthClientSecretArgsForCall, struct{}{})
+	fake.uAAOAuthClientSecretMutex.Unlock()
+	if fake.UAAOAuthClientSecretStub != nil {
+		return fake.UAAOAuthClientSecretStub()
+	} else {
+		return fake.uAAOAuthClientSecretReturns.result1
+	}
+}
+
+func (fake *FakeReadWriter) UAAOAuthClientSecretCallCount() int {
+	fake.uAAOAuthClientSecretMutex.RLock()
+	defer fake.uAAOAuthClientSecretMutex.RUnlock()
+	return len(fake.uAAOAuthClientSecretArgsForCall)
+}
+
+func (fake *FakeReadWriter) UAAOAuthClientSecretReturns(result1 string) {
+	fake.UAAOAuthClientSecretStub = nil
+	fake.uAAOAuthClientSecretReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeReadWriter) SetUAAGrantType(arg1 string) {
+	fake.setUAAGrantTypeMutex.Lock()
+	fake.setUAAGrantTypeArgsForCall = append(fake.setUAAGrantTypeArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.setUAAGrantTypeMutex.Unlock()
+	if fake.SetUAAGrantTypeStub != nil {
+		fake.SetUAAGrantTypeStub(arg1)
+	}
+}
+
+func (fake *FakeReadWriter) SetUAAGrantTypeCallCount() int {
+	fake.setUAAGrantTypeMutex.RLock()
+	defer fake.setUAAGrantTypeMutex.RUnlock()
+	return len(fake.setUAAGrantTypeArgsForCall)
+}
+
+func (fake *FakeReadWriter) SetUAAGrantTypeArgsForCall(i int) string {
+	fake.setUAAGrantTypeMutex.RLock()
+	defer fake.setUAAGrantTypeMutex.RUnlock()
+	return fake.setUAAGrantTypeArgsForCall[i].arg1
+}
+
+func (fake *FakeReadWriter) SetUAAOAuthClient(arg1 string) {
+	fake.setUAAOAuthClientMutex.Lock()
+	fake.setUAAOAuthClientArgsForCall = append(fake.setUAAOAuthClientArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.setUAAOAuthClientMutex.Unlock()
+	if fake.SetUAAOAuthClientStub != nil {
+		fake.SetUAAOAuthClientStub(arg1)
+	}
+}
+
+func (fake *FakeReadWriter) SetUAAOAuthClientCallCount() int {
+	fake.setUAAOAuthClientMutex.RLock()
+	defer fake.setUAAOAuthClientMutex.RUnlock()
+	return len(fake.setUAAOAuthClientArgsForCall)
+}
+
+func (fake *FakeReadWriter) SetUAAOAuthClientArgsForCall(i int) string {
+	fake.setUAAOAuthClientMutex.RLock()
+	defer fake.setUAAOAuthClientMutex.RUnlock()
+	return fake.setUAAOAuthClientArgsForCall[i].arg1
+}
+
+func (fake *FakeReadWriter) SetUAAOAuthClientSecret(arg1 string) {
+	fake.setUAAOAuthClientSecretMutex.Lock()
+	fake.setUAAOAuthClientSecretArgsForCall = append(fake.setUAAOAuthClientSecretArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.setUAAOAuthClientSecretMutex.Unlock()
+	if fake.SetUAAOAuthClientSecretStub != nil {
+		fake.SetUAAOAuthClientSecretStub(arg1)
+	}
+}
+
+func (fake *FakeReadWriter) SetUAAOAuthClientSecretCallCount() int {
+	fake.setUAAOAuthClientSecretMutex.RLock()
+	defer fake.setUAAOAuthClientSecretMutex.RUnlock()
+	return len(fake.setUAAOAuthClientSecretArgsForCall)
+}
+
+func (fake *FakeReadWriter) SetUAAOAuthClientSecretArgsForCall(i int) string {
+	fake.setUAAOAuthClientSecretMutex.RLock()
+	defer fake.setUAAOAuthClientSecretMutex.RUnlock()
+	return fake.setUAAOAuthClientSecretArgsForCall[i].arg1
+}
+
+func (fake *FakeReadWriter) ValueSource(field string) string {
+	fake.valueSourceMutex.Lock()
+	fake.valueSourceArgsForCall = append(fake.valueSourceArgsForCall, struct {
+		field string
+	}{field})
+	fake.valueSourceMutex.Unlock()
+	if fake.ValueSourceStub != nil {
+		return fake.ValueSourceStub(field)
+	} else {
+		return fake.valueSourceReturns.result1
+	}
+}
+
+func (fake *FakeReadWriter) ValueSourceCallCount() int {
+	fake.valueSourceMutex.RLock()
+	defer fake.valueSourceMutex.RUnlock()
+	return len(fake.valueSourceArgsForCall)
+}
+
+func (fake *FakeReadWriter) ValueSourceArgsForCall(i int) string {
+	fake.valueSourceMutex.RLock()
+	defer fake.valueSourceMutex.RUnlock()
+	return fake.valueSourceArgsForCall[i].field
+}
+
+func (fake *FakeReadWriter) ValueSourceReturns(result1 string) {
+	fake.ValueSourceStub = nil
+	fake.valueSourceReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeReadWriter) IsReadOnly() bool {
+	fake.isReadOnlyMutex.Lock()
+	fake.isReadOnlyArgsForCall = append(fake.isReadOnlyArgsForCall, struct{}{})
+	fake.isReadOnlyMutex.Unlock()
+	if fake.IsReadOnlyStub != nil {
+		return fake.IsReadOnlyStub()
+	} else {
+		return fake.isReadOnlyReturns.result1
+	}
+}
+
+func (fake *FakeReadWriter) IsReadOnlyCallCount() int {
+	fake.isReadOnlyMutex.RLock()
+	defer fake.isReadOnlyMutex.RUnlock()
+	return len(fake.isReadOnlyArgsForCall)
+}
+
+func (fake *FakeReadWriter) IsReadOnlyReturns(result1 bool) {
+	fake.IsReadOnlyStub = nil
+	fake.isReadOnlyReturns = struct {
+		result1 bool
+	}{result1}
+}
+
 var _ coreconfig.ReadWriter = new(FakeReadWriter)
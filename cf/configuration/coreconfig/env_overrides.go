@@ -0,0 +1,151 @@
+package coreconfig
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/cloudfoundry/cli/cf/models"
+)
+
+// ReadOnlyEnvVar makes the config read-only when true: settings can still
+// be changed for the command, but nothing is written back to disk.
+const ReadOnlyEnvVar = "CF_READ_ONLY_CONFIG"
+
+// EnvOverride is an environment variable that overrides a field of Data, as
+// a path like "SpaceFields", for as long as it is set. The config file keeps
+// its own value. An org or space is overridden as a whole, by its name and
+// the variable GUIDName for its GUID, which must be set together.
+type EnvOverride struct {
+	Name     string
+	Field    string
+	GUIDName string
+}
+
+// EnvOverrides are all of the environment variables that override the config.
+var EnvOverrides = []EnvOverride{
+	{Name: "CF_API", Field: "Target"},
+	{Name: "CF_API_VERSION", Field: "APIVersion"},
+	{Name: "CF_AUTHORIZATION_ENDPOINT", Field: "AuthorizationEndpoint"},
+	{Name: "CF_UAA_ENDPOINT", Field: "UaaEndpoint"},
+	{Name: "CF_LOGGREGATOR_ENDPOINT", Field: "LoggregatorEndPoint"},
+	{Name: "CF_DOPPLER_ENDPOINT", Field: "DopplerEndPoint"},
+	{Name: "CF_ROUTING_API_ENDPOINT", Field: "RoutingAPIEndpoint"},
+	{Name: "CF_ACCESS_TOKEN", Field: "AccessToken"},
+	{Name: "CF_REFRESH_TOKEN", Field: "RefreshToken"},
+	{Name: "CF_SSH_OAUTH_CLIENT", Field: "SSHOAuthClient"},
+	{Name: "CF_UAA_GRANT_TYPE", Field: "UAAGrantType"},
+	{Name: "CF_UAA_CLIENT", Field: "UAAOAuthClient"},
+	{Name: "CF_UAA_CLIENT_SECRET", Field: "UAAOAuthClientSecret"},
+	{Name: "CF_ORG", Field: "OrganizationFields", GUIDName: "CF_ORG_GUID"},
+	{Name: "CF_SPACE", Field: "SpaceFields", GUIDName: "CF_SPACE_GUID"},
+	{Name: "CF_SKIP_SSL_VALIDATION", Field: "SSLDisabled"},
+	{Name: "CF_MIN_CLI_VERSION", Field: "MinCLIVersion"},
+	{Name: "CF_MIN_RECOMMENDED_CLI_VERSION", Field: "MinRecommendedCLIVersion"},
+	{Name: "CF_ASYNC_TIMEOUT", Field: "AsyncTimeout"},
+	{Name: "CF_TRACE", Field: "Trace"},
+	{Name: "CF_COLOR", Field: "ColorEnabled"},
+	{Name: "CF_LOCALE", Field: "Locale"},
+	{Name: "CF_CREDENTIAL_STORE", Field: "CredentialStore"},
+}
+
+// envValue is an override in effect: the value the field has for this
+// command, and the one it has in the config file.
+type envValue struct {
+	EnvOverride
+	value     interface{}
+	fileValue interface{}
+}
+
+// lookupEnvOverrides returns the overrides set in the environment, with
+// their values converted to the types of their fields.
+func lookupEnvOverrides(data *Data) ([]*envValue, error) {
+	values := []*envValue{}
+	for _, override := range EnvOverrides {
+		raw, ok := os.LookupEnv(override.Name)
+		if override.GUIDName != "" {
+			_, hasGUID := os.LookupEnv(override.GUIDName)
+			if ok != hasGUID {
+				return nil, fmt.Errorf("%s and %s must be set together", override.Name, override.GUIDName)
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		value, err := override.parse(data, raw)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, &envValue{EnvOverride: override, value: value})
+	}
+	return values, nil
+}
+
+func (o EnvOverride) isCredential() bool {
+	return o.Field == "AccessToken" || o.Field == "RefreshToken" || o.Field == "UAAOAuthClientSecret"
+}
+
+func (o EnvOverride) parse(data *Data, raw string) (interface{}, error) {
+	field := o.field(data)
+	switch field.Kind() {
+	case reflect.Bool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false, not %q", o.Name, raw)
+		}
+		return value, nil
+	case reflect.Uint:
+		value, err := strconv.ParseUint(raw, 10, 0)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a positive number, not %q", o.Name, raw)
+		}
+		return uint(value), nil
+	case reflect.Struct:
+		guid := os.Getenv(o.GUIDName)
+		if raw == "" || guid == "" {
+			return nil, fmt.Errorf("%s and %s must not be empty", o.Name, o.GUIDName)
+		}
+		if o.Field == "OrganizationFields" {
+			return models.OrganizationFields{Name: raw, GUID: guid}, nil
+		}
+		return models.SpaceFields{Name: raw, GUID: guid}, nil
+	default:
+		if o.Field == "AccessToken" && raw != "" && !strings.Contains(raw, " ") {
+			// the token alone, as UAA returns it
+			raw = "bearer " + raw
+		}
+		return raw, nil
+	}
+}
+
+func (o EnvOverride) field(data *Data) reflect.Value {
+	field := reflect.ValueOf(data).Elem()
+	for _, name := range strings.Split(o.Field, ".") {
+		field = field.FieldByName(name)
+	}
+	return field
+}
+
+func (o EnvOverride) get(data *Data) interface{} {
+	return o.field(data).Interface()
+}
+
+func (o EnvOverride) set(data *Data, value interface{}) {
+	o.field(data).Set(reflect.ValueOf(value))
+}
+
+func isReadOnlyFromEnv() (bool, error) {
+	raw := os.Getenv(ReadOnlyEnvVar)
+	if raw == "" {
+		return false, nil
+	}
+
+	readOnly, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("%s must be true or false, not %q", ReadOnlyEnvVar, raw)
+	}
+	return readOnly, nil
+}
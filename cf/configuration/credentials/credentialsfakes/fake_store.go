@@ -0,0 +1,137 @@
+// This file was generated by counterfeiter
+package credentialsfakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry/cli/cf/configuration/credentials"
+)
+
+type FakeStore struct {
+	GetStub        func(ref string) (credentials.Credentials, error)
+	getMutex       sync.RWMutex
+	getArgsForCall []struct {
+		ref string
+	}
+	getReturns struct {
+		result1 credentials.Credentials
+		result2 error
+	}
+	StoreStub        func(ref string, creds credentials.Credentials) error
+	storeMutex       sync.RWMutex
+	storeArgsForCall []struct {
+		ref   string
+		creds credentials.Credentials
+	}
+	storeReturns struct {
+		result1 error
+	}
+	EraseStub        func(ref string) error
+	eraseMutex       sync.RWMutex
+	eraseArgsForCall []struct {
+		ref string
+	}
+	eraseReturns struct {
+		result1 error
+	}
+}
+
+func (fake *FakeStore) Get(ref string) (credentials.Credentials, error) {
+	fake.getMutex.Lock()
+	fake.getArgsForCall = append(fake.getArgsForCall, struct {
+		ref string
+	}{ref})
+	fake.getMutex.Unlock()
+	if fake.GetStub != nil {
+		return fake.GetStub(ref)
+	} else {
+		return fake.getReturns.result1, fake.getReturns.result2
+	}
+}
+
+func (fake *FakeStore) GetCallCount() int {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return len(fake.getArgsForCall)
+}
+
+func (fake *FakeStore) GetArgsForCall(i int) string {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return fake.getArgsForCall[i].ref
+}
+
+func (fake *FakeStore) GetReturns(result1 credentials.Credentials, result2 error) {
+	fake.GetStub = nil
+	fake.getReturns = struct {
+		result1 credentials.Credentials
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) Store(ref string, creds credentials.Credentials) error {
+	fake.storeMutex.Lock()
+	fake.storeArgsForCall = append(fake.storeArgsForCall, struct {
+		ref   string
+		creds credentials.Credentials
+	}{ref, creds})
+	fake.storeMutex.Unlock()
+	if fake.StoreStub != nil {
+		return fake.StoreStub(ref, creds)
+	} else {
+		return fake.storeReturns.result1
+	}
+}
+
+func (fake *FakeStore) StoreCallCount() int {
+	fake.storeMutex.RLock()
+	defer fake.storeMutex.RUnlock()
+	return len(fake.storeArgsForCall)
+}
+
+func (fake *FakeStore) StoreArgsForCall(i int) (string, credentials.Credentials) {
+	fake.storeMutex.RLock()
+	defer fake.storeMutex.RUnlock()
+	return fake.storeArgsForCall[i].ref, fake.storeArgsForCall[i].creds
+}
+
+func (fake *FakeStore) StoreReturns(result1 error) {
+	fake.StoreStub = nil
+	fake.storeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) Erase(ref string) error {
+	fake.eraseMutex.Lock()
+	fake.eraseArgsForCall = append(fake.eraseArgsForCall, struct {
+		ref string
+	}{ref})
+	fake.eraseMutex.Unlock()
+	if fake.EraseStub != nil {
+		return fake.EraseStub(ref)
+	} else {
+		return fake.eraseReturns.result1
+	}
+}
+
+func (fake *FakeStore) EraseCallCount() int {
+	fake.eraseMutex.RLock()
+	defer fake.eraseMutex.RUnlock()
+	return len(fake.eraseArgsForCall)
+}
+
+func (fake *FakeStore) EraseArgsForCall(i int) string {
+	fake.eraseMutex.RLock()
+	defer fake.eraseMutex.RUnlock()
+	return fake.eraseArgsForCall[i].ref
+}
+
+func (fake *FakeStore) EraseReturns(result1 error) {
+	fake.EraseStub = nil
+	fake.eraseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+var _ credentials.Store = new(FakeStore)
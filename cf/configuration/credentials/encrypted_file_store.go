@@ -0,0 +1,265 @@
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/cloudfoundry/cli/cf/configuration"
+	"golang.org/x/crypto/scrypt"
+)
+
+// The scrypt parameters of new files, as recommended for interactive use.
+const (
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	saltLength   = 32
+	aesKeyLength = 32
+)
+
+// The largest scrypt parameters a file may ask for. The key derivation takes
+// 128*N*R bytes of memory, which a corrupt file must not be able to make
+// unbounded.
+const (
+	maxScryptMemory = 256 << 20
+	maxScryptP      = 16
+)
+
+// passphraseCheck is sealed in every file, so that a wrong passphrase is
+// noticed before an entry is sealed with the key derived from it.
+var passphraseCheck = []byte("cf credentials")
+
+const passphraseCheckRef = "passphrase-check"
+
+// encryptedFile is the layout of the file of an encrypted store. The key is
+// derived from the passphrase with scrypt, and every entry is sealed with
+// AES-GCM, its reference being the additional data. Check is
+// passphraseCheck, sealed the same way.
+type encryptedFile struct {
+	Salt    []byte
+	N       int
+	R       int
+	P       int
+	Check   []byte `json:",omitempty"`
+	Entries map[string][]byte
+}
+
+type encryptedFileStore struct {
+	path       string
+	passphrase func() (string, error)
+
+	salt []byte
+	aead cipher.AEAD
+}
+
+// NewEncryptedFileStore returns a store that keeps credentials in a file,
+// encrypted with a key derived from the passphrase. The passphrase is only
+// asked for once credentials are read or written.
+func NewEncryptedFileStore(path string, passphrase func() (string, error)) Store {
+	return &encryptedFileStore{path: path, passphrase: passphrase}
+}
+
+func (s *encryptedFileStore) Get(ref string) (Credentials, error) {
+	file, err := s.read()
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	sealed, ok := file.Entries[ref]
+	if !ok {
+		return Credentials{}, nil
+	}
+
+	aead, err := s.cipher(file)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	plaintext, err := open(aead, sealed, ref)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	credentials := Credentials{}
+	err = json.Unmarshal(plaintext, &credentials)
+	return credentials, err
+}
+
+func (s *encryptedFileStore) Store(ref string, credentials Credentials) error {
+	unlock := configuration.LockFile(s.path)
+	defer unlock()
+
+	file, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	aead, err := s.cipher(file)
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := json.Marshal(credentials)
+	if err != nil {
+		return err
+	}
+
+	file.Entries[ref], err = seal(aead, plaintext, ref)
+	if err != nil {
+		return err
+	}
+	return s.write(file)
+}
+
+func (s *encryptedFileStore) Erase(ref string) error {
+	unlock := configuration.LockFile(s.path)
+	defer unlock()
+
+	file, err := s.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := file.Entries[ref]; !ok {
+		return nil
+	}
+	delete(file.Entries, ref)
+	return s.write(file)
+}
+
+// cipher returns the AEAD of the file, deriving its key from the passphrase
+// the first time and checking it against the file. A new file gets a new
+// salt.
+func (s *encryptedFileStore) cipher(file *encryptedFile) (cipher.AEAD, error) {
+	if file.Salt == nil {
+		file.Salt = make([]byte, saltLength)
+		_, err := io.ReadFull(rand.Reader, file.Salt)
+		if err != nil {
+			return nil, err
+		}
+		file.N, file.R, file.P = scryptN, scryptR, scryptP
+	}
+
+	if s.aead != nil && string(s.salt) == string(file.Salt) {
+		return s.aead, nil
+	}
+
+	if !validScryptParameters(file.N, file.R, file.P) {
+		return nil, errors.New("Unable to decrypt credentials: the credentials file is corrupt")
+	}
+
+	passphrase, err := s.passphrase()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := scrypt.Key([]byte(passphrase), file.Salt, file.N, file.R, file.P, aesKeyLength)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkPassphrase(aead, file)
+	if err != nil {
+		return nil, err
+	}
+
+	s.salt, s.aead = file.Salt, aead
+	return aead, nil
+}
+
+// checkPassphrase opens the check value of the file, or an entry of files
+// written before there was one, with aead. A file without either is given a
+// check value.
+func checkPassphrase(aead cipher.AEAD, file *encryptedFile) error {
+	if file.Check != nil {
+		_, err := open(aead, file.Check, passphraseCheckRef)
+		return err
+	}
+
+	for ref, sealed := range file.Entries {
+		_, err := open(aead, sealed, ref)
+		if err != nil {
+			return err
+		}
+		break
+	}
+
+	check, err := seal(aead, passphraseCheck, passphraseCheckRef)
+	if err != nil {
+		return err
+	}
+	file.Check = check
+	return nil
+}
+
+func validScryptParameters(n, r, p int) bool {
+	if n <= 1 || n&(n-1) != 0 || r <= 0 || p <= 0 || p > maxScryptP {
+		return false
+	}
+	return n <= maxScryptMemory/128/r
+}
+
+func seal(aead cipher.AEAD, plaintext []byte, ref string) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	_, err := io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, []byte(ref)), nil
+}
+
+func open(aead cipher.AEAD, sealed []byte, ref string) ([]byte, error) {
+	nonceSize := aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, errors.New("Unable to decrypt credentials: the credentials file is corrupt")
+	}
+
+	plaintext, err := aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], []byte(ref))
+	if err != nil {
+		return nil, errors.New("Unable to decrypt credentials: the passphrase is wrong or the credentials file is corrupt")
+	}
+	return plaintext, nil
+}
+
+func (s *encryptedFileStore) read() (*encryptedFile, error) {
+	file := &encryptedFile{}
+
+	bytes, err := ioutil.ReadFile(s.path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		err = json.Unmarshal(bytes, file)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if file.Entries == nil {
+		file.Entries = map[string][]byte{}
+	}
+	return file, nil
+}
+
+func (s *encryptedFileStore) write(file *encryptedFile) error {
+	bytes, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	return configuration.ReplaceFile(s.path, bytes)
+}
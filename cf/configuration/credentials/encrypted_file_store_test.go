@@ -0,0 +1,136 @@
+package credentials_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/cli/cf/configuration/credentials"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EncryptedFileStore", func() {
+	var (
+		dir              string
+		path             string
+		passphrase       string
+		passphraseAsked  int
+		passphraseGetter func() (string, error)
+		store            credentials.Store
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "credentials")
+		Expect(err).NotTo(HaveOccurred())
+
+		path = filepath.Join(dir, "credentials.enc.json")
+		passphrase = "correct horse battery staple"
+		passphraseAsked = 0
+		passphraseGetter = func() (string, error) {
+			passphraseAsked++
+			return passphrase, nil
+		}
+		store = credentials.NewEncryptedFileStore(path, passphraseGetter)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("does not ask for the passphrase when there are no credentials", func() {
+		creds, err := store.Get("ref-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(creds.IsEmpty()).To(BeTrue())
+		Expect(passphraseAsked).To(Equal(0))
+	})
+
+	Context("when credentials are stored", func() {
+		BeforeEach(func() {
+			err := store.Store("ref-1", credentials.Credentials{AccessToken: "access-1", RefreshToken: "refresh-1"})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("does not write the tokens in the clear", func() {
+			content, err := ioutil.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).NotTo(ContainSubstring("access-1"))
+			Expect(string(content)).NotTo(ContainSubstring("refresh-1"))
+		})
+
+		It("reads them back with the passphrase", func() {
+			creds, err := credentials.NewEncryptedFileStore(path, passphraseGetter).Get("ref-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(creds).To(Equal(credentials.Credentials{AccessToken: "access-1", RefreshToken: "refresh-1"}))
+		})
+
+		It("asks for the passphrase once", func() {
+			_, err := store.Get("ref-1")
+			Expect(err).NotTo(HaveOccurred())
+			err = store.Store("ref-2", credentials.Credentials{AccessToken: "access-2"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(passphraseAsked).To(Equal(1))
+		})
+
+		It("erases them", func() {
+			err := store.Erase("ref-1")
+			Expect(err).NotTo(HaveOccurred())
+
+			creds, err := store.Get("ref-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(creds.IsEmpty()).To(BeTrue())
+		})
+
+		It("fails with the wrong passphrase", func() {
+			passphrase = "wrong"
+			_, err := credentials.NewEncryptedFileStore(path, passphraseGetter).Get("ref-1")
+			Expect(err).To(MatchError(ContainSubstring("passphrase is wrong")))
+		})
+
+		It("refuses to store more credentials with the wrong passphrase", func() {
+			passphrase = "wrong"
+			err := credentials.NewEncryptedFileStore(path, passphraseGetter).Store("ref-2", credentials.Credentials{AccessToken: "access-2"})
+			Expect(err).To(MatchError(ContainSubstring("passphrase is wrong")))
+
+			passphrase = "correct horse battery staple"
+			creds, err := credentials.NewEncryptedFileStore(path, passphraseGetter).Get("ref-2")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(creds.IsEmpty()).To(BeTrue())
+		})
+
+		It("refuses to store credentials with the wrong passphrase once they were all erased", func() {
+			Expect(store.Erase("ref-1")).To(Succeed())
+
+			passphrase = "wrong"
+			err := credentials.NewEncryptedFileStore(path, passphraseGetter).Store("ref-2", credentials.Credentials{AccessToken: "access-2"})
+			Expect(err).To(MatchError(ContainSubstring("passphrase is wrong")))
+		})
+
+		It("fails without deriving a key when the file asks for too much memory", func() {
+			content, err := ioutil.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			file := map[string]interface{}{}
+			Expect(json.Unmarshal(content, &file)).To(Succeed())
+			file["N"] = 1 << 30
+			content, err = json.Marshal(file)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ioutil.WriteFile(path, content, 0600)).To(Succeed())
+
+			_, err = credentials.NewEncryptedFileStore(path, passphraseGetter).Get("ref-1")
+			Expect(err).To(MatchError(ContainSubstring("the credentials file is corrupt")))
+			Expect(passphraseAsked).To(Equal(1))
+		})
+
+		It("fails when the passphrase cannot be read", func() {
+			_, err := credentials.NewEncryptedFileStore(path, func() (string, error) {
+				return "", errors.New("no terminal")
+			}).Get("ref-1")
+			Expect(err).To(MatchError("no terminal"))
+		})
+	})
+})
@@ -0,0 +1,77 @@
+package credentials
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+
+	"github.com/cloudfoundry/cli/cf/configuration"
+)
+
+type fileStore struct {
+	path string
+}
+
+// NewFileStore returns a store that keeps credentials in a file only the
+// user can read.
+func NewFileStore(path string) Store {
+	return &fileStore{path: path}
+}
+
+func (s *fileStore) Get(ref string) (Credentials, error) {
+	entries, err := s.read()
+	if err != nil {
+		return Credentials{}, err
+	}
+	return entries[ref], nil
+}
+
+func (s *fileStore) Store(ref string, credentials Credentials) error {
+	unlock := configuration.LockFile(s.path)
+	defer unlock()
+
+	entries, err := s.read()
+	if err != nil {
+		return err
+	}
+	entries[ref] = credentials
+	return s.write(entries)
+}
+
+func (s *fileStore) Erase(ref string) error {
+	unlock := configuration.LockFile(s.path)
+	defer unlock()
+
+	entries, err := s.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := entries[ref]; !ok {
+		return nil
+	}
+	delete(entries, ref)
+	return s.write(entries)
+}
+
+func (s *fileStore) read() (map[string]Credentials, error) {
+	entries := map[string]Credentials{}
+
+	bytes, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(bytes, &entries)
+	return entries, err
+}
+
+func (s *fileStore) write(entries map[string]Credentials) error {
+	bytes, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return configuration.ReplaceFile(s.path, bytes)
+}
@@ -0,0 +1,101 @@
+package credentials_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/cli/cf/configuration/credentials"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FileStore", func() {
+	var (
+		dir   string
+		path  string
+		store credentials.Store
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "credentials")
+		Expect(err).NotTo(HaveOccurred())
+
+		path = filepath.Join(dir, "credentials.json")
+		store = credentials.NewFileStore(path)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("has no credentials when the file does not exist", func() {
+		creds, err := store.Get("ref-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(creds.IsEmpty()).To(BeTrue())
+	})
+
+	It("keeps credentials under their reference", func() {
+		err := store.Store("ref-1", credentials.Credentials{AccessToken: "access-1", RefreshToken: "refresh-1"})
+		Expect(err).NotTo(HaveOccurred())
+		err = store.Store("ref-2", credentials.Credentials{AccessToken: "access-2"})
+		Expect(err).NotTo(HaveOccurred())
+
+		creds, err := credentials.NewFileStore(path).Get("ref-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(creds).To(Equal(credentials.Credentials{AccessToken: "access-1", RefreshToken: "refresh-1"}))
+	})
+
+	It("makes the file readable only by the user", func() {
+		err := store.Store("ref-1", credentials.Credentials{AccessToken: "access-1"})
+		Expect(err).NotTo(HaveOccurred())
+
+		info, err := os.Stat(path)
+		Expect(err).NotTo(HaveOccurred())
+		if os.PathSeparator == '/' {
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+		}
+	})
+
+	It("erases credentials", func() {
+		err := store.Store("ref-1", credentials.Credentials{AccessToken: "access-1"})
+		Expect(err).NotTo(HaveOccurred())
+
+		err = store.Erase("ref-1")
+		Expect(err).NotTo(HaveOccurred())
+
+		creds, err := store.Get("ref-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(creds.IsEmpty()).To(BeTrue())
+	})
+
+	It("keeps the credentials of processes storing at the same time", func() {
+		otherStore := credentials.NewFileStore(path)
+
+		done := make(chan bool)
+		go func() {
+			defer GinkgoRecover()
+			for i := 0; i < 20; i++ {
+				Expect(otherStore.Store(fmt.Sprintf("other-%d", i), credentials.Credentials{AccessToken: "other"})).To(Succeed())
+			}
+			close(done)
+		}()
+		for i := 0; i < 20; i++ {
+			Expect(store.Store(fmt.Sprintf("ref-%d", i), credentials.Credentials{AccessToken: "access"})).To(Succeed())
+		}
+		<-done
+
+		for i := 0; i < 20; i++ {
+			creds, err := store.Get(fmt.Sprintf("other-%d", i))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(creds.AccessToken).To(Equal("other"))
+
+			creds, err = store.Get(fmt.Sprintf("ref-%d", i))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(creds.AccessToken).To(Equal("access"))
+		}
+	})
+})
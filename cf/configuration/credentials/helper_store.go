@@ -0,0 +1,97 @@
+package credentials
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+type helperStore struct {
+	command string
+}
+
+// NewHelperStore returns a store that hands credentials to an external
+// helper, like git credential helpers do. The helper is the program at name
+// if it is a path, otherwise cf-credential-NAME on the PATH.
+//
+// The helper is run with one argument, get, store or erase, and reads lines
+// of key=value from stdin up to a blank line: the ref of the credentials, and
+// to store them their access_token, refresh_token and client_secret. To get
+// them it writes their access_token, refresh_token and client_secret lines to
+// stdout, or nothing when it has none under ref. What it writes to stderr is shown to the user.
+func NewHelperStore(name string) Store {
+	command := name
+	if !strings.ContainsAny(name, `/\`) {
+		command = "cf-credential-" + name
+	}
+	return &helperStore{command: command}
+}
+
+func (s *helperStore) Get(ref string) (Credentials, error) {
+	output, err := s.run("get", [][2]string{{"ref", ref}})
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	return Credentials{
+		AccessToken:  output["access_token"],
+		RefreshToken: output["refresh_token"],
+		ClientSecret: output["client_secret"],
+	}, nil
+}
+
+func (s *helperStore) Store(ref string, credentials Credentials) error {
+	_, err := s.run("store", [][2]string{
+		{"ref", ref},
+		{"access_token", credentials.AccessToken},
+		{"refresh_token", credentials.RefreshToken},
+		{"client_secret", credentials.ClientSecret},
+	})
+	return err
+}
+
+func (s *helperStore) Erase(ref string) error {
+	_, err := s.run("erase", [][2]string{{"ref", ref}})
+	return err
+}
+
+func (s *helperStore) run(action string, input [][2]string) (map[string]string, error) {
+	stdin := &bytes.Buffer{}
+	for _, pair := range input {
+		if strings.ContainsAny(pair[1], "\r\n") {
+			return nil, fmt.Errorf("Unable to pass %s to credential helper: it contains a newline", pair[0])
+		}
+		fmt.Fprintf(stdin, "%s=%s\n", pair[0], pair[1])
+	}
+	stdin.WriteString("\n")
+
+	stdout := &bytes.Buffer{}
+	cmd := exec.Command(s.command, action)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("Credential helper %s %s failed: %s", s.command, action, err.Error())
+	}
+
+	output := map[string]string{}
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if line == "" {
+			break
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("Credential helper %s %s wrote an invalid line: %q", s.command, action, line)
+		}
+		output[parts[0]] = parts[1]
+	}
+
+	return output, scanner.Err()
+}
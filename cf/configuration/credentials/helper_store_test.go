@@ -0,0 +1,97 @@
+// +build !windows
+
+package credentials_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/cli/cf/configuration/credentials"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// helperScript keeps each ref's key=value lines in a file in its directory.
+const helperScript = `#!/bin/sh
+dir=$(dirname "$0")
+input=$(sed '/^$/q')
+ref=$(echo "$input" | sed -n 's/^ref=//p')
+echo "$1" >> "$dir/calls"
+case "$1" in
+get) [ -f "$dir/$ref" ] && cat "$dir/$ref" ;;
+store) echo "$input" | grep -v '^ref=' > "$dir/$ref" ;;
+erase) rm -f "$dir/$ref" ;;
+esac
+exit 0
+`
+
+var _ = Describe("HelperStore", func() {
+	var (
+		dir    string
+		helper string
+		store  credentials.Store
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "credential-helper")
+		Expect(err).NotTo(HaveOccurred())
+
+		helper = filepath.Join(dir, "cf-credential-test")
+		err = ioutil.WriteFile(helper, []byte(helperScript), 0700)
+		Expect(err).NotTo(HaveOccurred())
+
+		store = credentials.NewHelperStore(helper)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("stores and gets credentials through the helper", func() {
+		err := store.Store("ref-1", credentials.Credentials{AccessToken: "access-1", RefreshToken: "refresh-1"})
+		Expect(err).NotTo(HaveOccurred())
+
+		stored, err := ioutil.ReadFile(filepath.Join(dir, "ref-1"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(stored)).To(Equal("access_token=access-1\nrefresh_token=refresh-1\nclient_secret=\n"))
+
+		creds, err := store.Get("ref-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(creds).To(Equal(credentials.Credentials{AccessToken: "access-1", RefreshToken: "refresh-1"}))
+	})
+
+	It("has no credentials when the helper writes nothing", func() {
+		creds, err := store.Get("ref-2")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(creds.IsEmpty()).To(BeTrue())
+	})
+
+	It("erases credentials through the helper", func() {
+		err := store.Store("ref-1", credentials.Credentials{AccessToken: "access-1"})
+		Expect(err).NotTo(HaveOccurred())
+
+		err = store.Erase("ref-1")
+		Expect(err).NotTo(HaveOccurred())
+
+		calls, err := ioutil.ReadFile(filepath.Join(dir, "calls"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(calls)).To(Equal("store\nerase\n"))
+		Expect(filepath.Join(dir, "ref-1")).NotTo(BeAnExistingFile())
+	})
+
+	It("fails when the helper fails", func() {
+		err := ioutil.WriteFile(helper, []byte("#!/bin/sh\nexit 1\n"), 0700)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = store.Get("ref-1")
+		Expect(err).To(MatchError(ContainSubstring("Credential helper " + helper + " get failed")))
+	})
+
+	It("refuses tokens with newlines", func() {
+		err := store.Store("ref-1", credentials.Credentials{AccessToken: "access\nref=other"})
+		Expect(err).To(HaveOccurred())
+	})
+})
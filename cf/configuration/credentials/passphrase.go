@@ -0,0 +1,51 @@
+package credentials
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/docker/docker/pkg/term"
+)
+
+// PassphraseEnvVar names the environment variable the passphrase of an
+// encrypted store is taken from, when it is set.
+const PassphraseEnvVar = "CF_CREDENTIALS_PASSPHRASE"
+
+// TerminalPassphrase returns the passphrase from the environment, or else
+// asks for it on the terminal without echoing it.
+func TerminalPassphrase() (string, error) {
+	if passphrase := os.Getenv(PassphraseEnvVar); passphrase != "" {
+		return passphrase, nil
+	}
+
+	fd := os.Stdin.Fd()
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("The credentials are encrypted. Set %s to their passphrase.", PassphraseEnvVar)
+	}
+
+	state, err := term.SaveState(fd)
+	if err != nil {
+		return "", err
+	}
+	err = term.DisableEcho(fd, state)
+	if err != nil {
+		return "", err
+	}
+	defer term.RestoreTerminal(fd, state)
+
+	fmt.Fprint(os.Stderr, "Credentials passphrase: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+
+	passphrase := strings.TrimRight(line, "\r\n")
+	if passphrase == "" {
+		return "", errors.New("The credentials passphrase cannot be empty")
+	}
+	return passphrase, nil
+}
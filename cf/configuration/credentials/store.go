@@ -0,0 +1,68 @@
+package credentials
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Credentials are the tokens of a target, and the secret of the UAA client
+// authenticated as with the client_credentials grant.
+type Credentials struct {
+	AccessToken  string
+	RefreshToken string
+	ClientSecret string `json:",omitempty"`
+}
+
+// IsEmpty tells whether there is nothing to keep.
+func (c Credentials) IsEmpty() bool {
+	return c.AccessToken == "" && c.RefreshToken == "" && c.ClientSecret == ""
+}
+
+//go:generate counterfeiter . Store
+
+// A Store keeps credentials outside of the config file, which only holds the
+// reference they are kept under.
+type Store interface {
+	Get(ref string) (Credentials, error)
+	Store(ref string, credentials Credentials) error
+	Erase(ref string) error
+}
+
+const (
+	KindFile          = "file"
+	KindEncryptedFile = "encrypted-file"
+	KindHelperPrefix  = "helper:"
+)
+
+// NewStore returns the store of the given kind: "file", the default, keeps
+// credentials in a file next to the config file, "encrypted-file" keeps them
+// encrypted with a passphrase, and "helper:NAME" hands them to an external
+// credential helper.
+func NewStore(kind string, configDir string, passphrase func() (string, error)) (Store, error) {
+	err := ValidateKind(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case kind == KindEncryptedFile:
+		return NewEncryptedFileStore(filepath.Join(configDir, "credentials.enc.json"), passphrase), nil
+	case strings.HasPrefix(kind, KindHelperPrefix):
+		return NewHelperStore(strings.TrimPrefix(kind, KindHelperPrefix)), nil
+	default:
+		return NewFileStore(filepath.Join(configDir, "credentials.json")), nil
+	}
+}
+
+// ValidateKind returns an error unless kind is one of the kinds of store.
+func ValidateKind(kind string) error {
+	switch {
+	case kind == "" || kind == KindFile || kind == KindEncryptedFile:
+		return nil
+	case strings.HasPrefix(kind, KindHelperPrefix) && len(kind) > len(KindHelperPrefix):
+		return nil
+	default:
+		return fmt.Errorf("Unknown credential store %q, expected %s, %s or %sNAME", kind, KindFile, KindEncryptedFile, KindHelperPrefix)
+	}
+}
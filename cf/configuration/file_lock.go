@@ -0,0 +1,56 @@
+package configuration
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// LockFile takes the lock on the file at path, waiting for other processes
+// to release it, so that they do not read and write it at the same time.
+// The lock is advisory, so when the lock file cannot be created, e.g. in a
+// read-only config directory, the file is used without it.
+func LockFile(path string) (unlock func()) {
+	file, err := os.OpenFile(path+".lock", os.O_RDWR|os.O_CREATE, filePermissions)
+	if err != nil {
+		return func() {}
+	}
+
+	err = lockFile(file)
+	if err != nil {
+		file.Close()
+		return func() {}
+	}
+
+	return func() {
+		unlockFile(file)
+		file.Close()
+	}
+}
+
+// ReplaceFile writes the content to a temporary file and renames it to the
+// file at path, so that the file is never seen half written. The file can
+// only be read by the user.
+func ReplaceFile(path string, content []byte) error {
+	tmpFile, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+
+	_, err = tmpFile.Write(content)
+	if err == nil {
+		err = tmpFile.Sync()
+	}
+	closeErr := tmpFile.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpFile.Name(), path)
+	}
+
+	if err != nil {
+		os.Remove(tmpFile.Name())
+	}
+	return err
+}
@@ -0,0 +1,105 @@
+package configuration
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// mergeGroups are fields that are only valid together, such as the tokens of
+// a login or a space and the org it is in. Each group is merged as a whole.
+var mergeGroups = [][]string{
+	{"AccessToken", "RefreshToken", "UAAGrantType", "UAAOAuthClient", "UAAOAuthClientSecret"},
+	{"OrganizationFields", "SpaceFields"},
+}
+
+// mergeJSON merges the JSON objects saved by this process and another one.
+// For each field, what this process has changed since it loaded base is
+// kept, and the rest is taken from saved, field by field in nested objects.
+// The fields of each of mergeGroups are all taken from the same side. When
+// saved cannot be merged, e.g. as it was written by an older version
+// with another layout, ours is returned as it is.
+func mergeJSON(base, ours, saved []byte) []byte {
+	var baseObject, ourObject, savedObject map[string]interface{}
+	if json.Unmarshal(base, &baseObject) != nil ||
+		json.Unmarshal(ours, &ourObject) != nil ||
+		json.Unmarshal(saved, &savedObject) != nil {
+		return ours
+	}
+
+	if !reflect.DeepEqual(ourObject["ConfigVersion"], savedObject["ConfigVersion"]) {
+		return ours
+	}
+
+	merged, err := json.MarshalIndent(mergeObjects(baseObject, ourObject, savedObject), "", "  ")
+	if err != nil {
+		return ours
+	}
+	return merged
+}
+
+func mergeObjects(base, ours, saved map[string]interface{}) map[string]interface{} {
+	merged := map[string]interface{}{}
+
+	keys := map[string]bool{}
+	for _, object := range []map[string]interface{}{base, ours, saved} {
+		for key := range object {
+			keys[key] = true
+		}
+	}
+
+	for _, group := range mergeGroups {
+		inGroup := false
+		oursChanged := false
+		for _, key := range group {
+			if keys[key] {
+				inGroup = true
+				oursChanged = oursChanged || changed(base, ours, key)
+			}
+		}
+		if !inGroup {
+			continue
+		}
+
+		from := saved
+		if oursChanged {
+			from = ours
+		}
+		for _, key := range group {
+			if value, ok := from[key]; ok {
+				merged[key] = value
+			}
+			delete(keys, key)
+		}
+	}
+
+	for key := range keys {
+		baseValue := base[key]
+		ourValue, inOurs := ours[key]
+		savedValue, inSaved := saved[key]
+
+		baseMap, baseIsMap := baseValue.(map[string]interface{})
+		ourMap, oursIsMap := ourValue.(map[string]interface{})
+		savedMap, savedIsMap := savedValue.(map[string]interface{})
+
+		switch {
+		case baseIsMap && oursIsMap && savedIsMap:
+			merged[key] = mergeObjects(baseMap, ourMap, savedMap)
+		case changed(base, ours, key):
+			if inOurs {
+				merged[key] = ourValue
+			}
+		case inSaved:
+			merged[key] = savedValue
+		}
+	}
+
+	return merged
+}
+
+// changed reports whether key was added, removed or given another value in
+// ours since base.
+func changed(base, ours map[string]interface{}, key string) bool {
+	baseValue, inBase := base[key]
+	ourValue, inOurs := ours[key]
+	return inOurs != inBase || !reflect.DeepEqual(ourValue, baseValue)
+}
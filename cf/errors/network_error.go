@@ -0,0 +1,15 @@
+package errors
+
+// NetworkError is returned when a request could not be sent or its response
+// could not be received, e.g. because the connection was dropped.
+type NetworkError struct {
+	message string
+}
+
+func NewNetworkError(message string) error {
+	return &NetworkError{message: message}
+}
+
+func (err *NetworkError) Error() string {
+	return err.message
+}
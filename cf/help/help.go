@@ -105,6 +105,9 @@ func newAppPresenter() (presenter appPresenter) {
 				}, {
 					presentCommand("api"),
 					presentCommand("auth"),
+				}, {
+					presentCommand("profiles"),
+					presentCommand("profile"),
 				},
 			},
 		}, {
@@ -115,6 +118,7 @@ func newAppPresenter() (presenter appPresenter) {
 					presentCommand("app"),
 				}, {
 					presentCommand("push"),
+					presentCommand("push-files"),
 					presentCommand("scale"),
 					presentCommand("delete"),
 					presentCommand("rename"),
@@ -124,6 +128,7 @@ func newAppPresenter() (presenter appPresenter) {
 					presentCommand("restart"),
 					presentCommand("restage"),
 					presentCommand("restart-app-instance"),
+					presentCommand("rolling-restart"),
 				}, {
 					presentCommand("events"),
 					presentCommand("files"),
@@ -139,6 +144,7 @@ func newAppPresenter() (presenter appPresenter) {
 					presentCommand("copy-source"),
 				}, {
 					presentCommand("create-app-manifest"),
+					presentCommand("validate-manifest"),
 				}, {
 					presentCommand("get-health-check"),
 					presentCommand("set-health-check"),
@@ -146,6 +152,7 @@ func newAppPresenter() (presenter appPresenter) {
 					presentCommand("disable-ssh"),
 					presentCommand("ssh-enabled"),
 					presentCommand("ssh"),
+					presentCommand("scp"),
 				},
 			},
 		}, {
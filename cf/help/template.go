@@ -16,9 +16,18 @@ func GetHelpTemplate() string {
 {{range .}}   {{.Name}} {{.Description}}
 {{end}}{{end}}{{end}}
 {{.Title "` + T("ENVIRONMENT VARIABLES:") + `"}}
+   CF_ACCESS_TOKEN=token              ` + T("Use this access token instead of the one in the config") + `
+   CF_API=https://api.example.com     ` + T("Use this API endpoint, of the version in CF_API_VERSION, instead of the targeted one") + `
+   CF_ASYNC_TIMEOUT=10                ` + T("Timeout for async HTTP requests, in minutes") + `
    CF_COLOR=false                     ` + T("Do not colorize output") + `
+   CF_CREDENTIALS_PASSPHRASE=secret   ` + T("Passphrase of the encrypted credential store, instead of asking for it") + `
    CF_HOME=path/to/dir/               ` + T("Override path to default config directory") + `
+   CF_ORG=name CF_ORG_GUID=guid       ` + T("Use this org instead of the targeted one") + `
    CF_PLUGIN_HOME=path/to/dir/        ` + T("Override path to default plugin config directory") + `
+   CF_PROFILE=name                    ` + T("Use the named target profile instead of the current one") + `
+   CF_READ_ONLY_CONFIG=true           ` + T("Do not write changes to the config back to disk") + `
+   CF_SKIP_SSL_VALIDATION=true        ` + T("Skip verification of the API endpoint's SSL certificate") + `
+   CF_SPACE=name CF_SPACE_GUID=guid   ` + T("Use this space instead of the targeted one") + `
    CF_STAGING_TIMEOUT=15              ` + T("Max wait time for buildpack staging, in minutes") + `
    CF_STARTUP_TIMEOUT=5               ` + T("Max wait time for app instance startup, in minutes") + `
    CF_TRACE=true                      ` + T("Print API request diagnostics to stdout") + `
@@ -28,5 +37,7 @@ func GetHelpTemplate() string {
 {{.Title "` + T("GLOBAL OPTIONS:") + `"}}
    --help, -h                         ` + T("Show help") + `
    -v                                 ` + T("Print API request diagnostics to stdout") + `
+   --profile name                     ` + T("Use the named target profile instead of the current one") + `
+   --output json|yaml|table           ` + T("Print tables as JSON or YAML records with stable field names, and other messages to stderr") + `
 `
 }
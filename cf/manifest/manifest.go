@@ -17,8 +17,13 @@ import (
 )
 
 type Manifest struct {
-	Path string
-	Data generic.Map
+	Path      string
+	Data      generic.Map
+	Variables Variables
+
+	// merged is set when Data was merged from several manifest files, so
+	// that not every property comes from the file at Path
+	merged bool
 }
 
 func NewEmptyManifest() (m *Manifest) {
@@ -26,7 +31,12 @@ func NewEmptyManifest() (m *Manifest) {
 }
 
 func (m Manifest) Applications() ([]models.AppParams, error) {
-	rawData, err := expandProperties(m.Data, generator.NewWordGenerator())
+	interpolatedData, err := interpolateVariables(m.Data, m.Variables)
+	if err != nil {
+		return []models.AppParams{}, err
+	}
+
+	rawData, err := expandProperties(interpolatedData, generator.NewWordGenerator())
 	if err != nil {
 		return []models.AppParams{}, err
 	}
@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/cloudfoundry/cli/cf/errors"
 	. "github.com/cloudfoundry/cli/cf/i18n"
@@ -17,6 +18,7 @@ import (
 
 type ManifestRepository interface {
 	ReadManifest(string) (*Manifest, error)
+	ReadManifests([]string) (*Manifest, error)
 }
 
 type ManifestDiskRepository struct{}
@@ -35,17 +37,70 @@ func (repo ManifestDiskRepository) ReadManifest(inputPath string) (*Manifest, er
 
 	m.Path = manifestPath
 
-	mapp, err := repo.readAllYAMLFiles(manifestPath)
+	mapp, inherited, err := repo.readAllYAMLFiles(manifestPath, []string{})
 	if err != nil {
 		return m, err
 	}
 
 	m.Data = mapp
+	m.merged = inherited
 
 	return m, nil
 }
 
-func (repo ManifestDiskRepository) readAllYAMLFiles(path string) (mergedMap generic.Map, err error) {
+// ReadManifests reads each of the given manifests, along with the manifests
+// they inherit from, and merges them from left to right so that every file
+// overrides the ones before it. The merge rules are those of
+// generic.DeepMerge. The path of the resulting manifest is the path of the
+// last file.
+func (repo ManifestDiskRepository) ReadManifests(inputPaths []string) (*Manifest, error) {
+	m := NewEmptyManifest()
+
+	var maps []generic.Map
+	var inherited bool
+	for _, inputPath := range inputPaths {
+		manifestPath, err := repo.manifestPath(inputPath)
+		if err != nil {
+			return m, fmt.Errorf("%s: %s", T("Error finding manifest"), err.Error())
+		}
+
+		m.Path = manifestPath
+
+		var mapp generic.Map
+		mapp, inherited, err = repo.readAllYAMLFiles(manifestPath, []string{})
+		if err != nil {
+			return m, err
+		}
+
+		maps = append(maps, mapp)
+	}
+
+	m.Data = generic.DeepMerge(maps...)
+	m.merged = inherited || len(maps) > 1
+
+	return m, nil
+}
+
+// readAllYAMLFiles reads the manifest at path and merges it on top of the
+// manifests named by its 'inherit' key, which may be a single path or a list
+// of paths relative to the inheriting manifest. chain holds the manifests
+// currently being read and is used to detect cycles. inherited reports
+// whether anything was merged in from other manifests.
+func (repo ManifestDiskRepository) readAllYAMLFiles(path string, chain []string) (mergedMap generic.Map, inherited bool, err error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return
+	}
+
+	for _, visited := range chain {
+		if visited == absPath {
+			err = errors.New(T("Manifest inheritance cycle detected: {{.Chain}}",
+				map[string]interface{}{"Chain": strings.Join(append(chain, absPath), " -> ")}))
+			return
+		}
+	}
+	chain = append(chain, absPath)
+
 	file, err := os.Open(filepath.Clean(path))
 	if err != nil {
 		return
@@ -62,25 +117,53 @@ func (repo ManifestDiskRepository) readAllYAMLFiles(path string) (mergedMap gene
 		return
 	}
 
-	inheritedPath, ok := mapp.Get("inherit").(string)
-	if !ok {
-		err = errors.New(T("invalid inherit path in manifest"))
+	inheritedPaths, err := inheritPaths(mapp.Get("inherit"))
+	if err != nil {
 		return
 	}
+	mapp.Delete("inherit")
 
-	if !filepath.IsAbs(inheritedPath) {
-		inheritedPath = filepath.Join(filepath.Dir(path), inheritedPath)
-	}
+	var maps []generic.Map
+	for _, inheritedPath := range inheritedPaths {
+		if !filepath.IsAbs(inheritedPath) {
+			inheritedPath = filepath.Join(filepath.Dir(path), inheritedPath)
+		}
 
-	inheritedMap, err := repo.readAllYAMLFiles(inheritedPath)
-	if err != nil {
-		return
+		var inheritedMap generic.Map
+		inheritedMap, _, err = repo.readAllYAMLFiles(inheritedPath, chain)
+		if err != nil {
+			return
+		}
+
+		maps = append(maps, inheritedMap)
 	}
 
-	mergedMap = generic.DeepMerge(inheritedMap, mapp)
+	mergedMap = generic.DeepMerge(append(maps, mapp)...)
+	inherited = true
 	return
 }
 
+func inheritPaths(inherit interface{}) ([]string, error) {
+	invalidErr := errors.New(T("invalid inherit path in manifest"))
+
+	switch inherit := inherit.(type) {
+	case string:
+		return []string{inherit}, nil
+	case []interface{}:
+		paths := make([]string, 0, len(inherit))
+		for _, item := range inherit {
+			path, ok := item.(string)
+			if !ok {
+				return nil, invalidErr
+			}
+			paths = append(paths, path)
+		}
+		return paths, nil
+	default:
+		return nil, invalidErr
+	}
+}
+
 func parseManifest(file io.Reader) (yamlMap generic.Map, err error) {
 	manifest, err := ioutil.ReadAll(file)
 	if err != nil {
@@ -155,6 +155,55 @@ var _ = Describe("ManifestDiskRepository", func() {
 		Expect(services).To(Equal([]string{"base-service", "foo-service"}))
 	})
 
+	It("merges manifests that inherit from a list of manifests, relative to the inheriting manifest", func() {
+		m, err := repo.ReadManifest("../../fixtures/manifests/overlays/staging.yml")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(m.Data.Has("inherit")).To(BeFalse())
+
+		applications, err := m.Applications()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(applications).To(HaveLen(1))
+		Expect(*applications[0].Name).To(Equal("base-app"))
+		Expect(*applications[0].Memory).To(Equal(int64(512)))
+		Expect(*applications[0].InstanceCount).To(Equal(2))
+		Expect((*applications[0].EnvironmentVars)["foo"]).To(Equal("common"))
+		Expect((*applications[0].EnvironmentVars)["will-be-overridden"]).To(Equal("baz"))
+	})
+
+	It("returns an error when manifests inherit from each other", func() {
+		_, err := repo.ReadManifest("../../fixtures/manifests/cycle-a.yml")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("cycle"))
+		Expect(err.Error()).To(ContainSubstring("cycle-a.yml -> "))
+		Expect(err.Error()).To(ContainSubstring("cycle-b.yml -> "))
+	})
+
+	Describe("ReadManifests", func() {
+		It("merges the manifests from left to right", func() {
+			m, err := repo.ReadManifests([]string{
+				"../../fixtures/manifests/overlays/staging.yml",
+				"../../fixtures/manifests/overlays/production.yml",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m.Path).To(Equal(filepath.Clean("../../fixtures/manifests/overlays/production.yml")))
+
+			applications, err := m.Applications()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(applications).To(HaveLen(1))
+			Expect(*applications[0].Memory).To(Equal(int64(1024)))
+			Expect(*applications[0].InstanceCount).To(Equal(10))
+			Expect(*applications[0].ServicesToBind).To(Equal([]string{"base-service"}))
+		})
+
+		It("returns an error when one of the manifests does not exist", func() {
+			_, err := repo.ReadManifests([]string{
+				"../../fixtures/manifests/overlays/staging.yml",
+				"some/path/that/doesnt/exist/manifest.yml",
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 	It("supports yml merges", func() {
 		m, err := repo.ReadManifest("../../fixtures/manifests/merge-manifest.yml")
 		Expect(err).NotTo(HaveOccurred())
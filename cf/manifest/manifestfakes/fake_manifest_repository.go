@@ -17,6 +17,15 @@ type FakeManifestRepository struct {
 		result1 *manifest.Manifest
 		result2 error
 	}
+	ReadManifestsStub        func([]string) (*manifest.Manifest, error)
+	readManifestsMutex       sync.RWMutex
+	readManifestsArgsForCall []struct {
+		arg1 []string
+	}
+	readManifestsReturns struct {
+		result1 *manifest.Manifest
+		result2 error
+	}
 }
 
 func (fake *FakeManifestRepository) ReadManifest(arg1 string) (*manifest.Manifest, error) {
@@ -52,4 +61,42 @@ func (fake *FakeManifestRepository) ReadManifestReturns(result1 *manifest.Manife
 	}{result1, result2}
 }
 
+func (fake *FakeManifestRepository) ReadManifests(arg1 []string) (*manifest.Manifest, error) {
+	var arg1Copy []string
+	if arg1 != nil {
+		arg1Copy = make([]string, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.readManifestsMutex.Lock()
+	fake.readManifestsArgsForCall = append(fake.readManifestsArgsForCall, struct {
+		arg1 []string
+	}{arg1Copy})
+	fake.readManifestsMutex.Unlock()
+	if fake.ReadManifestsStub != nil {
+		return fake.ReadManifestsStub(arg1)
+	} else {
+		return fake.readManifestsReturns.result1, fake.readManifestsReturns.result2
+	}
+}
+
+func (fake *FakeManifestRepository) ReadManifestsCallCount() int {
+	fake.readManifestsMutex.RLock()
+	defer fake.readManifestsMutex.RUnlock()
+	return len(fake.readManifestsArgsForCall)
+}
+
+func (fake *FakeManifestRepository) ReadManifestsArgsForCall(i int) []string {
+	fake.readManifestsMutex.RLock()
+	defer fake.readManifestsMutex.RUnlock()
+	return fake.readManifestsArgsForCall[i].arg1
+}
+
+func (fake *FakeManifestRepository) ReadManifestsReturns(result1 *manifest.Manifest, result2 error) {
+	fake.ReadManifestsStub = nil
+	fake.readManifestsReturns = struct {
+		result1 *manifest.Manifest
+		result2 error
+	}{result1, result2}
+}
+
 var _ manifest.ManifestRepository = new(FakeManifestRepository)
@@ -0,0 +1,289 @@
+package manifest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/cloudfoundry/cli/generic"
+)
+
+type position struct {
+	Line   int
+	Column int
+}
+
+type positionFrame struct {
+	column  int
+	path    string
+	seq     bool
+	index   int
+	lastKey string
+}
+
+// yamlPositions maps the property paths of a YAML document, in the form used
+// by validation errors (e.g. "applications[0].env.FOO"), to the line and
+// column they appear at. yaml.v2 does not expose node positions, so this is a
+// scan of the source that only places block style collections with plain
+// keys and single line scalars. Everything else (flow style collections,
+// multi-line scalars, aliases, and mappings with quoted, anchored or merge
+// keys) is mapped to the zero position, which lookupPosition reports as no
+// position at all rather than the position of an ancestor.
+func yamlPositions(source []byte) map[string]position {
+	positions := make(map[string]position)
+	frames := []*positionFrame{{column: 0}}
+
+	// lines indented deeper than skipColumn belong to the value on the line
+	// before them; if that value is a plain or quoted scalar, it is multi-line
+	skipColumn := -1
+	scalarPath := ""
+
+	for lineIndex, rawLine := range strings.Split(string(source), "\n") {
+		line := strings.TrimRight(rawLine, " \t\r")
+		content := strings.TrimLeft(line, " ")
+		column := len(line) - len(content)
+
+		if skipColumn >= 0 {
+			if content == "" || column > skipColumn {
+				if scalarPath != "" && content != "" && !strings.HasPrefix(content, "#") {
+					positions[scalarPath] = position{}
+				}
+				continue
+			}
+			skipColumn = -1
+			scalarPath = ""
+		}
+
+		if content == "" || strings.HasPrefix(content, "#") || content == "---" || content == "..." {
+			continue
+		}
+
+		for {
+			top := frames[len(frames)-1]
+			if len(frames) > 1 && (top.column > column || (top.seq && top.column == column && !isSequenceItem(content))) {
+				frames = frames[:len(frames)-1]
+				continue
+			}
+			break
+		}
+
+		itemPath := ""
+		itemColumn := 0
+		for isSequenceItem(content) {
+			top := frames[len(frames)-1]
+			if !(top.seq && top.column == column) {
+				top = &positionFrame{column: column, path: childPath(top), seq: true, index: -1}
+				frames = append(frames, top)
+			}
+			top.index++
+
+			itemPath = fmt.Sprintf("%s[%d]", top.path, top.index)
+			itemColumn = column
+			positions[itemPath] = position{Line: lineIndex + 1, Column: column + 1}
+
+			rest := strings.TrimLeft(strings.TrimPrefix(content, "-"), " ")
+			if rest == "" {
+				content = ""
+				break
+			}
+			column = column + (len(content) - len(rest))
+			content = rest
+			frames = append(frames, &positionFrame{column: column, path: itemPath})
+		}
+
+		if content == "" {
+			continue
+		}
+
+		top := frames[len(frames)-1]
+		key, value, ok := splitMappingLine(content)
+		if !ok && itemPath != "" && top.path == itemPath && top.column == column {
+			// a sequence item that is not a mapping
+			skipColumn, scalarPath = skipValue(positions, itemPath, content, itemColumn)
+			continue
+		}
+
+		if top.seq || top.column < column {
+			top = &positionFrame{column: column, path: childPath(top)}
+			frames = append(frames, top)
+		}
+
+		if !ok || key == "<<" {
+			// the keys of this mapping cannot all be placed, so none of the
+			// properties missing from positions can fall back to it
+			if top.path != "" {
+				positions[top.path] = position{}
+			}
+			skipColumn = column
+			continue
+		}
+
+		top.lastKey = key
+		keyPath := joinPath(top.path, key)
+		positions[keyPath] = position{Line: lineIndex + 1, Column: column + 1}
+		skipColumn, scalarPath = skipValue(positions, keyPath, value, column)
+	}
+
+	return positions
+}
+
+// skipValue returns the column that the lines following value, the value at
+// path on a line indented to column, must be indented deeper than to belong
+// to it, or -1 if a block collection may follow. If value is a plain or
+// quoted scalar, path is returned as well, as it is multi-line if any line
+// is skipped. Flow style collections and aliases are given the zero position.
+func skipValue(positions map[string]position, path, value string, column int) (int, string) {
+	for strings.HasPrefix(value, "&") || strings.HasPrefix(value, "!") {
+		end := strings.Index(value, " ")
+		if end < 0 {
+			return -1, ""
+		}
+		value = strings.TrimLeft(value[end:], " ")
+	}
+
+	switch {
+	case value == "" || strings.HasPrefix(value, "#"):
+		return -1, ""
+	case strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">"):
+		return column, ""
+	case strings.HasPrefix(value, "{") || strings.HasPrefix(value, "[") || strings.HasPrefix(value, "*"):
+		positions[path] = position{}
+		return column, ""
+	default:
+		return column, path
+	}
+}
+
+func childPath(frame *positionFrame) string {
+	if frame.seq {
+		return fmt.Sprintf("%s[%d]", frame.path, frame.index)
+	}
+	if frame.lastKey == "" {
+		return frame.path
+	}
+	return joinPath(frame.path, frame.lastKey)
+}
+
+func isSequenceItem(content string) bool {
+	return content == "-" || strings.HasPrefix(content, "- ")
+}
+
+// splitMappingLine splits a block mapping line with a plain key into its key
+// and value.
+func splitMappingLine(content string) (string, string, bool) {
+	index := strings.Index(content, ": ")
+	if index < 0 {
+		if !strings.HasSuffix(content, ":") {
+			return "", "", false
+		}
+		index = len(content) - 1
+	}
+
+	key := strings.TrimSpace(content[:index])
+	if key == "" || strings.ContainsAny(key[:1], "[{&*!|>'\"%@`?") {
+		return "", "", false
+	}
+
+	return key, strings.TrimSpace(content[index+1:]), true
+}
+
+// lookupPosition returns the position of path, or of its closest ancestor
+// that has one. Nothing is found if that is the zero position.
+func lookupPosition(positions map[string]position, path string) (position, bool) {
+	for path != "" {
+		if pos, ok := positions[path]; ok {
+			return pos, pos.Line > 0
+		}
+
+		cut := strings.LastIndexAny(path, ".[")
+		if cut < 0 {
+			break
+		}
+		path = path[:cut]
+	}
+
+	return position{}, false
+}
+
+// ownPositions keeps the positions of the properties of merged, a manifest
+// merged from several files, that come unchanged from own, the manifest the
+// positions were read from. They are keyed by their path in merged: list
+// items are matched the way generic.DeepMerge merges them, by name for lists
+// of named maps and from the end of the list otherwise.
+func ownPositions(positions map[string]position, merged generic.Map, own generic.Map) map[string]position {
+	result := make(map[string]position)
+	collectOwnPositions(positions, result, "", "", merged, own)
+	return result
+}
+
+func collectOwnPositions(positions, result map[string]position, path, ownPath string, merged, own interface{}) bool {
+	owned := true
+
+	switch {
+	case merged == nil || own == nil:
+		owned = merged == nil && own == nil
+
+	case generic.IsMappable(merged) && generic.IsMappable(own):
+		mergedMap, ownMap := generic.NewMap(merged), generic.NewMap(own)
+		owned = mergedMap.Count() == ownMap.Count()
+		for _, key := range mergedMap.Keys() {
+			if !ownMap.Has(key) {
+				owned = false
+				continue
+			}
+			if !collectOwnPositions(positions, result, joinPath(path, key), joinPath(ownPath, key), mergedMap.Get(key), ownMap.Get(key)) {
+				owned = false
+			}
+		}
+
+	default:
+		mergedItems, isList := merged.([]interface{})
+		ownItems, ownIsList := own.([]interface{})
+		if !isList || !ownIsList {
+			owned = reflect.DeepEqual(merged, own)
+			break
+		}
+
+		owned = len(mergedItems) == len(ownItems)
+		for index, item := range mergedItems {
+			ownIndex := matchingItem(ownItems, item, index-(len(mergedItems)-len(ownItems)))
+			if ownIndex != index {
+				owned = false
+			}
+			if ownIndex < 0 {
+				continue
+			}
+			if !collectOwnPositions(positions, result, fmt.Sprintf("%s[%d]", path, index), fmt.Sprintf("%s[%d]", ownPath, ownIndex), item, ownItems[ownIndex]) {
+				owned = false
+			}
+		}
+	}
+
+	if owned && path != "" {
+		if pos, ok := positions[ownPath]; ok {
+			result[path] = pos
+		}
+	}
+	return owned
+}
+
+// matchingItem returns the index of the item of items that item was merged
+// from, or -1 if there is none. Maps with a name are matched by name, and
+// anything else by its index in items.
+func matchingItem(items []interface{}, item interface{}, index int) int {
+	if item != nil && generic.IsMappable(item) {
+		if name, ok := generic.NewMap(item).Get("name").(string); ok {
+			for i, candidate := range items {
+				if candidate != nil && generic.IsMappable(candidate) && generic.NewMap(candidate).Get("name") == name {
+					return i
+				}
+			}
+			return -1
+		}
+	}
+
+	if index < 0 || index >= len(items) {
+		return -1
+	}
+	return index
+}
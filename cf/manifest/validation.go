@@ -0,0 +1,341 @@
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	. "github.com/cloudfoundry/cli/cf/i18n"
+
+	"github.com/cloudfoundry/cli/cf/formatters"
+	"github.com/cloudfoundry/cli/generic"
+)
+
+type ValidationError struct {
+	File     string
+	Line     int
+	Column   int
+	Property string
+	Message  string
+}
+
+func (err ValidationError) Error() string {
+	switch {
+	case err.File != "" && err.Line > 0:
+		return fmt.Sprintf("%s:%d:%d: %s", err.File, err.Line, err.Column, err.Message)
+	case err.File != "":
+		return fmt.Sprintf("%s: %s", err.File, err.Message)
+	default:
+		return err.Message
+	}
+}
+
+type ValidationErrors []ValidationError
+
+func (errs ValidationErrors) Error() string {
+	lines := make([]string, len(errs))
+	for i, err := range errs {
+		lines[i] = err.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+type propertyValidator func(v *validator, path string, value interface{})
+
+var appPropertyValidators = map[string]propertyValidator{
+	"app-ports":         validateIntList,
+	"buildpack":         validateNullableString,
+	"command":           validateNullableString,
+	"disk_quota":        validateByteSize,
+	"domain":            validateDomain,
+	"domains":           validateDomains,
+	"env":               validateEnv,
+	"health-check-type": validateHealthCheckType,
+	"host":              validateHost,
+	"hosts":             validateHosts,
+	"instances":         validateInt,
+	"memory":            validateByteSize,
+	"name":              validateString,
+	"no-hostname":       validateBool,
+	"no-route":          validateBool,
+	"path":              validateString,
+	"random-route":      validateBool,
+	"services":          validateStringList,
+	"stack":             validateString,
+	"timeout":           validateInt,
+}
+
+var (
+	hostRegex   = regexp.MustCompile(`^(\*|[a-zA-Z0-9]([-a-zA-Z0-9]*[a-zA-Z0-9])?)$`)
+	domainRegex = regexp.MustCompile(`^[a-zA-Z0-9]([-a-zA-Z0-9]*[a-zA-Z0-9])?(\.[a-zA-Z0-9]([-a-zA-Z0-9]*[a-zA-Z0-9])?)*$`)
+)
+
+type validator struct {
+	file      string
+	merged    bool
+	positions map[string]position
+	errs      ValidationErrors
+}
+
+// Validate checks the manifest's property names and values before anything
+// is pushed, so that e.g. a misspelled key is reported rather than silently
+// ignored. Variables are interpolated first. Every problem found is returned
+// in a single ValidationErrors, with the line and column of the offending
+// property when it can be found in the manifest file. When the manifest was
+// merged from several files, only the properties that come unchanged from the
+// file at m.Path get a position.
+func (m Manifest) Validate() error {
+	data, err := interpolateVariables(m.Data, m.Variables)
+	if err != nil {
+		return err
+	}
+
+	v := &validator{file: m.Path, merged: m.merged, positions: map[string]position{}}
+	if m.Path != "" {
+		if source, err := ioutil.ReadFile(filepath.Clean(m.Path)); err == nil {
+			v.positions = yamlPositions(source)
+
+			if m.merged {
+				own, err := parseManifest(bytes.NewReader(source))
+				if err != nil {
+					own = generic.NewMap()
+				}
+				v.positions = ownPositions(v.positions, m.Data, own)
+			}
+		}
+	}
+
+	v.validateManifest(generic.NewMap(data))
+
+	if len(v.errs) > 0 {
+		return v.errs
+	}
+	return nil
+}
+
+func (v *validator) addError(path string, message string) {
+	pos, found := lookupPosition(v.positions, path)
+
+	// a property of a merged manifest without a position may come from a
+	// file other than v.file
+	file := v.file
+	if v.merged && !found {
+		file = ""
+	}
+
+	v.errs = append(v.errs, ValidationError{
+		File:     file,
+		Line:     pos.Line,
+		Column:   pos.Column,
+		Property: path,
+		Message:  message,
+	})
+}
+
+func (v *validator) validateManifest(data generic.Map) {
+	for _, key := range sortedKeys(data) {
+		path := joinPath("", key)
+		value := data.Get(key)
+
+		switch key {
+		case "applications":
+			v.validateApplications(path, value)
+		case "inherit":
+		default:
+			// top level maps that are not properties are allowed, as they
+			// are commonly used to hold YAML anchors
+			if _, known := appPropertyValidators[coerceToString(key)]; !known && value != nil && generic.IsMappable(value) {
+				continue
+			}
+			v.validateProperty(path, key, value)
+		}
+	}
+}
+
+func (v *validator) validateApplications(path string, value interface{}) {
+	apps, ok := value.([]interface{})
+	if !ok {
+		v.addError(path, T("'{{.Property}}' must be a list of applications", map[string]interface{}{"Property": path}))
+		return
+	}
+
+	for index, app := range apps {
+		appPath := fmt.Sprintf("%s[%d]", path, index)
+		if app == nil || !generic.IsMappable(app) {
+			v.addError(appPath, T("'{{.Property}}' must be a set of key/value pairs", map[string]interface{}{"Property": appPath}))
+			continue
+		}
+
+		appMap := generic.NewMap(app)
+		for _, key := range sortedKeys(appMap) {
+			v.validateProperty(joinPath(appPath, key), key, appMap.Get(key))
+		}
+	}
+}
+
+func (v *validator) validateProperty(path string, key interface{}, value interface{}) {
+	validate, known := appPropertyValidators[coerceToString(key)]
+	if !known {
+		v.addError(path, T("Unknown property '{{.Property}}'", map[string]interface{}{"Property": path}))
+		return
+	}
+
+	if value == nil {
+		if key != "command" && key != "buildpack" {
+			v.addError(path, T("'{{.Property}}' should not be null", map[string]interface{}{"Property": path}))
+		}
+		return
+	}
+
+	validate(v, path, value)
+}
+
+func validateString(v *validator, path string, value interface{}) {
+	if _, ok := value.(string); !ok {
+		v.addError(path, T("'{{.Property}}' must be a string", map[string]interface{}{"Property": path}))
+	}
+}
+
+func validateNullableString(v *validator, path string, value interface{}) {
+	if _, ok := value.(string); !ok {
+		v.addError(path, T("'{{.Property}}' must be a string or null", map[string]interface{}{"Property": path}))
+	}
+}
+
+func validateInt(v *validator, path string, value interface{}) {
+	switch value := value.(type) {
+	case int, int64:
+		return
+	case string:
+		if _, err := strconv.Atoi(value); err == nil {
+			return
+		}
+	}
+	v.addError(path, T("'{{.Property}}' must be a number, but it was '{{.Value}}'",
+		map[string]interface{}{"Property": path, "Value": coerceToString(value)}))
+}
+
+func validateBool(v *validator, path string, value interface{}) {
+	switch value := value.(type) {
+	case bool:
+		return
+	case string:
+		if value == "true" || value == "false" {
+			return
+		}
+	}
+	v.addError(path, T("'{{.Property}}' must be a boolean, but it was '{{.Value}}'",
+		map[string]interface{}{"Property": path, "Value": coerceToString(value)}))
+}
+
+func validateByteSize(v *validator, path string, value interface{}) {
+	stringVal := coerceToString(value)
+	if _, err := formatters.ToMegabytes(stringVal); err != nil {
+		v.addError(path, T("'{{.Property}}' has an invalid size '{{.Value}}': {{.Err}}",
+			map[string]interface{}{"Property": path, "Value": stringVal, "Err": err.Error()}))
+	}
+}
+
+func validateStringList(v *validator, path string, value interface{}) {
+	list, ok := value.([]interface{})
+	if !ok {
+		v.addError(path, T("'{{.Property}}' must be a list of strings", map[string]interface{}{"Property": path}))
+		return
+	}
+
+	for index, item := range list {
+		if _, ok := item.(string); !ok {
+			itemPath := fmt.Sprintf("%s[%d]", path, index)
+			v.addError(itemPath, T("'{{.Property}}' must be a string", map[string]interface{}{"Property": itemPath}))
+		}
+	}
+}
+
+func validateIntList(v *validator, path string, value interface{}) {
+	list, ok := value.([]interface{})
+	if !ok {
+		v.addError(path, T("'{{.Property}}' must be a list of numbers", map[string]interface{}{"Property": path}))
+		return
+	}
+
+	for index, item := range list {
+		if _, ok := item.(int); !ok {
+			itemPath := fmt.Sprintf("%s[%d]", path, index)
+			v.addError(itemPath, T("'{{.Property}}' must be a number, but it was '{{.Value}}'",
+				map[string]interface{}{"Property": itemPath, "Value": coerceToString(item)}))
+		}
+	}
+}
+
+func validateEnv(v *validator, path string, value interface{}) {
+	if !generic.IsMappable(value) {
+		v.addError(path, T("'{{.Property}}' must be a set of key/value pairs", map[string]interface{}{"Property": path}))
+		return
+	}
+
+	env := generic.NewMap(value)
+	for _, key := range sortedKeys(env) {
+		if env.Get(key) == nil {
+			keyPath := joinPath(path, key)
+			v.addError(keyPath, T("'{{.Property}}' should not be null", map[string]interface{}{"Property": keyPath}))
+		}
+	}
+}
+
+func validateHealthCheckType(v *validator, path string, value interface{}) {
+	healthCheckType, ok := value.(string)
+	if !ok || (healthCheckType != "port" && healthCheckType != "none") {
+		v.addError(path, T("'{{.Property}}' has an invalid health check type '{{.Value}}'. Expected 'port' or 'none'",
+			map[string]interface{}{"Property": path, "Value": coerceToString(value)}))
+	}
+}
+
+func validateHost(v *validator, path string, value interface{}) {
+	host, ok := value.(string)
+	if !ok {
+		v.addError(path, T("'{{.Property}}' must be a string", map[string]interface{}{"Property": path}))
+		return
+	}
+
+	if !hostRegex.MatchString(strings.Replace(host, "${random-word}", "random", -1)) {
+		v.addError(path, T("'{{.Property}}' is not a valid host name: '{{.Value}}'",
+			map[string]interface{}{"Property": path, "Value": host}))
+	}
+}
+
+func validateHosts(v *validator, path string, value interface{}) {
+	validateEach(v, path, value, validateHost)
+}
+
+func validateDomain(v *validator, path string, value interface{}) {
+	domain, ok := value.(string)
+	if !ok {
+		v.addError(path, T("'{{.Property}}' must be a string", map[string]interface{}{"Property": path}))
+		return
+	}
+
+	if !domainRegex.MatchString(domain) {
+		v.addError(path, T("'{{.Property}}' is not a valid domain: '{{.Value}}'",
+			map[string]interface{}{"Property": path, "Value": domain}))
+	}
+}
+
+func validateDomains(v *validator, path string, value interface{}) {
+	validateEach(v, path, value, validateDomain)
+}
+
+func validateEach(v *validator, path string, value interface{}, validate propertyValidator) {
+	list, ok := value.([]interface{})
+	if !ok {
+		v.addError(path, T("'{{.Property}}' must be a list of strings", map[string]interface{}{"Property": path}))
+		return
+	}
+
+	for index, item := range list {
+		validate(v, fmt.Sprintf("%s[%d]", path, index), item)
+	}
+}
@@ -0,0 +1,190 @@
+package manifest_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/cli/cf/manifest"
+	"github.com/cloudfoundry/cli/generic"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Validate", func() {
+	It("accepts a valid manifest", func() {
+		m := NewManifest("/some/path/manifest.yml", generic.NewMap(map[interface{}]interface{}{
+			"memory":    "512M",
+			"instances": "3",
+			"app_types": map[interface{}]interface{}{
+				"small": map[interface{}]interface{}{"memory": "128M"},
+			},
+			"applications": []interface{}{
+				map[interface{}]interface{}{
+					"name":              "app",
+					"disk_quota":        "1G",
+					"timeout":           60,
+					"no-route":          true,
+					"host":              "app-${random-word}",
+					"domains":           []interface{}{"example.com", "apps.example.com"},
+					"services":          []interface{}{"db"},
+					"health-check-type": "port",
+					"command":           nil,
+					"env": map[interface{}]interface{}{
+						"FOO": "bar",
+					},
+				},
+			},
+		}))
+
+		Expect(m.Validate()).To(Succeed())
+	})
+
+	It("validates the manifest after substituting variables", func() {
+		m := NewManifest("/some/path/manifest.yml", generic.NewMap(map[interface{}]interface{}{
+			"applications": []interface{}{
+				map[interface{}]interface{}{"name": "app", "instances": "((instances))"},
+			},
+		}))
+
+		m.Variables = manifest.Variables{"instances": "many"}
+		Expect(m.Validate()).To(MatchError(ContainSubstring("'applications[0].instances' must be a number, but it was 'many'")))
+
+		m.Variables = manifest.Variables{}
+		Expect(m.Validate()).To(MatchError(ContainSubstring("((instances)) at applications[0].instances")))
+	})
+
+	It("reports every problem with the line and column it was found at", func() {
+		repo := manifest.NewManifestDiskRepository()
+		m, err := repo.ReadManifest("../../fixtures/manifests/invalid-manifest.yml")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = m.Validate()
+		Expect(err).To(HaveOccurred())
+
+		errs, ok := err.(manifest.ValidationErrors)
+		Expect(ok).To(BeTrue())
+
+		messages := []string{}
+		for _, e := range errs {
+			messages = append(messages, e.Error())
+		}
+
+		Expect(messages).To(ConsistOf(
+			"../../fixtures/manifests/invalid-manifest.yml:3:1: Unknown property 'instance'",
+			"../../fixtures/manifests/invalid-manifest.yml:13:5: 'applications[1].env.EMPTY' should not be null",
+			"../../fixtures/manifests/invalid-manifest.yml:10:3: 'applications[1].health-check-type' has an invalid health check type 'http'. Expected 'port' or 'none'",
+			"../../fixtures/manifests/invalid-manifest.yml:11:3: 'applications[1].host' is not a valid host name: 'not_a_host'",
+			ContainSubstring("invalid-manifest.yml:9:3: 'applications[1].memory' has an invalid size 'lots'"),
+		))
+	})
+
+	It("only reports the line and column of properties from the manifest itself when it inherits from others", func() {
+		repo := manifest.NewManifestDiskRepository()
+		m, err := repo.ReadManifest("../../fixtures/manifests/invalid-inherited-manifest.yml")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = m.Validate()
+		Expect(err).To(HaveOccurred())
+
+		errs, ok := err.(manifest.ValidationErrors)
+		Expect(ok).To(BeTrue())
+
+		messages := []string{}
+		for _, e := range errs {
+			messages = append(messages, e.Error())
+		}
+
+		Expect(messages).To(ConsistOf(
+			"Unknown property 'instance'",
+			ContainSubstring("'applications[0].memory' has an invalid size 'lots'"),
+			"'applications[1].host' is not a valid host name: 'not_a_host'",
+			"../../fixtures/manifests/invalid-inherited-manifest.yml:5:3: 'applications[1].health-check-type' has an invalid health check type 'http'. Expected 'port' or 'none'",
+			HavePrefix("../../fixtures/manifests/invalid-inherited-manifest.yml:7:3: 'applications[2].timeout'"),
+		))
+	})
+
+	Context("when properties are not in block style with plain keys", func() {
+		var path string
+
+		BeforeEach(func() {
+			dir, err := ioutil.TempDir("", "validate-manifest")
+			Expect(err).NotTo(HaveOccurred())
+			path = filepath.Join(dir, "manifest.yml")
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(filepath.Dir(path))
+		})
+
+		validate := func(source string) []string {
+			Expect(ioutil.WriteFile(path, []byte(source), 0644)).To(Succeed())
+
+			m, err := manifest.NewManifestDiskRepository().ReadManifest(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			errs, ok := m.Validate().(manifest.ValidationErrors)
+			Expect(ok).To(BeTrue())
+
+			messages := []string{}
+			for _, e := range errs {
+				messages = append(messages, e.Error())
+			}
+			return messages
+		}
+
+		It("reports no position for properties in flow style collections", func() {
+			Expect(validate("applications:\n- name: app\n  env: {EMPTY: ~}\n- {name: other, host: not_a_host}\n")).To(ConsistOf(
+				path+": 'applications[0].env.EMPTY' should not be null",
+				path+": 'applications[1].host' is not a valid host name: 'not_a_host'",
+			))
+		})
+
+		It("reports no position for multi-line scalars", func() {
+			Expect(validate("applications:\n- name: app\n  host: not\n    a host\n  health-check-type: \"http\n    check\"\n")).To(ConsistOf(
+				path+": 'applications[0].host' is not a valid host name: 'not a host'",
+				path+": 'applications[0].health-check-type' has an invalid health check type 'http check'. Expected 'port' or 'none'",
+			))
+		})
+
+		It("reports no position for aliases and the properties merged from them", func() {
+			Expect(validate("applications:\n- name: app\n  host: &host not_a_host\n- name: other\n  host: *host\n- &defaults\n  name: defaults\n  health-check-type: http\n- <<: *defaults\n  name: merged\n")).To(ConsistOf(
+				path+":3:3: 'applications[0].host' is not a valid host name: 'not_a_host'",
+				path+": 'applications[1].host' is not a valid host name: 'not_a_host'",
+				path+":8:3: 'applications[2].health-check-type' has an invalid health check type 'http'. Expected 'port' or 'none'",
+				path+": 'applications[3].health-check-type' has an invalid health check type 'http'. Expected 'port' or 'none'",
+			))
+		})
+
+		It("reports no position for the properties of a mapping with quoted keys", func() {
+			Expect(validate("applications:\n- name: app\n  \"host\": not_a_host\n  'instance': 1\n")).To(ConsistOf(
+				path+": 'applications[0].host' is not a valid host name: 'not_a_host'",
+				path+": Unknown property 'applications[0].instance'",
+			))
+		})
+	})
+
+	It("reports values of the wrong type", func() {
+		m := NewManifest("", generic.NewMap(map[interface{}]interface{}{
+			"applications": []interface{}{
+				map[interface{}]interface{}{
+					"name":      []interface{}{"a"},
+					"no-route":  "maybe",
+					"services":  "db",
+					"app-ports": []interface{}{"8080"},
+					"domain":    "not a domain",
+				},
+				"not-an-app",
+			},
+		}))
+
+		err := m.Validate()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("'applications[0].name' must be a string"))
+		Expect(err.Error()).To(ContainSubstring("'applications[0].no-route' must be a boolean, but it was 'maybe'"))
+		Expect(err.Error()).To(ContainSubstring("'applications[0].services' must be a list of strings"))
+		Expect(err.Error()).To(ContainSubstring("'applications[0].app-ports[0]' must be a number"))
+		Expect(err.Error()).To(ContainSubstring("'applications[0].domain' is not a valid domain: 'not a domain'"))
+		Expect(err.Error()).To(ContainSubstring("'applications[1]' must be a set of key/value pairs"))
+	})
+})
@@ -0,0 +1,171 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/generic"
+	"gopkg.in/yaml.v2"
+)
+
+var variableRegex = regexp.MustCompile(`\(\(([-\w\.]+)\)\)`)
+
+// Variables holds the values substituted for ((name)) placeholders in a
+// manifest.
+type Variables map[string]interface{}
+
+func NewVariables() Variables {
+	return Variables{}
+}
+
+// ReadVariablesFile loads a YAML file of top-level key/value pairs.
+func ReadVariablesFile(path string) (Variables, error) {
+	contents, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make(map[interface{}]interface{})
+	err = yaml.Unmarshal(contents, &raw)
+	if err != nil {
+		return nil, errors.New(T("Invalid vars file '{{.Path}}': {{.Err}}",
+			map[string]interface{}{"Path": path, "Err": err.Error()}))
+	}
+
+	vars := NewVariables()
+	for key, value := range raw {
+		name, ok := key.(string)
+		if !ok {
+			return nil, errors.New(T("Invalid vars file '{{.Path}}': variable names must be strings",
+				map[string]interface{}{"Path": path}))
+		}
+		vars[name] = value
+	}
+
+	return vars, nil
+}
+
+// ParseVariable parses a KEY=VALUE pair as given to push --var.
+func ParseVariable(pair string) (string, string, error) {
+	parts := strings.SplitN(pair, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", "", errors.New(T("Invalid variable '{{.Var}}'. Expected KEY=VALUE",
+			map[string]interface{}{"Var": pair}))
+	}
+	return parts[0], parts[1], nil
+}
+
+// Merge returns a copy of vars overridden by the values in other.
+func (vars Variables) Merge(other Variables) Variables {
+	merged := NewVariables()
+	for key, value := range vars {
+		merged[key] = value
+	}
+	for key, value := range other {
+		merged[key] = value
+	}
+	return merged
+}
+
+type missingVariable struct {
+	name string
+	path string
+}
+
+func interpolateVariables(input interface{}, vars Variables) (interface{}, error) {
+	var missing []missingVariable
+	output := interpolate(input, vars, "", &missing)
+
+	if len(missing) > 0 {
+		message := T("Could not find values for the following manifest variables:")
+		for _, m := range missing {
+			message = message + "\n" + T("  (({{.Name}})) at {{.Path}}",
+				map[string]interface{}{"Name": m.name, "Path": m.path})
+		}
+		return nil, errors.New(message)
+	}
+
+	return output, nil
+}
+
+func interpolate(input interface{}, vars Variables, path string, missing *[]missingVariable) interface{} {
+	switch input := input.(type) {
+	case string:
+		return interpolateString(input, vars, path, missing)
+	case []interface{}:
+		output := make([]interface{}, len(input))
+		for index, item := range input {
+			output[index] = interpolate(item, vars, fmt.Sprintf("%s[%d]", path, index), missing)
+		}
+		return output
+	case map[interface{}]interface{}:
+		return interpolate(generic.NewMap(input), vars, path, missing)
+	case map[string]interface{}:
+		return interpolate(generic.NewMap(input), vars, path, missing)
+	case generic.Map:
+		output := generic.NewMap()
+		for _, key := range sortedKeys(input) {
+			output.Set(key, interpolate(input.Get(key), vars, joinPath(path, key), missing))
+		}
+		return output
+	default:
+		return input
+	}
+}
+
+func interpolateString(input string, vars Variables, path string, missing *[]missingVariable) interface{} {
+	matches := variableRegex.FindAllStringSubmatch(input, -1)
+	if matches == nil {
+		return input
+	}
+
+	for _, match := range matches {
+		if _, ok := vars[match[1]]; !ok {
+			*missing = append(*missing, missingVariable{name: match[1], path: path})
+		}
+	}
+
+	// a value consisting of a single placeholder keeps the type of the
+	// variable, so that e.g. `instances: ((instances))` stays a number
+	if len(matches) == 1 && matches[0][0] == input {
+		if value, ok := vars[matches[0][1]]; ok {
+			return value
+		}
+		return input
+	}
+
+	return variableRegex.ReplaceAllStringFunc(input, func(placeholder string) string {
+		name := variableRegex.FindStringSubmatch(placeholder)[1]
+		if value, ok := vars[name]; ok {
+			return coerceToString(value)
+		}
+		return placeholder
+	})
+}
+
+func joinPath(path string, key interface{}) string {
+	if path == "" {
+		return coerceToString(key)
+	}
+	return path + "." + coerceToString(key)
+}
+
+type byKeyString []interface{}
+
+func (keys byKeyString) Len() int      { return len(keys) }
+func (keys byKeyString) Swap(i, j int) { keys[i], keys[j] = keys[j], keys[i] }
+func (keys byKeyString) Less(i, j int) bool {
+	return coerceToString(keys[i]) < coerceToString(keys[j])
+}
+
+func sortedKeys(input generic.Map) []interface{} {
+	keys := input.Keys()
+	sort.Sort(byKeyString(keys))
+	return keys
+}
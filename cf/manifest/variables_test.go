@@ -0,0 +1,91 @@
+package manifest_test
+
+import (
+	"github.com/cloudfoundry/cli/cf/manifest"
+	"github.com/cloudfoundry/cli/generic"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Variables", func() {
+	Describe("Applications with ((var)) placeholders", func() {
+		var m *manifest.Manifest
+
+		BeforeEach(func() {
+			m = NewManifest("/some/path/manifest.yml", generic.NewMap(map[interface{}]interface{}{
+				"memory": "((memory))",
+				"applications": []interface{}{
+					map[interface{}]interface{}{
+						"name":      "app-((env))",
+						"instances": "((instances))",
+						"env": map[interface{}]interface{}{
+							"GREETING": "hello ((env)) world",
+						},
+					},
+				},
+			}))
+		})
+
+		It("substitutes the values of the provided variables", func() {
+			m.Variables = manifest.Variables{"memory": "256M", "env": "staging", "instances": 4}
+
+			apps, err := m.Applications()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(*apps[0].Name).To(Equal("app-staging"))
+			Expect(*apps[0].Memory).To(Equal(int64(256)))
+			Expect(*apps[0].InstanceCount).To(Equal(4))
+			Expect((*apps[0].EnvironmentVars)["GREETING"]).To(Equal("hello staging world"))
+		})
+
+		It("returns a single error naming every missing variable and where it is used", func() {
+			m.Variables = manifest.Variables{"env": "staging"}
+
+			_, err := m.Applications()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("((instances)) at applications[0].instances"))
+			Expect(err.Error()).To(ContainSubstring("((memory)) at memory"))
+		})
+
+		It("returns an error when no variables are provided", func() {
+			_, err := m.Applications()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("((env)) at applications[0].env.GREETING"))
+			Expect(err.Error()).To(ContainSubstring("((env)) at applications[0].name"))
+		})
+	})
+
+	Describe("ReadVariablesFile", func() {
+		It("reads the variables from a YAML file", func() {
+			vars, err := manifest.ReadVariablesFile("../../fixtures/manifests/vars.yml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vars).To(Equal(manifest.Variables{"instances": 3, "host": "my-host"}))
+		})
+
+		It("returns an error when the file does not exist", func() {
+			_, err := manifest.ReadVariablesFile("../../fixtures/manifests/does-not-exist.yml")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("ParseVariable", func() {
+		It("splits on the first '='", func() {
+			key, value, err := manifest.ParseVariable("cmd=a=b")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(key).To(Equal("cmd"))
+			Expect(value).To(Equal("a=b"))
+		})
+
+		It("returns an error when there is no '='", func() {
+			_, _, err := manifest.ParseVariable("just-a-key")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Merge", func() {
+		It("prefers the values of the other variables", func() {
+			vars := manifest.Variables{"a": "1", "b": "2"}.Merge(manifest.Variables{"b": "3"})
+			Expect(vars).To(Equal(manifest.Variables{"a": "1", "b": "3"}))
+		})
+	})
+})
@@ -53,6 +53,11 @@ type tokenRefresher interface {
 type Request struct {
 	HTTPReq      *http.Request
 	SeekableBody io.ReadSeeker
+
+	// SendOnce is set when the caller retries the request itself, with a
+	// backoff of its own, so that it is not also retried when the server
+	// cannot be contacted.
+	SendOnce bool
 }
 
 type Gateway struct {
@@ -234,6 +239,20 @@ func (gateway Gateway) NewRequestForFile(method, fullURL, accessToken string, bo
 	return gateway.newRequest(request, accessToken, progressReader), nil
 }
 
+// NewRequestForStream builds a request whose body is read from body while it
+// is being sent, with its progress reported like that of a file. As the size
+// of the body is not known up front, it is sent in chunks.
+func (gateway Gateway) NewRequestForStream(method, fullURL, accessToken string, body io.ReadSeeker) (*Request, error) {
+	progressReader := NewProgressReader(body, gateway.ui, 5*time.Second)
+
+	request, err := http.NewRequest(method, fullURL, progressReader)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", T("Error building request"), err.Error())
+	}
+
+	return gateway.newRequest(request, accessToken, progressReader), nil
+}
+
 func (gateway Gateway) NewRequest(method, path, accessToken string, body io.ReadSeeker) (*Request, error) {
 	request, err := http.NewRequest(method, path, body)
 	if err != nil {
@@ -291,7 +310,7 @@ func (gateway Gateway) PerformRequestForJSONResponse(request *Request, response
 
 func (gateway Gateway) PerformPollingRequestForJSONResponse(endpoint string, request *Request, response interface{}, timeout time.Duration) (headers http.Header, apiErr error) {
 	query := request.HTTPReq.URL.Query()
-	query.Add("async", "true")
+	query.Set("async", "true")
 	request.HTTPReq.URL.RawQuery = query.Encode()
 
 	bytes, headers, rawResponse, apiErr := gateway.performRequestForResponseBytes(request)
@@ -401,7 +420,7 @@ func (gateway Gateway) doRequestHandlingAuth(request *Request) (rawResponse *htt
 }
 
 func (gateway Gateway) doRequestAndHandlerError(request *Request) (rawResponse *http.Response, err error) {
-	rawResponse, err = gateway.doRequest(request.HTTPReq)
+	rawResponse, err = gateway.doRequest(request)
 	if err != nil {
 		err = WrapNetworkErrors(request.HTTPReq.URL.Host, err)
 		return
@@ -417,17 +436,28 @@ func (gateway Gateway) doRequestAndHandlerError(request *Request) (rawResponse *
 	return
 }
 
-func (gateway Gateway) doRequest(request *http.Request) (response *http.Response, err error) {
+func (gateway Gateway) doRequest(request *Request) (response *http.Response, err error) {
 	if gateway.transport == nil {
 		makeHTTPTransport(&gateway)
 	}
 
 	httpClient := NewHTTPClient(gateway.transport, NewRequestDumper(gateway.logger))
 
-	httpClient.DumpRequest(request)
+	httpClient.DumpRequest(request.HTTPReq)
+
+	attempts := 3
+	if request.SendOnce {
+		attempts = 1
+	}
+
+	for i := 0; i < attempts; i++ {
+		// a failed attempt may have read part of the body already
+		if i > 0 && request.SeekableBody != nil {
+			request.SeekableBody.Seek(0, 0)
+			request.HTTPReq.Body = ioutil.NopCloser(request.SeekableBody)
+		}
 
-	for i := 0; i < 3; i++ {
-		response, err = httpClient.Do(request)
+		response, err = httpClient.Do(request.HTTPReq)
 		if response == nil && err != nil {
 			continue
 		} else {
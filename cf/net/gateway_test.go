@@ -97,6 +97,17 @@ var _ = Describe("Gateway", func() {
 			Expect(apiErr).To(HaveOccurred())
 			Expect(client.DoCallCount()).To(Equal(3))
 		})
+
+		It("does not retry requests that their caller retries", func() {
+			client.DoReturns(nil, errors.New("Connection refused"))
+			request, apiErr := ccGateway.NewRequest("PUT", "https://example.com/v2/apps/guid/bits", "BEARER my-access-token", strings.NewReader("bits"))
+			Expect(apiErr).ToNot(HaveOccurred())
+			request.SendOnce = true
+
+			_, apiErr = ccGateway.PerformRequest(request)
+			Expect(apiErr).To(HaveOccurred())
+			Expect(client.DoCallCount()).To(Equal(1))
+		})
 	})
 
 	Describe("NewRequest", func() {
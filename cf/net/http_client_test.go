@@ -153,6 +153,13 @@ var _ = Describe("HTTP Client", func() {
 			Expect(ok).To(BeFalse())
 		})
 
+		It("returns a NetworkError for other errors", func() {
+			err := WrapNetworkErrors("example.com", &url.Error{Err: &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}})
+			_, ok := err.(*errors.NetworkError)
+			Expect(ok).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("connection reset by peer"))
+		})
+
 		It("returns an error with a tip when it is a tcp dial error", func() {
 			err := WrapNetworkErrors("example.com", &url.Error{Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("tcp-dial-error")}})
 			Expect(err).To(HaveOccurred())
@@ -16,6 +16,8 @@ type ProgressReader struct {
 	quit           chan bool
 	ui             terminal.UI
 	outputInterval time.Duration
+	progressFormat string
+	doneMessage    string
 }
 
 func NewProgressReader(readSeeker io.ReadSeeker, ui terminal.UI, outputInterval time.Duration) *ProgressReader {
@@ -23,6 +25,8 @@ func NewProgressReader(readSeeker io.ReadSeeker, ui terminal.UI, outputInterval
 		ioReadSeeker:   readSeeker,
 		ui:             ui,
 		outputInterval: outputInterval,
+		progressFormat: "%s uploaded...",
+		doneMessage:    "Done uploading",
 	}
 }
 
@@ -33,27 +37,34 @@ func (progressReader *ProgressReader) Read(p []byte) (int, error) {
 
 	n, err := progressReader.ioReadSeeker.Read(p)
 
-	if progressReader.total > int64(0) {
-		if n > 0 {
-			if progressReader.quit == nil {
-				progressReader.quit = make(chan bool)
-				go progressReader.printProgress(progressReader.quit)
-			}
+	if n > 0 {
+		if progressReader.quit == nil {
+			progressReader.quit = make(chan bool)
+			go progressReader.printProgress(progressReader.quit)
+		}
+
+		progressReader.bytesRead += int64(n)
+	}
 
-			progressReader.bytesRead += int64(n)
+	// without a total size, e.g. for a streamed body, the content is all read
+	// when the end of it is reached
+	done := progressReader.bytesRead == progressReader.total ||
+		(progressReader.total == 0 && err == io.EOF)
 
-			if progressReader.total == progressReader.bytesRead {
-				progressReader.quit <- true
-				return n, err
-			}
-		}
+	if done && progressReader.quit != nil {
+		progressReader.quit <- true
+		progressReader.quit = nil
 	}
 
 	return n, err
 }
 
 func (progressReader *ProgressReader) Seek(offset int64, whence int) (int64, error) {
-	return progressReader.ioReadSeeker.Seek(offset, whence)
+	position, err := progressReader.ioReadSeeker.Seek(offset, whence)
+	if err == nil {
+		progressReader.bytesRead = position
+	}
+	return position, err
 }
 
 func (progressReader *ProgressReader) printProgress(quit chan bool) {
@@ -65,10 +76,10 @@ func (progressReader *ProgressReader) printProgress(quit chan bool) {
 			//The spaces are there to ensure we overwrite the entire line
 			//before using the terminal printer to output Done Uploading
 			progressReader.ui.PrintCapturingNoOutput("\r                             ")
-			progressReader.ui.Say("\rDone uploading")
+			progressReader.ui.Say("\r" + progressReader.doneMessage)
 			return
 		case <-timer.C:
-			progressReader.ui.PrintCapturingNoOutput("\r%s uploaded...", formatters.ByteSize(progressReader.bytesRead))
+			progressReader.ui.PrintCapturingNoOutput("\r"+progressReader.progressFormat, formatters.ByteSize(progressReader.bytesRead))
 		}
 	}
 }
@@ -76,3 +87,11 @@ func (progressReader *ProgressReader) printProgress(quit chan bool) {
 func (progressReader *ProgressReader) SetTotalSize(size int64) {
 	progressReader.total = size
 }
+
+// SetMessages changes what is shown while the content is read, with a %s for
+// the size read so far, and once it is all read. By default they tell how much
+// was uploaded.
+func (progressReader *ProgressReader) SetMessages(progressFormat string, doneMessage string) {
+	progressReader.progressFormat = progressFormat
+	progressReader.doneMessage = doneMessage
+}
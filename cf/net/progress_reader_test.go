@@ -50,6 +50,41 @@ var _ = Describe("ProgressReader", func() {
 		Expect(ui.Outputs).To(ContainSubstrings([]string{"\rDone "}))
 	})
 
+	It("prints progress until the end is reached when the total size is not known", func() {
+		progressReader = NewProgressReader(testFile, ui, 1*time.Millisecond)
+
+		for {
+			time.Sleep(50 * time.Microsecond)
+			_, err := progressReader.Read(b)
+			if err != nil {
+				break
+			}
+		}
+
+		Expect(ui.UncapturedOutput).To(ContainSubstrings([]string{"\r", "uploaded..."}))
+		Eventually(func() []string { return ui.Outputs }).Should(ContainSubstrings([]string{"\rDone "}))
+	})
+
+	It("starts counting again when it is rewound", func() {
+		_, err := progressReader.Read(b)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = progressReader.Seek(0, 0)
+		Expect(err).NotTo(HaveOccurred())
+
+		bytesRead := 0
+		for {
+			n, err := progressReader.Read(b)
+			if err != nil {
+				break
+			}
+			bytesRead += n
+		}
+
+		Expect(int64(bytesRead)).To(Equal(fileStat.Size()))
+		Eventually(func() []string { return ui.Outputs }).Should(ContainSubstrings([]string{"\rDone "}))
+	})
+
 	It("reads the correct number of bytes", func() {
 		bytesRead := 0
 
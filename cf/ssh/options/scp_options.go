@@ -0,0 +1,83 @@
+package options
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cloudfoundry/cli/flags"
+)
+
+// SCPOptions describe a copy between the local machine and an app instance.
+// Exactly one of SOURCE and TARGET names an app instance, as
+// APP_NAME[:INDEX]:PATH.
+type SCPOptions struct {
+	AppName             string
+	Index               uint
+	RemotePath          string
+	LocalPath           string
+	Upload              bool
+	Recursive           bool
+	PreservePermissions bool
+	SkipHostValidation  bool
+}
+
+// remoteLocation matches APP_NAME[:INDEX]:PATH. A single letter before the
+// colon is a Windows drive rather than an app, and so is anything with a path
+// separator in it.
+var remoteLocation = regexp.MustCompile(`^([^:/\\]{2,}):(?:(\d+):)?(.*)$`)
+
+func NewSCPOptions(fc flags.FlagContext) (*SCPOptions, error) {
+	scpOptions := &SCPOptions{
+		Recursive:           fc.Bool("r"),
+		PreservePermissions: fc.Bool("p"),
+		SkipHostValidation:  fc.Bool("k"),
+	}
+
+	source, target := fc.Args()[0], fc.Args()[1]
+	sourceMatch := remoteLocation.FindStringSubmatch(source)
+	targetMatch := remoteLocation.FindStringSubmatch(target)
+
+	var remote []string
+	switch {
+	case sourceMatch != nil && targetMatch != nil:
+		return scpOptions, fmt.Errorf("Only one of SOURCE and TARGET can be on an app instance")
+	case sourceMatch != nil:
+		remote = sourceMatch
+		scpOptions.LocalPath = target
+	case targetMatch != nil:
+		remote = targetMatch
+		scpOptions.LocalPath = source
+		scpOptions.Upload = true
+	default:
+		return scpOptions, fmt.Errorf("One of SOURCE and TARGET must be on an app instance, as APP_NAME[:INDEX]:PATH")
+	}
+
+	scpOptions.AppName = remote[1]
+	if remote[2] != "" {
+		index, err := strconv.ParseUint(remote[2], 10, 32)
+		if err != nil {
+			return scpOptions, fmt.Errorf("Invalid app instance index %q", remote[2])
+		}
+		scpOptions.Index = uint(index)
+	}
+
+	scpOptions.RemotePath = remote[3]
+	if strings.TrimSpace(scpOptions.RemotePath) == "" {
+		scpOptions.RemotePath = "."
+	}
+
+	return scpOptions, nil
+}
+
+// SSHOptions returns the options to connect to the app instance with.
+func (o *SCPOptions) SSHOptions() *SSHOptions {
+	return &SSHOptions{
+		AppName:             o.AppName,
+		Index:               o.Index,
+		SkipHostValidation:  o.SkipHostValidation,
+		SkipRemoteExecution: true,
+		TerminalRequest:     REQUEST_TTY_NO,
+	}
+}
@@ -0,0 +1,118 @@
+package options_test
+
+import (
+	"github.com/cloudfoundry/cli/cf/ssh/options"
+	"github.com/cloudfoundry/cli/flags"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SCPOptions", func() {
+	var (
+		opts       *options.SCPOptions
+		args       []string
+		parseError error
+		fc         flags.FlagContext
+	)
+
+	BeforeEach(func() {
+		fc = flags.New()
+		fc.NewBoolFlag("recursive", "r", "")
+		fc.NewBoolFlag("preserve", "p", "")
+		fc.NewBoolFlag("skip-host-validation", "k", "")
+
+		args = []string{}
+		parseError = nil
+	})
+
+	JustBeforeEach(func() {
+		err := fc.Parse(args...)
+		Expect(err).NotTo(HaveOccurred())
+
+		opts, parseError = options.NewSCPOptions(fc)
+	})
+
+	Context("when the source is on an app instance", func() {
+		BeforeEach(func() {
+			args = append(args, "app-1:3:/home/vcap/app/heap.hprof", "dumps/heap.hprof")
+		})
+
+		It("downloads the remote path of that instance to the local path", func() {
+			Expect(parseError).NotTo(HaveOccurred())
+			Expect(opts.Upload).To(BeFalse())
+			Expect(opts.AppName).To(Equal("app-1"))
+			Expect(opts.Index).To(Equal(uint(3)))
+			Expect(opts.RemotePath).To(Equal("/home/vcap/app/heap.hprof"))
+			Expect(opts.LocalPath).To(Equal("dumps/heap.hprof"))
+		})
+	})
+
+	Context("when the target is on an app instance", func() {
+		BeforeEach(func() {
+			args = append(args, "-r", "-p", "-k", "config", "app-1:app/config")
+		})
+
+		It("uploads the local path to the first instance", func() {
+			Expect(parseError).NotTo(HaveOccurred())
+			Expect(opts.Upload).To(BeTrue())
+			Expect(opts.AppName).To(Equal("app-1"))
+			Expect(opts.Index).To(Equal(uint(0)))
+			Expect(opts.RemotePath).To(Equal("app/config"))
+			Expect(opts.LocalPath).To(Equal("config"))
+			Expect(opts.Recursive).To(BeTrue())
+			Expect(opts.PreservePermissions).To(BeTrue())
+			Expect(opts.SkipHostValidation).To(BeTrue())
+		})
+
+		It("connects to the instance without running a command", func() {
+			sshOpts := opts.SSHOptions()
+			Expect(sshOpts.AppName).To(Equal("app-1"))
+			Expect(sshOpts.SkipHostValidation).To(BeTrue())
+			Expect(sshOpts.TerminalRequest).To(Equal(options.REQUEST_TTY_NO))
+		})
+	})
+
+	Context("when the remote path is empty", func() {
+		BeforeEach(func() {
+			args = append(args, "heap.hprof", "app-1:")
+		})
+
+		It("copies to the home directory", func() {
+			Expect(parseError).NotTo(HaveOccurred())
+			Expect(opts.RemotePath).To(Equal("."))
+		})
+	})
+
+	Context("when the local path has a Windows drive", func() {
+		BeforeEach(func() {
+			args = append(args, "app-1:heap.hprof", `C:\dumps\heap.hprof`)
+		})
+
+		It("is not taken for an app", func() {
+			Expect(parseError).NotTo(HaveOccurred())
+			Expect(opts.AppName).To(Equal("app-1"))
+			Expect(opts.LocalPath).To(Equal(`C:\dumps\heap.hprof`))
+		})
+	})
+
+	Context("when neither path is on an app instance", func() {
+		BeforeEach(func() {
+			args = append(args, "heap.hprof", "./dumps")
+		})
+
+		It("returns an error", func() {
+			Expect(parseError).To(MatchError(ContainSubstring("must be on an app instance")))
+		})
+	})
+
+	Context("when both paths are on app instances", func() {
+		BeforeEach(func() {
+			args = append(args, "app-1:heap.hprof", "app-2:heap.hprof")
+		})
+
+		It("returns an error", func() {
+			Expect(parseError).To(MatchError(ContainSubstring("Only one of SOURCE and TARGET")))
+		})
+	})
+})
@@ -29,6 +29,14 @@ type SSHOptions struct {
 	SkipRemoteExecution bool
 	TerminalRequest     TTYRequest
 	ForwardSpecs        []ForwardSpec
+
+	// RemoteForwardSpecs listen in the app container and connect from the
+	// local machine.
+	RemoteForwardSpecs []ForwardSpec
+
+	// DynamicForwardAddresses are the local addresses of SOCKS5 proxies
+	// that connect from the app container.
+	DynamicForwardAddresses []string
 }
 
 func NewSSHOptions(fc flags.FlagContext) (*SSHOptions, error) {
@@ -42,7 +50,7 @@ func NewSSHOptions(fc flags.FlagContext) (*SSHOptions, error) {
 
 	if fc.IsSet("L") {
 		for _, arg := range fc.StringSlice("L") {
-			forwardSpec, err := sshOptions.parseLocalForwardingSpec(arg)
+			forwardSpec, err := sshOptions.parseForwardingSpec(arg, "local")
 			if err != nil {
 				return sshOptions, err
 			}
@@ -50,6 +58,26 @@ func NewSSHOptions(fc flags.FlagContext) (*SSHOptions, error) {
 		}
 	}
 
+	if fc.IsSet("R") {
+		for _, arg := range fc.StringSlice("R") {
+			forwardSpec, err := sshOptions.parseForwardingSpec(arg, "remote")
+			if err != nil {
+				return sshOptions, err
+			}
+			sshOptions.RemoteForwardSpecs = append(sshOptions.RemoteForwardSpecs, *forwardSpec)
+		}
+	}
+
+	if fc.IsSet("D") {
+		for _, arg := range fc.StringSlice("D") {
+			address, err := sshOptions.parseDynamicForwardingSpec(arg)
+			if err != nil {
+				return sshOptions, err
+			}
+			sshOptions.DynamicForwardAddresses = append(sshOptions.DynamicForwardAddresses, address)
+		}
+	}
+
 	if fc.IsSet("t") && fc.Bool("t") {
 		sshOptions.TerminalRequest = REQUEST_TTY_YES
 	}
@@ -65,18 +93,12 @@ func NewSSHOptions(fc flags.FlagContext) (*SSHOptions, error) {
 	return sshOptions, nil
 }
 
-func (o *SSHOptions) parseLocalForwardingSpec(arg string) (*ForwardSpec, error) {
+func (o *SSHOptions) parseForwardingSpec(arg string, kind string) (*ForwardSpec, error) {
 	arg = strings.TrimSpace(arg)
 
-	parts := []string{}
-	for remainder := arg; remainder != ""; {
-		part, r, err := tokenizeForward(remainder)
-		if err != nil {
-			return nil, err
-		}
-
-		parts = append(parts, part)
-		remainder = r
+	parts, err := tokenizeForwardSpec(arg)
+	if err != nil {
+		return nil, err
 	}
 
 	forwardSpec := &ForwardSpec{}
@@ -91,12 +113,47 @@ func (o *SSHOptions) parseLocalForwardingSpec(arg string) (*ForwardSpec, error)
 		forwardSpec.ListenAddress = fmt.Sprintf("localhost:%s", parts[0])
 		forwardSpec.ConnectAddress = fmt.Sprintf("%s:%s", parts[1], parts[2])
 	default:
-		return nil, fmt.Errorf("Unable to parse local forwarding argument: %q", arg)
+		return nil, fmt.Errorf("Unable to parse %s forwarding argument: %q", kind, arg)
 	}
 
 	return forwardSpec, nil
 }
 
+func (o *SSHOptions) parseDynamicForwardingSpec(arg string) (string, error) {
+	arg = strings.TrimSpace(arg)
+
+	parts, err := tokenizeForwardSpec(arg)
+	if err != nil {
+		return "", err
+	}
+
+	switch len(parts) {
+	case 2:
+		if parts[0] == "*" {
+			parts[0] = ""
+		}
+		return fmt.Sprintf("%s:%s", parts[0], parts[1]), nil
+	case 1:
+		return fmt.Sprintf("localhost:%s", parts[0]), nil
+	default:
+		return "", fmt.Errorf("Unable to parse dynamic forwarding argument: %q", arg)
+	}
+}
+
+func tokenizeForwardSpec(arg string) ([]string, error) {
+	parts := []string{}
+	for remainder := arg; remainder != ""; {
+		part, r, err := tokenizeForward(remainder)
+		if err != nil {
+			return nil, err
+		}
+
+		parts = append(parts, part)
+		remainder = r
+	}
+	return parts, nil
+}
+
 func tokenizeForward(arg string) (string, string, error) {
 	switch arg[0] {
 	case ':':
@@ -20,6 +20,8 @@ var _ = Describe("SSHOptions", func() {
 		BeforeEach(func() {
 			fc = flags.New()
 			fc.NewStringSliceFlag("L", "", "")
+			fc.NewStringSliceFlag("R", "", "")
+			fc.NewStringSliceFlag("D", "", "")
 			fc.NewStringSliceFlag("command", "c", "")
 			fc.NewIntFlag("app-instance-index", "i", "")
 			fc.NewBoolFlag("skip-host-validation", "k", "")
@@ -281,6 +283,85 @@ var _ = Describe("SSHOptions", func() {
 			})
 		})
 
+		Context("when remote port forwarding is requested", func() {
+			BeforeEach(func() {
+				args = append(args, "app-name")
+			})
+
+			Context("without an explicit bind address", func() {
+				BeforeEach(func() {
+					args = append(args, "-R", "9999:localhost:8888")
+				})
+
+				It("listens on the loopback interface of the app container", func() {
+					Expect(parseError).NotTo(HaveOccurred())
+					Expect(opts.RemoteForwardSpecs).To(ConsistOf(options.ForwardSpec{ListenAddress: "localhost:9999", ConnectAddress: "localhost:8888"}))
+				})
+			})
+
+			Context("with an explicit bind address", func() {
+				BeforeEach(func() {
+					args = append(args, "-R", "*:9999:localhost:8888", "-R", "[::1]:7777:[::1]:6666")
+				})
+
+				It("sets the forward specs", func() {
+					Expect(parseError).NotTo(HaveOccurred())
+					Expect(opts.RemoteForwardSpecs).To(ConsistOf(
+						options.ForwardSpec{ListenAddress: ":9999", ConnectAddress: "localhost:8888"},
+						options.ForwardSpec{ListenAddress: "[::1]:7777", ConnectAddress: "[::1]:6666"},
+					))
+				})
+			})
+
+			Context("with too few parts", func() {
+				BeforeEach(func() {
+					args = append(args, "-R", "9999:localhost")
+				})
+
+				It("returns an error", func() {
+					Expect(parseError).To(MatchError(`Unable to parse remote forwarding argument: "9999:localhost"`))
+				})
+			})
+		})
+
+		Context("when dynamic port forwarding is requested", func() {
+			BeforeEach(func() {
+				args = append(args, "app-name")
+			})
+
+			Context("with only a port", func() {
+				BeforeEach(func() {
+					args = append(args, "-D", "1080")
+				})
+
+				It("listens on the local loopback interface", func() {
+					Expect(parseError).NotTo(HaveOccurred())
+					Expect(opts.DynamicForwardAddresses).To(ConsistOf("localhost:1080"))
+				})
+			})
+
+			Context("with a bind address", func() {
+				BeforeEach(func() {
+					args = append(args, "-D", "*:1080", "-D", "[::1]:1081")
+				})
+
+				It("sets the listen addresses", func() {
+					Expect(parseError).NotTo(HaveOccurred())
+					Expect(opts.DynamicForwardAddresses).To(ConsistOf(":1080", "[::1]:1081"))
+				})
+			})
+
+			Context("with a connect address", func() {
+				BeforeEach(func() {
+					args = append(args, "-D", "1080:remote:80")
+				})
+
+				It("returns an error", func() {
+					Expect(parseError).To(MatchError(`Unable to parse dynamic forwarding argument: "1080:remote:80"`))
+				})
+			})
+		})
+
 		Context("when -N is specified", func() {
 			BeforeEach(func() {
 				args = append(args, "app-name", "-N")
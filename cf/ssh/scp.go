@@ -0,0 +1,399 @@
+package sshCmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf/ssh/options"
+)
+
+// CopyProgress wraps the content of a file while it is copied, e.g. to show
+// how much of it has been copied so far.
+type CopyProgress func(name string, content io.Reader, size int64) io.Reader
+
+// SecureCopy copies files between the local machine and the app instance with
+// the scp protocol, talking to an scp process started on the instance.
+func (c *secureShell) SecureCopy(opts *options.SCPOptions, progress CopyProgress) error {
+	if progress == nil {
+		progress = func(_ string, content io.Reader, _ int64) io.Reader { return content }
+	}
+
+	session, err := c.secureClient.NewSession()
+	if err != nil {
+		return fmt.Errorf("SSH session allocation failed: %s", err.Error())
+	}
+	defer session.Close()
+
+	inPipe, err := session.StdinPipe()
+	if err != nil {
+		return err
+	}
+
+	outPipe, err := session.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	scp := &scpConn{
+		in:       inPipe,
+		out:      bufio.NewReader(outPipe),
+		preserve: opts.PreservePermissions,
+		progress: progress,
+	}
+
+	if opts.Upload {
+		err = c.upload(session, scp, opts)
+	} else {
+		err = c.download(session, scp, opts)
+	}
+	if err != nil {
+		return err
+	}
+
+	return session.Wait()
+}
+
+func (c *secureShell) upload(session SecureSession, scp *scpConn, opts *options.SCPOptions) error {
+	info, err := os.Stat(opts.LocalPath)
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() && !opts.Recursive {
+		return fmt.Errorf("%s is a directory (use -r to copy directories)", opts.LocalPath)
+	}
+
+	err = session.Start(scpCommand("-t", opts))
+	if err != nil {
+		return err
+	}
+
+	err = scp.readAck()
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		err = scp.sendDir(opts.LocalPath, info)
+	} else {
+		err = scp.sendFile(opts.LocalPath, info)
+	}
+	if err != nil {
+		return err
+	}
+
+	return scp.in.Close()
+}
+
+func (c *secureShell) download(session SecureSession, scp *scpConn, opts *options.SCPOptions) error {
+	err := session.Start(scpCommand("-f", opts))
+	if err != nil {
+		return err
+	}
+
+	err = scp.receive(opts.LocalPath)
+	if err != nil {
+		return err
+	}
+
+	return scp.in.Close()
+}
+
+// scpCommand is the command line of the scp process on the app instance,
+// which is the sink (-t) or source (-f) of the copy.
+func scpCommand(mode string, opts *options.SCPOptions) string {
+	args := []string{"scp", mode}
+	if opts.Recursive {
+		args = append(args, "-r")
+	}
+	if opts.PreservePermissions {
+		args = append(args, "-p")
+	}
+	args = append(args, "--", shellQuote(opts.RemotePath))
+	return strings.Join(args, " ")
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// scpConn speaks the scp protocol with the remote scp process. Each message
+// is a line like "C0644 1024 name", which the other side acknowledges with a
+// zero byte, or with 1 or 2 and an error message.
+type scpConn struct {
+	in       io.WriteCloser
+	out      *bufio.Reader
+	preserve bool
+	progress CopyProgress
+}
+
+func (scp *scpConn) readAck() error {
+	code, err := scp.out.ReadByte()
+	if err != nil {
+		return err
+	}
+
+	switch code {
+	case 0:
+		return nil
+	case 1, 2:
+		message, _ := scp.out.ReadString('\n')
+		return errors.New(strings.TrimSpace(message))
+	default:
+		return fmt.Errorf("Unexpected response from scp: %q", code)
+	}
+}
+
+func (scp *scpConn) ack() error {
+	_, err := scp.in.Write([]byte{0})
+	return err
+}
+
+func (scp *scpConn) send(format string, a ...interface{}) error {
+	_, err := fmt.Fprintf(scp.in, format, a...)
+	if err != nil {
+		return err
+	}
+	return scp.readAck()
+}
+
+func (scp *scpConn) sendTimes(info os.FileInfo) error {
+	if !scp.preserve {
+		return nil
+	}
+	mtime := info.ModTime().Unix()
+	return scp.send("T%d 0 %d 0\n", mtime, mtime)
+}
+
+func (scp *scpConn) sendFile(path string, info os.FileInfo) error {
+	err := scp.sendTimes(info)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = scp.send("C%04o %d %s\n", info.Mode().Perm(), info.Size(), info.Name())
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(scp.in, scp.progress(info.Name(), file, info.Size()))
+	if err != nil {
+		return err
+	}
+
+	return scp.send("\x00")
+}
+
+func (scp *scpConn) sendDir(path string, info os.FileInfo) error {
+	err := scp.sendTimes(info)
+	if err != nil {
+		return err
+	}
+
+	err = scp.send("D%04o 0 %s\n", info.Mode().Perm(), info.Name())
+	if err != nil {
+		return err
+	}
+
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		entryPath := filepath.Join(path, entry.Name())
+
+		// follow symbolic links, like scp does
+		entryInfo, err := os.Stat(entryPath)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case entryInfo.IsDir():
+			err = scp.sendDir(entryPath, entryInfo)
+		case entryInfo.Mode().IsRegular():
+			err = scp.sendFile(entryPath, entryInfo)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return scp.send("E\n")
+}
+
+// receive writes what the remote scp process sends to target. If target is
+// an existing directory the files are put into it, otherwise the first file
+// or directory sent becomes target.
+func (scp *scpConn) receive(target string) error {
+	dirs := []string{}
+	dirTimes := []*time.Time{}
+	var mtime *time.Time
+
+	err := scp.ack()
+	if err != nil {
+		return err
+	}
+
+	for {
+		code, err := scp.out.ReadByte()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		line, err := scp.out.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		line = strings.TrimSuffix(line, "\n")
+
+		switch code {
+		case 1, 2:
+			return errors.New(line)
+		case 'T':
+			var sec, usec, asec, ausec int64
+			_, err = fmt.Sscanf(line, "%d %d %d %d", &sec, &usec, &asec, &ausec)
+			if err != nil {
+				return fmt.Errorf("Invalid scp message: %q", line)
+			}
+			t := time.Unix(sec, usec*1000)
+			mtime = &t
+		case 'C', 'D':
+			mode, size, name, err := parseSCPEntry(line)
+			if err != nil {
+				return err
+			}
+
+			path := target
+			if len(dirs) > 0 {
+				path = filepath.Join(dirs[len(dirs)-1], name)
+			} else if info, statErr := os.Stat(target); statErr == nil && info.IsDir() {
+				path = filepath.Join(target, name)
+			}
+
+			if code == 'D' {
+				err = scp.receiveDir(path, mode)
+				dirs = append(dirs, path)
+				dirTimes = append(dirTimes, mtime)
+			} else if err = scp.ack(); err == nil {
+				err = scp.receiveFile(path, name, mode, size, mtime)
+			}
+			if err != nil {
+				return err
+			}
+			mtime = nil
+		case 'E':
+			if len(dirs) == 0 {
+				return fmt.Errorf("Invalid scp message: %q", "E"+line)
+			}
+			scp.setTimes(dirs[len(dirs)-1], dirTimes[len(dirTimes)-1])
+			dirs = dirs[:len(dirs)-1]
+			dirTimes = dirTimes[:len(dirTimes)-1]
+		default:
+			return fmt.Errorf("Invalid scp message: %q", string(code)+line)
+		}
+
+		err = scp.ack()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (scp *scpConn) receiveDir(path string, mode os.FileMode) error {
+	info, err := os.Stat(path)
+	switch {
+	case err == nil && !info.IsDir():
+		return fmt.Errorf("%s is not a directory", path)
+	case os.IsNotExist(err):
+		err = os.Mkdir(path, mode|0700)
+	}
+	if err != nil {
+		return err
+	}
+
+	if scp.preserve {
+		return os.Chmod(path, mode)
+	}
+	return nil
+}
+
+func (scp *scpConn) receiveFile(path string, name string, mode os.FileMode, size int64, mtime *time.Time) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(file, scp.progress(name, io.LimitReader(scp.out, size), size))
+	closeErr := file.Close()
+	if err != nil {
+		return err
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+
+	err = scp.readAck()
+	if err != nil {
+		return err
+	}
+
+	if scp.preserve {
+		err = os.Chmod(path, mode)
+		if err != nil {
+			return err
+		}
+	}
+	scp.setTimes(path, mtime)
+	return nil
+}
+
+func (scp *scpConn) setTimes(path string, mtime *time.Time) {
+	if scp.preserve && mtime != nil {
+		os.Chtimes(path, *mtime, *mtime)
+	}
+}
+
+// parseSCPEntry parses the "MODE SIZE NAME" of a file or directory message.
+// The name must not lead out of the directory it is copied into.
+func parseSCPEntry(line string) (os.FileMode, int64, string, error) {
+	parts := strings.SplitN(line, " ", 3)
+	if len(parts) != 3 {
+		return 0, 0, "", fmt.Errorf("Invalid scp message: %q", line)
+	}
+
+	mode, err := strconv.ParseUint(parts[0], 8, 32)
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("Invalid scp message: %q", line)
+	}
+
+	size, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || size < 0 {
+		return 0, 0, "", fmt.Errorf("Invalid scp message: %q", line)
+	}
+
+	name := parts[2]
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return 0, 0, "", fmt.Errorf("Invalid file name from scp: %q", name)
+	}
+
+	return os.FileMode(mode).Perm(), size, name, nil
+}
@@ -0,0 +1,248 @@
+package sshCmd_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/ssh"
+	"github.com/cloudfoundry/cli/cf/ssh/options"
+	"github.com/cloudfoundry/cli/cf/ssh/sshfakes"
+	"github.com/cloudfoundry/cli/cf/ssh/terminal"
+	"github.com/onsi/gomega/gbytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SecureCopy", func() {
+	var (
+		fakeSecureClient  *sshfakes.FakeSecureClient
+		fakeSecureDialer  *sshfakes.FakeSecureDialer
+		fakeSecureSession *sshfakes.FakeSecureSession
+
+		secureShell sshCmd.SecureShell
+		opts        *options.SCPOptions
+		stdin       *gbytes.Buffer
+		stdout      *bytes.Buffer
+		localDir    string
+		progress    sshCmd.CopyProgress
+		copyErr     error
+	)
+
+	BeforeEach(func() {
+		fakeSecureClient = new(sshfakes.FakeSecureClient)
+		fakeSecureDialer = new(sshfakes.FakeSecureDialer)
+		fakeSecureSession = new(sshfakes.FakeSecureSession)
+
+		fakeSecureDialer.DialReturns(fakeSecureClient, nil)
+		fakeSecureClient.NewSessionReturns(fakeSecureSession, nil)
+
+		stdin = gbytes.NewBuffer()
+		stdout = &bytes.Buffer{}
+		fakeSecureSession.StdinPipeReturns(stdin, nil)
+		fakeSecureSession.StdoutPipeReturns(stdout, nil)
+
+		var err error
+		localDir, err = ioutil.TempDir("", "scp")
+		Expect(err).NotTo(HaveOccurred())
+
+		progress = nil
+		opts = &options.SCPOptions{
+			AppName:            "app-1",
+			SkipHostValidation: true,
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(localDir)
+	})
+
+	JustBeforeEach(func() {
+		app := models.Application{}
+		app.State = "STARTED"
+		app.Diego = true
+
+		secureShell = sshCmd.NewSecureShell(
+			fakeSecureDialer,
+			sshTerminal.DefaultHelper(),
+			sshCmd.DefaultListenerFactory(),
+			30*time.Second,
+			app,
+			"",
+			"ssh.example.com:2222",
+			"token",
+		)
+
+		err := secureShell.Connect(opts.SSHOptions())
+		Expect(err).NotTo(HaveOccurred())
+
+		copyErr = secureShell.SecureCopy(opts, progress)
+	})
+
+	Context("when uploading", func() {
+		BeforeEach(func() {
+			opts.Upload = true
+			opts.RemotePath = "app/it's here"
+			stdout.Write(make([]byte, 16))
+		})
+
+		Context("a file", func() {
+			BeforeEach(func() {
+				opts.LocalPath = filepath.Join(localDir, "heap.hprof")
+				err := ioutil.WriteFile(opts.LocalPath, []byte("hello"), 0640)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("starts scp to receive the file on the instance", func() {
+				Expect(copyErr).NotTo(HaveOccurred())
+				Expect(fakeSecureSession.StartArgsForCall(0)).To(Equal(`scp -t -- 'app/it'\''s here'`))
+			})
+
+			It("sends the file with its permissions", func() {
+				Expect(copyErr).NotTo(HaveOccurred())
+				Expect(string(stdin.Contents())).To(Equal("C0640 5 heap.hprof\nhello\x00"))
+				Expect(stdin.Closed()).To(BeTrue())
+				Expect(fakeSecureSession.WaitCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("a directory", func() {
+			BeforeEach(func() {
+				opts.LocalPath = filepath.Join(localDir, "config")
+				err := os.Mkdir(opts.LocalPath, 0750)
+				Expect(err).NotTo(HaveOccurred())
+				err = ioutil.WriteFile(filepath.Join(opts.LocalPath, "app.yml"), []byte("a: 1"), 0600)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("requires -r", func() {
+				Expect(copyErr).To(MatchError(ContainSubstring("is a directory")))
+				Expect(fakeSecureSession.StartCallCount()).To(Equal(0))
+			})
+
+			Context("with -r", func() {
+				BeforeEach(func() {
+					opts.Recursive = true
+				})
+
+				It("sends the directory and its contents", func() {
+					Expect(copyErr).NotTo(HaveOccurred())
+					Expect(fakeSecureSession.StartArgsForCall(0)).To(Equal(`scp -t -r -- 'app/it'\''s here'`))
+					Expect(string(stdin.Contents())).To(Equal("D0750 0 config\nC0600 4 app.yml\na: 1\x00E\n"))
+				})
+			})
+		})
+
+		Context("when scp on the instance fails", func() {
+			BeforeEach(func() {
+				opts.LocalPath = filepath.Join(localDir, "heap.hprof")
+				err := ioutil.WriteFile(opts.LocalPath, []byte("hello"), 0640)
+				Expect(err).NotTo(HaveOccurred())
+
+				stdout.Reset()
+				stdout.WriteString("\x01scp: app/it's here: Permission denied\n")
+			})
+
+			It("returns its error", func() {
+				Expect(copyErr).To(MatchError("scp: app/it's here: Permission denied"))
+			})
+		})
+	})
+
+	Context("when downloading", func() {
+		BeforeEach(func() {
+			opts.RemotePath = "/home/vcap/app/config"
+			opts.Recursive = true
+			opts.PreservePermissions = true
+			opts.LocalPath = localDir
+			stdout.WriteString("T1400000000 0 1400000000 0\nD0750 0 config\nC0600 4 app.yml\na: 1\x00E\n")
+		})
+
+		It("starts scp to send the files from the instance", func() {
+			Expect(copyErr).NotTo(HaveOccurred())
+			Expect(fakeSecureSession.StartArgsForCall(0)).To(Equal(`scp -f -r -p -- '/home/vcap/app/config'`))
+		})
+
+		It("acknowledges every message", func() {
+			Expect(copyErr).NotTo(HaveOccurred())
+			Expect(stdin.Contents()).To(Equal(make([]byte, 6)))
+		})
+
+		It("writes the files into the local directory, preserving their permissions and times", func() {
+			Expect(copyErr).NotTo(HaveOccurred())
+
+			info, err := os.Stat(filepath.Join(localDir, "config"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0750)))
+			Expect(info.ModTime().Unix()).To(Equal(int64(1400000000)))
+
+			content, err := ioutil.ReadFile(filepath.Join(localDir, "config", "app.yml"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("a: 1"))
+
+			info, err = os.Stat(filepath.Join(localDir, "config", "app.yml"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+		})
+
+		Context("with a progress function", func() {
+			var names []string
+
+			BeforeEach(func() {
+				names = []string{}
+				progress = func(name string, content io.Reader, size int64) io.Reader {
+					names = append(names, name)
+					Expect(size).To(Equal(int64(4)))
+					return content
+				}
+			})
+
+			It("wraps the content of each file with it", func() {
+				Expect(copyErr).NotTo(HaveOccurred())
+				Expect(names).To(Equal([]string{"app.yml"}))
+			})
+		})
+
+		Context("when the local path does not exist", func() {
+			BeforeEach(func() {
+				opts.LocalPath = filepath.Join(localDir, "local-config")
+			})
+
+			It("copies the directory to it", func() {
+				Expect(copyErr).NotTo(HaveOccurred())
+
+				_, err := os.Stat(filepath.Join(localDir, "local-config", "app.yml"))
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		Context("when a file name leads out of the directory", func() {
+			BeforeEach(func() {
+				stdout.Reset()
+				stdout.WriteString("C0600 4 ../app.yml\na: 1\x00")
+			})
+
+			It("refuses to write it", func() {
+				Expect(copyErr).To(MatchError(ContainSubstring("Invalid file name")))
+				_, err := os.Stat(filepath.Join(localDir, "..", "app.yml"))
+				Expect(os.IsNotExist(err)).To(BeTrue())
+			})
+		})
+
+		Context("when scp on the instance fails", func() {
+			BeforeEach(func() {
+				stdout.Reset()
+				stdout.WriteString("\x01scp: /home/vcap/app/config: No such file or directory\n")
+			})
+
+			It("returns its error", func() {
+				Expect(copyErr).To(MatchError("scp: /home/vcap/app/config: No such file or directory"))
+			})
+		})
+	})
+})
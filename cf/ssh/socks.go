@@ -0,0 +1,143 @@
+package sshCmd
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+)
+
+// The parts of SOCKS5 (RFC 1928) a dynamic forward needs: CONNECT requests
+// without authentication.
+const (
+	socksVersion = 5
+
+	socksNoAuth       = 0x00
+	socksNoAcceptable = 0xff
+
+	socksConnect = 0x01
+
+	socksIPv4   = 0x01
+	socksDomain = 0x03
+	socksIPv6   = 0x04
+
+	socksSucceeded               = 0x00
+	socksHostUnreachable         = 0x04
+	socksCommandNotSupported     = 0x07
+	socksAddressTypeNotSupported = 0x08
+)
+
+func (c *secureShell) handleDynamicForwardConnection(conn net.Conn) {
+	defer conn.Close()
+
+	targetAddr, err := readSocksRequest(conn)
+	if err != nil {
+		return
+	}
+
+	target, err := c.secureClient.Dial("tcp", targetAddr)
+	if err != nil {
+		writeSocksReply(conn, socksHostUnreachable)
+		return
+	}
+	defer target.Close()
+
+	err = writeSocksReply(conn, socksSucceeded)
+	if err != nil {
+		return
+	}
+
+	pipe(conn, target)
+}
+
+// readSocksRequest negotiates the authentication method and reads a CONNECT
+// request from a SOCKS5 client, returning the address it wants to connect to.
+func readSocksRequest(conn io.ReadWriter) (string, error) {
+	header := make([]byte, 2)
+	_, err := io.ReadFull(conn, header)
+	if err != nil {
+		return "", err
+	}
+	if header[0] != socksVersion {
+		return "", fmt.Errorf("Unsupported SOCKS version: %d", header[0])
+	}
+
+	methods := make([]byte, header[1])
+	_, err = io.ReadFull(conn, methods)
+	if err != nil {
+		return "", err
+	}
+
+	if !containsByte(methods, socksNoAuth) {
+		conn.Write([]byte{socksVersion, socksNoAcceptable})
+		return "", errors.New("SOCKS client requires authentication")
+	}
+
+	_, err = conn.Write([]byte{socksVersion, socksNoAuth})
+	if err != nil {
+		return "", err
+	}
+
+	request := make([]byte, 4)
+	_, err = io.ReadFull(conn, request)
+	if err != nil {
+		return "", err
+	}
+
+	if request[1] != socksConnect {
+		writeSocksReply(conn, socksCommandNotSupported)
+		return "", fmt.Errorf("Unsupported SOCKS command: %d", request[1])
+	}
+
+	var host string
+	switch request[3] {
+	case socksIPv4, socksIPv6:
+		size := net.IPv4len
+		if request[3] == socksIPv6 {
+			size = net.IPv6len
+		}
+		ip := make([]byte, size)
+		_, err = io.ReadFull(conn, ip)
+		host = net.IP(ip).String()
+	case socksDomain:
+		length := make([]byte, 1)
+		_, err = io.ReadFull(conn, length)
+		if err == nil {
+			domain := make([]byte, length[0])
+			_, err = io.ReadFull(conn, domain)
+			host = string(domain)
+		}
+	default:
+		writeSocksReply(conn, socksAddressTypeNotSupported)
+		return "", fmt.Errorf("Unsupported SOCKS address type: %d", request[3])
+	}
+	if err != nil {
+		return "", err
+	}
+
+	port := make([]byte, 2)
+	_, err = io.ReadFull(conn, port)
+	if err != nil {
+		return "", err
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))), nil
+}
+
+// writeSocksReply answers a request. The bound address is left empty, since
+// the connection is made from the app container.
+func writeSocksReply(w io.Writer, code byte) error {
+	_, err := w.Write([]byte{socksVersion, code, 0, socksIPv4, 0, 0, 0, 0, 0, 0})
+	return err
+}
+
+func containsByte(values []byte, value byte) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,175 @@
+package sshCmd_test
+
+import (
+	"errors"
+	"io"
+	"net"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/ssh"
+	"github.com/cloudfoundry/cli/cf/ssh/options"
+	"github.com/cloudfoundry/cli/cf/ssh/sshfakes"
+	"github.com/cloudfoundry/cli/cf/ssh/terminal"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Dynamic port forwarding", func() {
+	var (
+		fakeSecureClient    *sshfakes.FakeSecureClient
+		fakeSecureDialer    *sshfakes.FakeSecureDialer
+		fakeListenerFactory *sshfakes.FakeListenerFactory
+
+		secureShell   sshCmd.SecureShell
+		proxyListener net.Listener
+		echoListener  net.Listener
+		forwardErr    error
+	)
+
+	BeforeEach(func() {
+		fakeSecureClient = new(sshfakes.FakeSecureClient)
+		fakeSecureDialer = new(sshfakes.FakeSecureDialer)
+		fakeListenerFactory = new(sshfakes.FakeListenerFactory)
+
+		fakeSecureDialer.DialReturns(fakeSecureClient, nil)
+
+		var err error
+		proxyListener, err = net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		fakeListenerFactory.ListenReturns(proxyListener, nil)
+
+		echoListener, err = net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		go func(listener net.Listener) {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				go func() {
+					io.Copy(conn, conn)
+					conn.Close()
+				}()
+			}
+		}(echoListener)
+
+		echoAddress := echoListener.Addr().String()
+		fakeSecureClient.DialStub = func(network, address string) (net.Conn, error) {
+			if address == "db.service.internal:5432" {
+				return net.Dial(network, echoAddress)
+			}
+			return nil, errors.New("no route to host")
+		}
+	})
+
+	JustBeforeEach(func() {
+		app := models.Application{}
+		app.State = "STARTED"
+		app.Diego = true
+
+		secureShell = sshCmd.NewSecureShell(
+			fakeSecureDialer,
+			sshTerminal.DefaultHelper(),
+			fakeListenerFactory,
+			30*time.Second,
+			app,
+			"",
+			"ssh.example.com:2222",
+			"token",
+		)
+
+		err := secureShell.Connect(&options.SSHOptions{
+			AppName:                 "app-1",
+			SkipHostValidation:      true,
+			DynamicForwardAddresses: []string{"localhost:1080"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		forwardErr = secureShell.LocalPortForward()
+	})
+
+	AfterEach(func() {
+		secureShell.Close()
+		echoListener.Close()
+	})
+
+	connectThroughProxy := func(request []byte) (net.Conn, []byte) {
+		conn, err := net.Dial("tcp", proxyListener.Addr().String())
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = conn.Write([]byte{5, 1, 0})
+		Expect(err).NotTo(HaveOccurred())
+
+		method := make([]byte, 2)
+		_, err = io.ReadFull(conn, method)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(method).To(Equal([]byte{5, 0}))
+
+		_, err = conn.Write(request)
+		Expect(err).NotTo(HaveOccurred())
+
+		reply := make([]byte, 10)
+		_, err = io.ReadFull(conn, reply)
+		Expect(err).NotTo(HaveOccurred())
+
+		return conn, reply
+	}
+
+	domainRequest := func(domain string, port int) []byte {
+		request := []byte{5, 1, 0, 3, byte(len(domain))}
+		request = append(request, domain...)
+		return append(request, byte(port>>8), byte(port&0xff))
+	}
+
+	It("listens on the dynamic forward address", func() {
+		Expect(forwardErr).NotTo(HaveOccurred())
+		Expect(fakeListenerFactory.ListenCallCount()).To(Equal(1))
+		network, addr := fakeListenerFactory.ListenArgsForCall(0)
+		Expect(network).To(Equal("tcp"))
+		Expect(addr).To(Equal("localhost:1080"))
+	})
+
+	It("connects to the requested address from the app container", func() {
+		conn, reply := connectThroughProxy(domainRequest("db.service.internal", 5432))
+		defer conn.Close()
+
+		Expect(reply[1]).To(Equal(byte(0)))
+		network, addr := fakeSecureClient.DialArgsForCall(0)
+		Expect(network).To(Equal("tcp"))
+		Expect(addr).To(Equal("db.service.internal:5432"))
+
+		msg := "SELECT 1;"
+		_, err := conn.Write([]byte(msg))
+		Expect(err).NotTo(HaveOccurred())
+
+		response := make([]byte, len(msg))
+		_, err = io.ReadFull(conn, response)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(response)).To(Equal(msg))
+	})
+
+	It("understands IP addresses", func() {
+		conn, _ := connectThroughProxy([]byte{5, 1, 0, 1, 10, 0, 16, 4, 0x1f, 0x90})
+		defer conn.Close()
+
+		_, addr := fakeSecureClient.DialArgsForCall(0)
+		Expect(addr).To(Equal("10.0.16.4:8080"))
+	})
+
+	It("reports addresses that cannot be reached", func() {
+		conn, reply := connectThroughProxy(domainRequest("unknown.internal", 80))
+		defer conn.Close()
+
+		Expect(reply[1]).To(Equal(byte(4)))
+	})
+
+	It("refuses commands other than CONNECT", func() {
+		conn, reply := connectThroughProxy([]byte{5, 2, 0, 1, 0, 0, 0, 0, 0, 0})
+		defer conn.Close()
+
+		Expect(reply[1]).To(Equal(byte(7)))
+		Expect(fakeSecureClient.DialCallCount()).To(Equal(0))
+	})
+})
@@ -34,7 +34,10 @@ const (
 type SecureShell interface {
 	Connect(opts *options.SSHOptions) error
 	InteractiveSession() error
+	RunCommand(stdout io.Writer, stderr io.Writer) error
 	LocalPortForward() error
+	RemotePortForward() error
+	SecureCopy(opts *options.SCPOptions, progress CopyProgress) error
 	Wait() error
 	Close() error
 }
@@ -51,6 +54,7 @@ type SecureClient interface {
 	NewSession() (SecureSession, error)
 	Conn() ssh.Conn
 	Dial(network, address string) (net.Conn, error)
+	Listen(network, address string) (net.Listener, error)
 	Wait() error
 	Close() error
 }
@@ -87,7 +91,7 @@ type secureShell struct {
 	secureClient           SecureClient
 	opts                   *options.SSHOptions
 
-	localListeners []net.Listener
+	listeners []net.Listener
 }
 
 func NewSecureShell(
@@ -109,7 +113,7 @@ func NewSecureShell(
 		sshEndpointFingerprint: sshEndpointFingerprint,
 		sshEndpoint:            sshEndpoint,
 		token:                  token,
-		localListeners:         []net.Listener{},
+		listeners:              []net.Listener{},
 	}
 }
 
@@ -138,7 +142,7 @@ func (c *secureShell) Connect(opts *options.SSHOptions) error {
 }
 
 func (c *secureShell) Close() error {
-	for _, listener := range c.localListeners {
+	for _, listener := range c.listeners {
 		listener.Close()
 	}
 	return c.secureClient.Close()
@@ -150,15 +154,47 @@ func (c *secureShell) LocalPortForward() error {
 		if err != nil {
 			return err
 		}
-		c.localListeners = append(c.localListeners, listener)
+		c.listeners = append(c.listeners, listener)
+
+		connectAddress := forwardSpec.ConnectAddress
+		go c.forwardAcceptLoop(listener, func(conn net.Conn) {
+			c.handleForwardConnection(conn, connectAddress, c.secureClient.Dial)
+		})
+	}
+
+	for _, address := range c.opts.DynamicForwardAddresses {
+		listener, err := c.listenerFactory.Listen("tcp", address)
+		if err != nil {
+			return err
+		}
+		c.listeners = append(c.listeners, listener)
 
-		go c.localForwardAcceptLoop(listener, forwardSpec.ConnectAddress)
+		go c.forwardAcceptLoop(listener, c.handleDynamicForwardConnection)
 	}
 
 	return nil
 }
 
-func (c *secureShell) localForwardAcceptLoop(listener net.Listener, addr string) {
+// RemotePortForward listens in the app container and forwards the
+// connections made there to addresses reachable from the local machine.
+func (c *secureShell) RemotePortForward() error {
+	for _, forwardSpec := range c.opts.RemoteForwardSpecs {
+		listener, err := c.secureClient.Listen("tcp", forwardSpec.ListenAddress)
+		if err != nil {
+			return err
+		}
+		c.listeners = append(c.listeners, listener)
+
+		connectAddress := forwardSpec.ConnectAddress
+		go c.forwardAcceptLoop(listener, func(conn net.Conn) {
+			c.handleForwardConnection(conn, connectAddress, net.Dial)
+		})
+	}
+
+	return nil
+}
+
+func (c *secureShell) forwardAcceptLoop(listener net.Listener, handle func(net.Conn)) {
 	defer listener.Close()
 
 	for {
@@ -171,20 +207,25 @@ func (c *secureShell) localForwardAcceptLoop(listener net.Listener, addr string)
 			return
 		}
 
-		go c.handleForwardConnection(conn, addr)
+		go handle(conn)
 	}
 }
 
-func (c *secureShell) handleForwardConnection(conn net.Conn, targetAddr string) {
+func (c *secureShell) handleForwardConnection(conn net.Conn, targetAddr string, dial func(network, address string) (net.Conn, error)) {
 	defer conn.Close()
 
-	target, err := c.secureClient.Dial("tcp", targetAddr)
+	target, err := dial("tcp", targetAddr)
 	if err != nil {
 		fmt.Printf("connect to %s failed: %s\n", targetAddr, err.Error())
 		return
 	}
 	defer target.Close()
 
+	pipe(conn, target)
+}
+
+// pipe copies between two connections until both directions are done.
+func pipe(conn net.Conn, target net.Conn) {
 	wg := &sync.WaitGroup{}
 	wg.Add(2)
 
@@ -310,6 +351,46 @@ func (c *secureShell) InteractiveSession() error {
 	return result
 }
 
+// RunCommand runs the command from the options without a terminal or any
+// input, writing its output to stdout and stderr.
+func (c *secureShell) RunCommand(stdout io.Writer, stderr io.Writer) error {
+	session, err := c.secureClient.NewSession()
+	if err != nil {
+		return fmt.Errorf("SSH session allocation failed: %s", err.Error())
+	}
+	defer session.Close()
+
+	outPipe, err := session.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	errPipe, err := session.StderrPipe()
+	if err != nil {
+		return err
+	}
+
+	err = session.Start(strings.Join(c.opts.Command, " "))
+	if err != nil {
+		return err
+	}
+
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+
+	go copyAndDone(wg, stdout, outPipe)
+	go copyAndDone(wg, stderr, errPipe)
+
+	keepaliveStopCh := make(chan struct{})
+	defer close(keepaliveStopCh)
+
+	go keepalive(c.secureClient.Conn(), time.NewTicker(c.keepAliveInterval), keepaliveStopCh)
+
+	result := session.Wait()
+	wg.Wait()
+	return result
+}
+
 func (c *secureShell) Wait() error {
 	keepaliveStopCh := make(chan struct{})
 	defer close(keepaliveStopCh)
@@ -466,6 +547,9 @@ type secureClient struct{ client *ssh.Client }
 func (sc *secureClient) Close() error   { return sc.client.Close() }
 func (sc *secureClient) Conn() ssh.Conn { return sc.client.Conn }
 func (sc *secureClient) Wait() error    { return sc.client.Wait() }
+func (sc *secureClient) Listen(n, addr string) (net.Listener, error) {
+	return sc.client.Listen(n, addr)
+}
 func (sc *secureClient) Dial(n, addr string) (net.Conn, error) {
 	return sc.client.Dial(n, addr)
 }
@@ -7,11 +7,13 @@
 package sshCmd_test
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
 	"net"
 	"os"
+	"strings"
 	"syscall"
 	"time"
 
@@ -1123,6 +1125,162 @@ var _ = Describe("SSH", func() {
 		})
 	})
 
+	Describe("RemotePortForward", func() {
+		var (
+			opts               *options.SSHOptions
+			remoteForwardError error
+
+			echoAddress  string
+			echoListener net.Listener
+
+			remoteListener net.Listener
+		)
+
+		BeforeEach(func() {
+			var err error
+			echoListener, err = net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+			echoAddress = echoListener.Addr().String()
+
+			go func(listener net.Listener) {
+				for {
+					conn, err := listener.Accept()
+					if err != nil {
+						return
+					}
+					go func() {
+						io.Copy(conn, conn)
+						conn.Close()
+					}()
+				}
+			}(echoListener)
+
+			remoteListener, err = net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+			fakeSecureClient.ListenReturns(remoteListener, nil)
+
+			opts = &options.SSHOptions{
+				AppName: "app-1",
+				RemoteForwardSpecs: []options.ForwardSpec{{
+					ListenAddress:  "localhost:8080",
+					ConnectAddress: echoAddress,
+				}},
+			}
+
+			currentApp.State = "STARTED"
+			currentApp.Diego = true
+		})
+
+		JustBeforeEach(func() {
+			connectErr := secureShell.Connect(opts)
+			Expect(connectErr).NotTo(HaveOccurred())
+
+			remoteForwardError = secureShell.RemotePortForward()
+		})
+
+		AfterEach(func() {
+			secureShell.Close()
+			remoteListener.Close()
+			echoListener.Close()
+		})
+
+		It("listens on the listen address in the app container", func() {
+			Expect(remoteForwardError).NotTo(HaveOccurred())
+
+			Expect(fakeSecureClient.ListenCallCount()).To(Equal(1))
+			network, addr := fakeSecureClient.ListenArgsForCall(0)
+			Expect(network).To(Equal("tcp"))
+			Expect(addr).To(Equal("localhost:8080"))
+		})
+
+		It("copies data between the remote connections and the local connect address", func() {
+			conn, err := net.Dial("tcp", remoteListener.Addr().String())
+			Expect(err).NotTo(HaveOccurred())
+			defer conn.Close()
+
+			msg := "Hello from the app container\n"
+			_, err = conn.Write([]byte(msg))
+			Expect(err).NotTo(HaveOccurred())
+
+			response := make([]byte, len(msg))
+			_, err = io.ReadFull(conn, response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(response)).To(Equal(msg))
+		})
+
+		Context("when listening in the app container fails", func() {
+			BeforeEach(func() {
+				fakeSecureClient.ListenReturns(nil, errors.New("tcpip-forward request denied by peer"))
+			})
+
+			It("returns the error", func() {
+				Expect(remoteForwardError).To(MatchError("tcpip-forward request denied by peer"))
+			})
+		})
+	})
+
+	Describe("RunCommand", func() {
+		var (
+			opts             *options.SSHOptions
+			stdout, stderr   *bytes.Buffer
+			runCommandErr    error
+			sessionWaitError error
+		)
+
+		BeforeEach(func() {
+			opts = &options.SSHOptions{
+				AppName: "app-1",
+				Command: []string{"ls", "-l"},
+			}
+
+			currentApp.State = "STARTED"
+			currentApp.Diego = true
+
+			stdout = &bytes.Buffer{}
+			stderr = &bytes.Buffer{}
+
+			fakeSecureSession.StdoutPipeReturns(strings.NewReader("total 0\n"), nil)
+			fakeSecureSession.StderrPipeReturns(strings.NewReader("ls: warning\n"), nil)
+
+			sessionWaitError = errors.New("exited 1")
+			fakeSecureSession.WaitReturns(sessionWaitError)
+		})
+
+		JustBeforeEach(func() {
+			connectErr := secureShell.Connect(opts)
+			Expect(connectErr).NotTo(HaveOccurred())
+
+			runCommandErr = secureShell.RunCommand(stdout, stderr)
+		})
+
+		It("starts the command without a terminal or any input", func() {
+			Expect(fakeSecureSession.StartCallCount()).To(Equal(1))
+			Expect(fakeSecureSession.StartArgsForCall(0)).To(Equal("ls -l"))
+			Expect(fakeSecureSession.RequestPtyCallCount()).To(Equal(0))
+			Expect(fakeSecureSession.StdinPipeCallCount()).To(Equal(0))
+		})
+
+		It("writes the output of the command to the writers", func() {
+			Expect(stdout.String()).To(Equal("total 0\n"))
+			Expect(stderr.String()).To(Equal("ls: warning\n"))
+		})
+
+		It("returns the result of the session", func() {
+			Expect(runCommandErr).To(Equal(sessionWaitError))
+			Expect(fakeSecureSession.CloseCallCount()).To(Equal(1))
+		})
+
+		Context("when the session cannot be allocated", func() {
+			BeforeEach(func() {
+				fakeSecureClient.NewSessionReturns(nil, errors.New("no session"))
+			})
+
+			It("returns an error", func() {
+				Expect(runCommandErr).To(MatchError("SSH session allocation failed: no session"))
+			})
+		})
+	})
+
 	Describe("Wait", func() {
 		var opts *options.SSHOptions
 		var waitErr error
@@ -2,6 +2,7 @@
 package sshfakes
 
 import (
+	"io"
 	"sync"
 
 	"github.com/cloudfoundry/cli/cf/ssh"
@@ -23,12 +24,36 @@ type FakeSecureShell struct {
 	interactiveSessionReturns     struct {
 		result1 error
 	}
+	RunCommandStub        func(stdout io.Writer, stderr io.Writer) error
+	runCommandMutex       sync.RWMutex
+	runCommandArgsForCall []struct {
+		stdout io.Writer
+		stderr io.Writer
+	}
+	runCommandReturns struct {
+		result1 error
+	}
 	LocalPortForwardStub        func() error
 	localPortForwardMutex       sync.RWMutex
 	localPortForwardArgsForCall []struct{}
 	localPortForwardReturns     struct {
 		result1 error
 	}
+	RemotePortForwardStub        func() error
+	remotePortForwardMutex       sync.RWMutex
+	remotePortForwardArgsForCall []struct{}
+	remotePortForwardReturns     struct {
+		result1 error
+	}
+	SecureCopyStub        func(opts *options.SCPOptions, progress sshCmd.CopyProgress) error
+	secureCopyMutex       sync.RWMutex
+	secureCopyArgsForCall []struct {
+		opts     *options.SCPOptions
+		progress sshCmd.CopyProgress
+	}
+	secureCopyReturns struct {
+		result1 error
+	}
 	WaitStub        func() error
 	waitMutex       sync.RWMutex
 	waitArgsForCall []struct{}
@@ -99,6 +124,39 @@ func (fake *FakeSecureShell) InteractiveSessionReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeSecureShell) RunCommand(stdout io.Writer, stderr io.Writer) error {
+	fake.runCommandMutex.Lock()
+	fake.runCommandArgsForCall = append(fake.runCommandArgsForCall, struct {
+		stdout io.Writer
+		stderr io.Writer
+	}{stdout, stderr})
+	fake.runCommandMutex.Unlock()
+	if fake.RunCommandStub != nil {
+		return fake.RunCommandStub(stdout, stderr)
+	} else {
+		return fake.runCommandReturns.result1
+	}
+}
+
+func (fake *FakeSecureShell) RunCommandCallCount() int {
+	fake.runCommandMutex.RLock()
+	defer fake.runCommandMutex.RUnlock()
+	return len(fake.runCommandArgsForCall)
+}
+
+func (fake *FakeSecureShell) RunCommandArgsForCall(i int) (io.Writer, io.Writer) {
+	fake.runCommandMutex.RLock()
+	defer fake.runCommandMutex.RUnlock()
+	return fake.runCommandArgsForCall[i].stdout, fake.runCommandArgsForCall[i].stderr
+}
+
+func (fake *FakeSecureShell) RunCommandReturns(result1 error) {
+	fake.RunCommandStub = nil
+	fake.runCommandReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeSecureShell) LocalPortForward() error {
 	fake.localPortForwardMutex.Lock()
 	fake.localPortForwardArgsForCall = append(fake.localPortForwardArgsForCall, struct{}{})
@@ -123,6 +181,63 @@ func (fake *FakeSecureShell) LocalPortForwardReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeSecureShell) RemotePortForward() error {
+	fake.remotePortForwardMutex.Lock()
+	fake.remotePortForwardArgsForCall = append(fake.remotePortForwardArgsForCall, struct{}{})
+	fake.remotePortForwardMutex.Unlock()
+	if fake.RemotePortForwardStub != nil {
+		return fake.RemotePortForwardStub()
+	} else {
+		return fake.remotePortForwardReturns.result1
+	}
+}
+
+func (fake *FakeSecureShell) RemotePortForwardCallCount() int {
+	fake.remotePortForwardMutex.RLock()
+	defer fake.remotePortForwardMutex.RUnlock()
+	return len(fake.remotePortForwardArgsForCall)
+}
+
+func (fake *FakeSecureShell) RemotePortForwardReturns(result1 error) {
+	fake.RemotePortForwardStub = nil
+	fake.remotePortForwardReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSecureShell) SecureCopy(opts *options.SCPOptions, progress sshCmd.CopyProgress) error {
+	fake.secureCopyMutex.Lock()
+	fake.secureCopyArgsForCall = append(fake.secureCopyArgsForCall, struct {
+		opts     *options.SCPOptions
+		progress sshCmd.CopyProgress
+	}{opts, progress})
+	fake.secureCopyMutex.Unlock()
+	if fake.SecureCopyStub != nil {
+		return fake.SecureCopyStub(opts, progress)
+	} else {
+		return fake.secureCopyReturns.result1
+	}
+}
+
+func (fake *FakeSecureShell) SecureCopyCallCount() int {
+	fake.secureCopyMutex.RLock()
+	defer fake.secureCopyMutex.RUnlock()
+	return len(fake.secureCopyArgsForCall)
+}
+
+func (fake *FakeSecureShell) SecureCopyArgsForCall(i int) (*options.SCPOptions, sshCmd.CopyProgress) {
+	fake.secureCopyMutex.RLock()
+	defer fake.secureCopyMutex.RUnlock()
+	return fake.secureCopyArgsForCall[i].opts, fake.secureCopyArgsForCall[i].progress
+}
+
+func (fake *FakeSecureShell) SecureCopyReturns(result1 error) {
+	fake.SecureCopyStub = nil
+	fake.secureCopyReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeSecureShell) Wait() error {
 	fake.waitMutex.Lock()
 	fake.waitArgsForCall = append(fake.waitArgsForCall, struct{}{})
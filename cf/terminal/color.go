@@ -2,6 +2,7 @@ package terminal
 
 import (
 	"fmt"
+	"hash/fnv"
 	"os"
 	"regexp"
 	"runtime"
@@ -15,6 +16,7 @@ const (
 	red     Color = 31
 	green         = 32
 	yellow        = 33
+	blue          = 34
 	magenta       = 35
 	cyan          = 36
 	grey          = 37
@@ -135,6 +137,17 @@ func LogSysHeaderColor(message string) string {
 	return ColorizeBold(message, cyan)
 }
 
+var logPrefixColors = []Color{cyan, yellow, green, magenta, blue, red}
+
+// LogPrefixColor colors the prefix shown in front of the log lines of an
+// app, with a color picked from the app's name so that each app keeps its
+// color between runs.
+func LogPrefixColor(appName string, prefix string) string {
+	hash := fnv.New32a()
+	hash.Write([]byte(appName))
+	return ColorizeBold(prefix, logPrefixColors[hash.Sum32()%uint32(len(logPrefixColors))])
+}
+
 func isTerminal() bool {
 	return terminal.IsTerminal(1)
 }
@@ -0,0 +1,149 @@
+package terminal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"gopkg.in/yaml.v2"
+)
+
+// OutputFormat is the way tables are printed: as padded text, or as
+// structured records that are easier for programs to read.
+type OutputFormat string
+
+const (
+	OutputTable OutputFormat = "table"
+	OutputJSON  OutputFormat = "json"
+	OutputYAML  OutputFormat = "yaml"
+)
+
+func ParseOutputFormat(format string) (OutputFormat, error) {
+	switch OutputFormat(strings.ToLower(format)) {
+	case OutputTable:
+		return OutputTable, nil
+	case OutputJSON:
+		return OutputJSON, nil
+	case OutputYAML:
+		return OutputYAML, nil
+	}
+	return "", errors.New(T("Invalid output format '{{.Format}}'. Use one of: json, yaml, table", map[string]interface{}{"Format": format}))
+}
+
+// IsStructured tells whether tables are printed as records rather than text.
+func (format OutputFormat) IsStructured() bool {
+	return format == OutputJSON || format == OutputYAML
+}
+
+var nonFieldNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// fieldName turns a table header into a field name, for the columns of
+// tables that were not given field names of their own.
+func fieldName(header string, column int) string {
+	name := nonFieldNameChars.ReplaceAllString(strings.ToLower(Decolorize(header)), "_")
+	name = strings.Trim(name, "_")
+	if name == "" {
+		name = fmt.Sprintf("column_%d", column+1)
+	}
+	return name
+}
+
+// record is a table row with its field names, in the order of the columns.
+type record struct {
+	names  []string
+	values []string
+}
+
+func (r record) MarshalJSON() ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	buffer.WriteString("{")
+	for i := range r.names {
+		if i > 0 {
+			buffer.WriteString(",")
+		}
+		name, err := json.Marshal(r.names[i])
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(r.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(name)
+		buffer.WriteString(":")
+		buffer.Write(value)
+	}
+	buffer.WriteString("}")
+	return buffer.Bytes(), nil
+}
+
+func (r record) mapSlice() yaml.MapSlice {
+	items := yaml.MapSlice{}
+	for i := range r.names {
+		items = append(items, yaml.MapItem{Key: r.names[i], Value: r.values[i]})
+	}
+	return items
+}
+
+// printRecords writes the rows of a table as a JSON array or a YAML list of
+// objects, one per row, keyed by the field names of the columns.
+func printRecords(w io.Writer, format OutputFormat, names []string, rows [][]string) error {
+	records := []record{}
+	for _, row := range rows {
+		r := record{names: names, values: make([]string, len(names))}
+		for i := range names {
+			if i < len(row) {
+				r.values[i] = Decolorize(row[i])
+			}
+		}
+		records = append(records, r)
+	}
+
+	switch format {
+	case OutputJSON:
+		data, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	case OutputYAML:
+		items := []yaml.MapSlice{}
+		for _, r := range records {
+			items = append(items, r.mapSlice())
+		}
+		data, err := yaml.Marshal(items)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "---\n%s", data)
+		return err
+	}
+	return nil
+}
+
+type writerPrinter struct {
+	writer io.Writer
+}
+
+// NewWriterPrinter returns a Printer that writes to w, e.g. to os.Stderr.
+func NewWriterPrinter(w io.Writer) Printer {
+	return &writerPrinter{writer: w}
+}
+
+func (p *writerPrinter) Print(a ...interface{}) (int, error) {
+	return fmt.Fprint(p.writer, a...)
+}
+
+func (p *writerPrinter) Printf(format string, a ...interface{}) (int, error) {
+	return fmt.Fprintf(p.writer, format, a...)
+}
+
+func (p *writerPrinter) Println(a ...interface{}) (int, error) {
+	return fmt.Fprintln(p.writer, a...)
+}
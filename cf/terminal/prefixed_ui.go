@@ -0,0 +1,94 @@
+package terminal
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	. "github.com/cloudfoundry/cli/cf/i18n"
+)
+
+type prefixedUI struct {
+	UI
+	prefix string
+	lock   *sync.Mutex
+}
+
+// NewPrefixedUI returns a UI that prints every line through ui with prefix in
+// front of it, for telling apart the output of operations that run at the
+// same time. PrefixedUIs sharing a lock print whole messages at a time, so
+// that their lines do not get mixed up with each other.
+func NewPrefixedUI(ui UI, prefix string, lock *sync.Mutex) UI {
+	return &prefixedUI{
+		UI:     ui,
+		prefix: prefix,
+		lock:   lock,
+	}
+}
+
+func (ui *prefixedUI) PrintPaginator(rows []string, err error) {
+	if err != nil {
+		ui.Failed(err.Error())
+		return
+	}
+
+	for _, row := range rows {
+		ui.Say(row)
+	}
+}
+
+func (ui *prefixedUI) PrintCapturingNoOutput(message string, args ...interface{}) {
+	ui.lock.Lock()
+	defer ui.lock.Unlock()
+
+	ui.UI.PrintCapturingNoOutput("%s", ui.prefixLines(fmt.Sprintf(message, args...)))
+}
+
+func (ui *prefixedUI) Say(message string, args ...interface{}) {
+	if len(args) > 0 {
+		message = fmt.Sprintf(message, args...)
+	}
+
+	ui.lock.Lock()
+	defer ui.lock.Unlock()
+
+	ui.UI.Say("%s", ui.prefixLines(message))
+}
+
+func (ui *prefixedUI) Warn(message string, args ...interface{}) {
+	message = fmt.Sprintf(message, args...)
+	ui.Say(WarningColor(message))
+}
+
+func (ui *prefixedUI) Ok() {
+	ui.Say(SuccessColor(T("OK")))
+}
+
+func (ui *prefixedUI) Failed(message string, args ...interface{}) {
+	message = fmt.Sprintf(message, args...)
+
+	ui.Say(FailureColor(T("FAILED")))
+	ui.Say(message)
+
+	panic(QuietPanic)
+}
+
+// LoadingIndication prints nothing, as the dots it would print do not end
+// in a newline and would run into the lines of other prefixed UIs.
+func (ui *prefixedUI) LoadingIndication() {
+}
+
+func (ui *prefixedUI) Table(headers []string) *UITable {
+	return &UITable{
+		UI:    ui,
+		Table: NewTable(headers),
+	}
+}
+
+func (ui *prefixedUI) prefixLines(message string) string {
+	lines := strings.Split(message, "\n")
+	for i, line := range lines {
+		lines[i] = ui.prefix + line
+	}
+	return strings.Join(lines, "\n")
+}
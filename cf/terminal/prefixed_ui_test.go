@@ -0,0 +1,50 @@
+package terminal_test
+
+import (
+	"sync"
+
+	. "github.com/cloudfoundry/cli/cf/terminal"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PrefixedUI", func() {
+	var (
+		fakeUI *testterm.FakeUI
+		ui     UI
+	)
+
+	BeforeEach(func() {
+		fakeUI = new(testterm.FakeUI)
+		ui = NewPrefixedUI(fakeUI, "app1 | ", &sync.Mutex{})
+	})
+
+	It("prefixes every line it says", func() {
+		ui.Say("Staging %s\nDone", "app1")
+
+		Expect(fakeUI.Outputs).To(Equal([]string{
+			"app1 | Staging app1",
+			"app1 | Done",
+		}))
+	})
+
+	It("prefixes the rows of its tables", func() {
+		table := ui.Table([]string{"name", "state"})
+		table.Add("web", "started")
+		table.Print()
+
+		Expect(fakeUI.Outputs).To(HaveLen(2))
+		for _, line := range fakeUI.Outputs {
+			Expect(line).To(HavePrefix("app1 | "))
+		}
+	})
+
+	It("prints the failure with the prefix and then panics quietly", func() {
+		Expect(func() { ui.Failed("oh no") }).To(Panic())
+
+		Expect(fakeUI.Outputs).To(ContainElement(ContainSubstring("app1 | ")))
+		Expect(fakeUI.Outputs[len(fakeUI.Outputs)-1]).To(Equal("app1 | oh no"))
+	})
+})
@@ -49,19 +49,41 @@ type Printer interface {
 }
 
 type terminalUI struct {
-	stdin   io.Reader
-	printer Printer
-	logger  trace.Printer
+	stdin        io.Reader
+	printer      Printer
+	errPrinter   Printer
+	logger       trace.Printer
+	outputFormat OutputFormat
 }
 
 func NewUI(r io.Reader, printer Printer, logger trace.Printer) UI {
 	return &terminalUI{
-		stdin:   r,
-		printer: printer,
-		logger:  logger,
+		stdin:        r,
+		printer:      printer,
+		errPrinter:   printer,
+		logger:       logger,
+		outputFormat: OutputTable,
 	}
 }
 
+// NewUIWithOutputFormat returns a UI that prints its tables in the given
+// format. When that is a structured format, only the tables are printed
+// through printer; every other message goes through errPrinter, so that
+// the records can be read from the output as they are.
+func NewUIWithOutputFormat(r io.Reader, printer Printer, errPrinter Printer, logger trace.Printer, format OutputFormat) UI {
+	ui := &terminalUI{
+		stdin:        r,
+		printer:      printer,
+		errPrinter:   printer,
+		logger:       logger,
+		outputFormat: format,
+	}
+	if format.IsStructured() {
+		ui.errPrinter = errPrinter
+	}
+	return ui
+}
+
 func (ui *terminalUI) PrintPaginator(rows []string, err error) {
 	if err != nil {
 		ui.Failed(err.Error())
@@ -74,6 +96,14 @@ func (ui *terminalUI) PrintPaginator(rows []string, err error) {
 }
 
 func (ui *terminalUI) PrintCapturingNoOutput(message string, args ...interface{}) {
+	if ui.outputFormat.IsStructured() {
+		if len(args) > 0 {
+			message = fmt.Sprintf(message, args...)
+		}
+		ui.errPrinter.Print(message)
+		return
+	}
+
 	if len(args) == 0 {
 		fmt.Printf("%s", message)
 	} else {
@@ -83,9 +113,9 @@ func (ui *terminalUI) PrintCapturingNoOutput(message string, args ...interface{}
 
 func (ui *terminalUI) Say(message string, args ...interface{}) {
 	if len(args) == 0 {
-		ui.printer.Printf("%s\n", message)
+		ui.errPrinter.Printf("%s\n", message)
 	} else {
-		ui.printer.Printf(message+"\n", args...)
+		ui.errPrinter.Printf(message+"\n", args...)
 	}
 }
 
@@ -131,7 +161,7 @@ func (ui *terminalUI) Confirm(message string) bool {
 }
 
 func (ui *terminalUI) Ask(prompt string) (answer string) {
-	fmt.Printf("\n%s%s ", prompt, PromptColor(">"))
+	ui.printPrompt(prompt)
 
 	rd := bufio.NewReader(ui.stdin)
 	line, err := rd.ReadString('\n')
@@ -141,6 +171,16 @@ func (ui *terminalUI) Ask(prompt string) (answer string) {
 	return ""
 }
 
+// printPrompt prints prompt straight to the terminal, leaving it out of the
+// output captured for plugins, and out of structured output.
+func (ui terminalUI) printPrompt(prompt string) {
+	if ui.outputFormat.IsStructured() {
+		ui.errPrinter.Printf("\n%s%s ", prompt, PromptColor(">"))
+		return
+	}
+	fmt.Printf("\n%s%s ", prompt, PromptColor(">"))
+}
+
 func (ui *terminalUI) Ok() {
 	ui.Say(SuccessColor(T("OK")))
 }
@@ -171,6 +211,11 @@ func (ui *terminalUI) PanicQuietly() {
 }
 
 func (ui *terminalUI) ShowConfiguration(config coreconfig.Reader) {
+	if config.IsReadOnly() {
+		ui.Say(T("Changes to the config are not saved, because {{.EnvVar}} is set.",
+			map[string]interface{}{"EnvVar": coreconfig.ReadOnlyEnvVar}))
+	}
+
 	table := ui.Table([]string{"", ""})
 
 	if config.HasAPIEndpoint() {
@@ -180,7 +225,7 @@ func (ui *terminalUI) ShowConfiguration(config coreconfig.Reader) {
 				map[string]interface{}{
 					"APIEndpoint":      EntityNameColor(config.APIEndpoint()),
 					"APIVersionString": EntityNameColor(config.APIVersion()),
-				}),
+				})+valueSource(config, "Target"),
 		)
 	}
 
@@ -190,7 +235,11 @@ func (ui *terminalUI) ShowConfiguration(config coreconfig.Reader) {
 		return
 	}
 
-	table.Add(T("User:"), EntityNameColor(config.UserEmail()))
+	if config.UAAGrantType() == coreconfig.ClientCredentialsGrantType {
+		table.Add(T("Client:"), EntityNameColor(config.UAAOAuthClient())+valueSource(config, "UAAOAuthClient", "AccessToken"))
+	} else {
+		table.Add(T("User:"), EntityNameColor(config.UserEmail())+valueSource(config, "AccessToken"))
+	}
 
 	if !config.HasOrganization() && !config.HasSpace() {
 		table.Print()
@@ -205,7 +254,7 @@ func (ui *terminalUI) ShowConfiguration(config coreconfig.Reader) {
 	if config.HasOrganization() {
 		table.Add(
 			T("Org:"),
-			EntityNameColor(config.OrganizationFields().Name),
+			EntityNameColor(config.OrganizationFields().Name)+valueSource(config, "OrganizationFields"),
 		)
 	} else {
 		command := fmt.Sprintf("%s target -o Org", cf.Name)
@@ -221,7 +270,7 @@ func (ui *terminalUI) ShowConfiguration(config coreconfig.Reader) {
 	if config.HasSpace() {
 		table.Add(
 			T("Space:"),
-			EntityNameColor(config.SpaceFields().Name),
+			EntityNameColor(config.SpaceFields().Name)+valueSource(config, "SpaceFields"),
 		)
 	} else {
 		command := fmt.Sprintf("%s target -s SPACE", cf.Name)
@@ -234,30 +283,77 @@ func (ui *terminalUI) ShowConfiguration(config coreconfig.Reader) {
 	table.Print()
 }
 
+// valueSource notes the environment variable a value shown comes from, if
+// any of the fields it is made of is overridden.
+func valueSource(config coreconfig.Reader, fields ...string) string {
+	for _, field := range fields {
+		if envVar := config.ValueSource(field); envVar != "" {
+			return " " + T("(from {{.EnvVar}})", map[string]interface{}{"EnvVar": envVar})
+		}
+	}
+	return ""
+}
+
 func (ui *terminalUI) LoadingIndication() {
-	ui.printer.Print(".")
+	ui.errPrinter.Print(".")
 }
 
 func (ui *terminalUI) Table(headers []string) *UITable {
 	return &UITable{
-		UI:    ui,
-		Table: NewTable(headers),
+		UI:           ui,
+		Table:        NewTable(headers),
+		OutputFormat: ui.outputFormat,
+		printer:      ui.printer,
 	}
 }
 
 type UITable struct {
 	UI    UI
 	Table *Table
+
+	// OutputFormat is how Print prints the table. When it is a structured
+	// format, each row is printed as a record keyed by the field names of
+	// the columns.
+	OutputFormat OutputFormat
+
+	fieldNames []string
+	printer    Printer
 }
 
 func (u *UITable) Add(row ...string) {
 	u.Table.Add(row...)
 }
 
+// SetFieldNames gives the columns of the table the names their values have
+// in structured output. Unlike the headers these are not translated, so
+// programs can rely on them. Columns without a field name are named after
+// their header.
+func (u *UITable) SetFieldNames(names ...string) {
+	u.fieldNames = names
+}
+
+// FieldNames returns the names the columns have in structured output.
+func (u *UITable) FieldNames() []string {
+	names := make([]string, len(u.Table.headers))
+	for i, header := range u.Table.headers {
+		if i < len(u.fieldNames) && u.fieldNames[i] != "" {
+			names[i] = u.fieldNames[i]
+		} else {
+			names[i] = fieldName(header, i)
+		}
+	}
+	return names
+}
+
 // Print formats the table and then prints it to the UI specified at
 // the time of the construction. Afterwards the table is cleared,
 // becoming ready for another round of rows and printing.
 func (u *UITable) Print() {
+	if u.OutputFormat.IsStructured() {
+		u.printRecords()
+		return
+	}
+
 	result := &bytes.Buffer{}
 	t := u.Table
 
@@ -283,6 +379,21 @@ func (u *UITable) Print() {
 	}
 }
 
+func (u *UITable) printRecords() {
+	result := &bytes.Buffer{}
+	err := printRecords(result, u.OutputFormat, u.FieldNames(), u.Table.rows)
+	if err != nil {
+		u.UI.Failed(err.Error())
+	}
+	u.Table.rows = [][]string{}
+
+	if u.printer != nil {
+		u.printer.Print(result.String())
+	} else {
+		u.UI.Say("%s", strings.TrimSuffix(result.String(), "\n"))
+	}
+}
+
 func (ui *terminalUI) NotifyUpdateIfNeeded(config coreconfig.Reader) {
 	if !config.IsMinCLIVersion(cf.Version) {
 		ui.Say("")
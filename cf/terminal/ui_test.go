@@ -278,6 +278,18 @@ var _ = Describe("UI", func() {
 				Expect(output).To(ContainSubstrings([]string{"User:", "my-user-email"}))
 			})
 
+			Context("when authenticated as a client", func() {
+				BeforeEach(func() {
+					config.SetUAAGrantType("client_credentials")
+					config.SetUAAOAuthClient("ci-client")
+				})
+
+				It("tells the user which client is logged in", func() {
+					Expect(output).To(ContainSubstrings([]string{"Client:", "ci-client"}))
+					Expect(output).NotTo(ContainSubstrings([]string{"User:"}))
+				})
+			})
+
 			Context("when an org is targeted", func() {
 				BeforeEach(func() {
 					config.SetOrganizationFields(models.OrganizationFields{
@@ -341,6 +353,47 @@ var _ = Describe("UI", func() {
 		})
 	})
 
+	Context("when values come from the environment", func() {
+		var output []string
+
+		BeforeEach(func() {
+			os.Setenv("CF_API", "https://env.example.org")
+			os.Setenv("CF_API_VERSION", "2.54.0")
+			os.Setenv("CF_ORG", "env-org")
+			os.Setenv("CF_ORG_GUID", "env-org-guid")
+			os.Setenv(coreconfig.ReadOnlyEnvVar, "true")
+
+			config := testconfig.NewRepositoryWithAccessToken(coreconfig.TokenInfo{Email: "my-user-email"})
+			config.SetSpaceFields(models.SpaceFields{Name: "my-space", GUID: "space-guid"})
+
+			output = io_helpers.CaptureOutput(func() {
+				ui := NewUI(os.Stdin, NewTeePrinter(), fakeLogger)
+				ui.ShowConfiguration(config)
+			})
+		})
+
+		AfterEach(func() {
+			os.Unsetenv("CF_API")
+			os.Unsetenv("CF_API_VERSION")
+			os.Unsetenv("CF_ORG")
+			os.Unsetenv("CF_ORG_GUID")
+			os.Unsetenv(coreconfig.ReadOnlyEnvVar)
+		})
+
+		It("tells the user which environment variables they come from", func() {
+			Expect(output).To(ContainSubstrings(
+				[]string{"API endpoint:", "https://env.example.org", "(from CF_API)"},
+				[]string{"Org:", "env-org", "(from CF_ORG)"},
+			))
+			Expect(output).NotTo(ContainSubstrings([]string{"Space:", "from"}))
+			Expect(output).NotTo(ContainSubstrings([]string{"User:", "from"}))
+		})
+
+		It("tells the user that changes to the config are not saved", func() {
+			Expect(output).To(ContainSubstrings([]string{"not saved", "CF_READ_ONLY_CONFIG"}))
+		})
+	})
+
 	Describe("failing", func() {
 		It("panics with a specific string", func() {
 			io_helpers.CaptureOutput(func() {
@@ -442,4 +495,87 @@ var _ = Describe("UI", func() {
 			Expect(output[0]).To(Equal(""))
 		})
 	})
+
+	Describe("printing tables in a structured output format", func() {
+		var (
+			stdout *gbytes.Buffer
+			stderr *gbytes.Buffer
+			ui     UI
+		)
+
+		newUI := func(format OutputFormat) UI {
+			return NewUIWithOutputFormat(os.Stdin, NewWriterPrinter(stdout), NewWriterPrinter(stderr), fakeLogger, format)
+		}
+
+		BeforeEach(func() {
+			stdout = gbytes.NewBuffer()
+			stderr = gbytes.NewBuffer()
+		})
+
+		It("prints the rows as JSON records keyed by the field names of the columns", func() {
+			ui = newUI(OutputJSON)
+			table := ui.Table([]string{"name", "requested state", "urls"})
+			table.SetFieldNames("name", "state")
+			table.Add(EntityNameColor("my-app"), "started", "my-app.example.com")
+			table.Add("other-app", "stopped")
+			table.Print()
+
+			Expect(string(stdout.Contents())).To(MatchJSON(`[
+				{"name": "my-app", "state": "started", "urls": "my-app.example.com"},
+				{"name": "other-app", "state": "stopped", "urls": ""}
+			]`))
+			Expect(string(stdout.Contents())).To(MatchRegexp(`"name": "my-app",\s+"state": "started",\s+"urls"`))
+		})
+
+		It("prints an empty JSON array for a table without rows", func() {
+			ui = newUI(OutputJSON)
+			ui.Table([]string{"name"}).Print()
+
+			Expect(string(stdout.Contents())).To(MatchJSON(`[]`))
+		})
+
+		It("prints the rows as a YAML list", func() {
+			ui = newUI(OutputYAML)
+			table := ui.Table([]string{"", "bound apps"})
+			table.Add("#0", "my-app")
+			table.Print()
+
+			Expect(string(stdout.Contents())).To(Equal("---\n- column_1: '#0'\n  bound_apps: my-app\n"))
+		})
+
+		It("prints every other message to the error printer", func() {
+			ui = newUI(OutputJSON)
+			ui.Say("Getting apps as user...")
+			ui.Ok()
+
+			Expect(stdout.Contents()).To(BeEmpty())
+			Expect(stderr).To(gbytes.Say("Getting apps as user..."))
+			Expect(stderr).To(gbytes.Say("OK"))
+		})
+
+		It("prints tables and messages as before with the table output format", func() {
+			ui = newUI(OutputTable)
+			ui.Say("Getting apps as user...")
+			table := ui.Table([]string{"name"})
+			table.Add("my-app")
+			table.Print()
+
+			Expect(stderr.Contents()).To(BeEmpty())
+			Expect(stdout).To(gbytes.Say("Getting apps as user..."))
+			Expect(stdout).To(gbytes.Say("name\nmy-app"))
+		})
+	})
+
+	Describe("ParseOutputFormat", func() {
+		It("accepts json, yaml and table in any case", func() {
+			Expect(ParseOutputFormat("JSON")).To(Equal(OutputJSON))
+			Expect(ParseOutputFormat("yaml")).To(Equal(OutputYAML))
+			Expect(ParseOutputFormat("table")).To(Equal(OutputTable))
+		})
+
+		It("returns an error for other formats", func() {
+			_, err := ParseOutputFormat("xml")
+			Expect(err).To(MatchError(ContainSubstring("Invalid output format 'xml'")))
+		})
+	})
 })
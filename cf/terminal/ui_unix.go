@@ -32,7 +32,7 @@ func (ui terminalUI) AskForPassword(prompt string) (passwd string) {
 	sig := make(chan os.Signal, 10)
 
 	// Display the prompt.
-	fmt.Printf("\n%s%s ", prompt, PromptColor(">"))
+	ui.printPrompt(prompt)
 
 	// File descriptors for stdin, stdout, and stderr.
 	fd := []uintptr{os.Stdin.Fd(), os.Stdout.Fd(), os.Stderr.Fd()}
@@ -13,25 +13,42 @@ func Merge(collection, otherCollection Map) Map {
 	return mergedMap
 }
 
+// DeepMerge merges maps from left to right, so that values in later maps
+// take precedence over values in earlier ones. For a key present in both the
+// merged result and the next map:
+//
+//   - two maps are deep merged recursively
+//   - two lists whose elements are all maps with a "name" key (such as the
+//     applications in a manifest) are merged by name: elements with the same
+//     name are deep merged in place and new elements are appended in order
+//   - any two other lists are concatenated
+//   - anything else, including values of differing kinds and nil, is
+//     replaced by the later value
 func DeepMerge(maps ...Map) Map {
 	mergedMap := NewMap()
 	return Reduce(maps, mergedMap, mergeReducer)
 }
 
 func mergeReducer(key, val interface{}, reduced Map) Map {
+	existing := reduced.Get(key)
+
 	switch {
 	case reduced.Has(key) == false:
 		reduced.Set(key, val)
 		return reduced
 
-	case IsMappable(val):
-		maps := []Map{NewMap(reduced.Get(key)), NewMap(val)}
+	case existing == nil || val == nil:
+		reduced.Set(key, val)
+		return reduced
+
+	case IsMappable(val) && IsMappable(existing):
+		maps := []Map{NewMap(existing), NewMap(val)}
 		mergedMap := Reduce(maps, NewMap(), mergeReducer)
 		reduced.Set(key, mergedMap)
 		return reduced
 
-	case IsSliceable(val):
-		reduced.Set(key, append(reduced.Get(key).([]interface{}), val.([]interface{})...))
+	case IsSliceable(val) && IsSliceable(existing):
+		reduced.Set(key, mergeSlices(existing.([]interface{}), val.([]interface{})))
 		return reduced
 
 	default:
@@ -40,6 +57,54 @@ func mergeReducer(key, val interface{}, reduced Map) Map {
 	}
 }
 
+func mergeSlices(slice, otherSlice []interface{}) []interface{} {
+	if !isNamedSlice(slice) || !isNamedSlice(otherSlice) {
+		merged := make([]interface{}, 0, len(slice)+len(otherSlice))
+		merged = append(merged, slice...)
+		return append(merged, otherSlice...)
+	}
+
+	merged := make([]interface{}, len(slice))
+	copy(merged, slice)
+
+	for _, item := range otherSlice {
+		name := NewMap(item).Get("name")
+
+		found := false
+		for i, existing := range merged {
+			if NewMap(existing).Get("name") == name {
+				merged[i] = DeepMerge(NewMap(existing), NewMap(item))
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			merged = append(merged, item)
+		}
+	}
+
+	return merged
+}
+
+func isNamedSlice(slice []interface{}) bool {
+	if len(slice) == 0 {
+		return false
+	}
+
+	for _, item := range slice {
+		if item == nil || !IsMappable(item) {
+			return false
+		}
+
+		if _, ok := NewMap(item).Get("name").(string); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
 type Reducer func(key, val interface{}, reducedVal Map) Map
 
 func Reduce(collections []Map, resultVal Map, cb Reducer) Map {
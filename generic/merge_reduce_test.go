@@ -0,0 +1,96 @@
+package generic_test
+
+import (
+	. "github.com/cloudfoundry/cli/generic"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func init() {
+	Describe("DeepMerge", func() {
+		It("merges nested maps key by key", func() {
+			base := NewMap(map[interface{}]interface{}{
+				"env": map[interface{}]interface{}{"A": "1", "B": "2"},
+			})
+			overlay := NewMap(map[interface{}]interface{}{
+				"env": map[interface{}]interface{}{"B": "3", "C": "4"},
+			})
+
+			merged := DeepMerge(base, overlay)
+			Expect(merged.Get("env")).To(Equal(NewMap(map[interface{}]interface{}{
+				"A": "1", "B": "3", "C": "4",
+			})))
+		})
+
+		It("concatenates lists of plain values", func() {
+			base := NewMap(map[interface{}]interface{}{"services": []interface{}{"db"}})
+			overlay := NewMap(map[interface{}]interface{}{"services": []interface{}{"cache"}})
+
+			merged := DeepMerge(base, overlay)
+			Expect(merged.Get("services")).To(Equal([]interface{}{"db", "cache"}))
+		})
+
+		It("does not modify the lists of the maps being merged", func() {
+			services := make([]interface{}, 1, 10)
+			services[0] = "db"
+			base := NewMap(map[interface{}]interface{}{"services": services})
+
+			DeepMerge(base, NewMap(map[interface{}]interface{}{"services": []interface{}{"cache"}}))
+			DeepMerge(base, NewMap(map[interface{}]interface{}{"services": []interface{}{"queue"}}))
+			Expect(services[:2]).To(Equal([]interface{}{"db", nil}))
+		})
+
+		It("merges lists of named maps by name, keeping the order of the first list", func() {
+			base := NewMap(map[interface{}]interface{}{
+				"applications": []interface{}{
+					map[interface{}]interface{}{"name": "web", "instances": 1, "memory": "256M"},
+					map[interface{}]interface{}{"name": "worker", "instances": 1},
+				},
+			})
+			overlay := NewMap(map[interface{}]interface{}{
+				"applications": []interface{}{
+					map[interface{}]interface{}{"name": "worker", "instances": 5},
+					map[interface{}]interface{}{"name": "admin"},
+				},
+			})
+
+			merged := DeepMerge(base, overlay)
+			Expect(merged.Get("applications")).To(Equal([]interface{}{
+				map[interface{}]interface{}{"name": "web", "instances": 1, "memory": "256M"},
+				NewMap(map[interface{}]interface{}{"name": "worker", "instances": 5}),
+				map[interface{}]interface{}{"name": "admin"},
+			}))
+		})
+
+		It("concatenates lists of maps when not every element has a name", func() {
+			base := NewMap(map[interface{}]interface{}{
+				"routes": []interface{}{map[interface{}]interface{}{"route": "a.example.com"}},
+			})
+			overlay := NewMap(map[interface{}]interface{}{
+				"routes": []interface{}{map[interface{}]interface{}{"route": "b.example.com"}},
+			})
+
+			merged := DeepMerge(base, overlay)
+			Expect(merged.Get("routes")).To(HaveLen(2))
+		})
+
+		It("replaces values of differing kinds with the later value", func() {
+			base := NewMap(map[interface{}]interface{}{
+				"env":      map[interface{}]interface{}{"A": "1"},
+				"services": "db",
+				"command":  "start.sh",
+			})
+			overlay := NewMap(map[interface{}]interface{}{
+				"env":      "not-a-map",
+				"services": []interface{}{"cache"},
+				"command":  nil,
+			})
+
+			merged := DeepMerge(base, overlay)
+			Expect(merged.Get("env")).To(Equal("not-a-map"))
+			Expect(merged.Get("services")).To(Equal([]interface{}{"cache"}))
+			Expect(merged.Has("command")).To(BeTrue())
+			Expect(merged.Get("command")).To(BeNil())
+		})
+	})
+}
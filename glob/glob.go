@@ -79,6 +79,97 @@ func CompileGlob(pat string) (glob Glob, err error) {
 	return
 }
 
+// CompileIgnoreGlob is like CompileGlob, but reads pat the way patterns in a
+// .gitignore file are read:
+//  - `\` escapes the character after it instead of separating components
+//  - `[...]` matches a single char of a set, or not of it with `[!...]`
+//  - `**` only matches across components as a whole component, as in
+//    `**/a`, `a/**` or `a/**/b`; elsewhere it is the same as `*`
+func CompileIgnoreGlob(pat string) (glob Glob, err error) {
+	s, err := translateIgnoreGlob(pat)
+	if err != nil {
+		return
+	}
+	r, err := regexp.Compile(s)
+	if err != nil {
+		return
+	}
+	glob = Glob{pat, r}
+	return
+}
+
+func translateIgnoreGlob(pat string) (string, error) {
+	chars := []rune(pat)
+	outs := []string{"^"}
+
+	for i := 0; i < len(chars); i++ {
+		switch c := chars[i]; c {
+		default:
+			outs = append(outs, regexp.QuoteMeta(string(c)))
+		case '\\':
+			if i+1 == len(chars) {
+				return "", GlobError(pat)
+			}
+			i++
+			outs = append(outs, regexp.QuoteMeta(string(chars[i])))
+		case '?':
+			outs = append(outs, `[^/]`)
+		case '*':
+			wholeComponent := i+1 < len(chars) && chars[i+1] == '*' &&
+				(i == 0 || chars[i-1] == '/') &&
+				(i+2 == len(chars) || chars[i+2] == '/')
+
+			switch {
+			case wholeComponent && i+2 == len(chars):
+				outs = append(outs, `.*`)
+				i++
+			case wholeComponent:
+				outs = append(outs, `(.*/)?`)
+				i += 2
+			default:
+				outs = append(outs, `[^/]*`)
+			}
+		case '[':
+			class, length := translateCharClass(chars[i:])
+			if length == 0 {
+				outs = append(outs, `\[`)
+				continue
+			}
+			outs = append(outs, class)
+			i += length - 1
+		}
+	}
+
+	return strings.Join(outs, "") + "$", nil
+}
+
+// translateCharClass translates the `[...]` set chars starts with, returning
+// the number of chars it takes up, or 0 if it is not closed.
+func translateCharClass(chars []rune) (string, int) {
+	outs := []string{"["}
+	i := 1
+	if i < len(chars) && (chars[i] == '!' || chars[i] == '^') {
+		outs = append(outs, "^/")
+		i++
+	}
+
+	for first := i; i < len(chars); i++ {
+		switch c := chars[i]; {
+		case c == ']' && i > first:
+			return strings.Join(outs, "") + "]", i + 1
+		case c == '\\' && i+1 < len(chars):
+			i++
+			outs = append(outs, regexp.QuoteMeta(string(chars[i])))
+		case c == '-':
+			outs = append(outs, "-")
+		default:
+			outs = append(outs, regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	return "", 0
+}
+
 // MustCompileGlob is like CompileGlob, but it panics if an error occurs,
 // simplifying safe initialization of global variables holding glob patterns.
 func MustCompileGlob(pat string) Glob {
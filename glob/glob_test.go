@@ -40,6 +40,35 @@ var nonMatches = [][]string{
 	{"/a**", "/", "/ba"},
 }
 
+var ignoreMatches = [][]string{
+	{"a/b", "a/b"},
+	{"*.so", "a.so", ".so"},
+	{"**/a", "a", "b/a", "b/c/a"},
+	{"a/**", "a/b", "a/b/c"},
+	{"a/**/b", "a/b", "a/x/b", "a/x/y/b"},
+	{"a**b", "ab", "axxb"},
+	{"[ab].c", "a.c", "b.c"},
+	{"[!ab].c", "c.c"},
+	{"[a-c]", "b"},
+	{"[]]", "]"},
+	{`\#a`, "#a"},
+	{`\!a`, "!a"},
+	{`a\*`, "a*"},
+	{`a\ `, "a "},
+	{"[a", "[a"},
+}
+
+var ignoreNonMatches = [][]string{
+	{"a/b", "a/b/c", "x/a/b"},
+	{"*.so", "a/b.so"},
+	{"a/**", "a", "b/a/c"},
+	{"a/**/b", "a/b/c", "b"},
+	{"a**b", "a/b", "a/x/b"},
+	{"[ab].c", "c.c", "ab.c"},
+	{"[!ab].c", "a.c", "/.c"},
+	{`a\*`, "ab"},
+}
+
 var _ = Describe("Glob", func() {
 	It("translates globs to regexes", func() {
 		for _, parts := range globs {
@@ -74,6 +103,37 @@ var _ = Describe("Glob", func() {
 			}
 		}
 	})
+
+	Describe("CompileIgnoreGlob", func() {
+		It("creates regexes that match correct file paths", func() {
+			for _, parts := range ignoreMatches {
+				pat, paths := parts[0], parts[1:]
+				glob, err := CompileIgnoreGlob(pat)
+
+				Expect(err).NotTo(HaveOccurred())
+				for _, path := range paths {
+					Expect(glob.Match(path)).To(BeTrue(), "path %q should match %q", path, pat)
+				}
+			}
+		})
+
+		It("creates regexes that do not match incorrect file paths", func() {
+			for _, parts := range ignoreNonMatches {
+				pat, paths := parts[0], parts[1:]
+				glob, err := CompileIgnoreGlob(pat)
+
+				Expect(err).NotTo(HaveOccurred())
+				for _, path := range paths {
+					Expect(glob.Match(path)).To(BeFalse(), "path %q should not match %q", path, pat)
+				}
+			}
+		})
+
+		It("returns an error for a pattern that ends in a backslash", func() {
+			_, err := CompileIgnoreGlob(`a\`)
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
 
 func TestGlobSuite(t *testing.T) {
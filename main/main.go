@@ -45,6 +45,23 @@ func main() {
 	os.Args = newArgs
 	traceLogger = trace.NewLogger(isVerbose, traceEnv, "")
 
+	commandsloader.Load()
+
+	//handles `cf --output json|yaml|table [COMMAND] ...` and
+	//`cf --profile NAME [COMMAND] ...`, which applies to plugin commands too
+	newArgs, globalOptions := handleGlobalOptions(os.Args, "output", "profile")
+	os.Args = newArgs
+	outputFormatArg, ok := globalOptions["output"]
+	if !ok {
+		outputFormatArg = string(terminal.OutputTable)
+	}
+	if profile, ok := globalOptions["profile"]; ok {
+		os.Setenv("CF_PROFILE", profile)
+	}
+	if len(os.Args) == 1 {
+		os.Args = []string{os.Args[0], "help"}
+	}
+
 	errFunc := func(err error) {
 		if err != nil {
 			ui := terminal.NewUI(os.Stdin, terminal.NewTeePrinter(), traceLogger)
@@ -60,7 +77,15 @@ func main() {
 
 	traceLogger = trace.NewLogger(isVerbose, traceEnv, traceConfigVal)
 
-	deps := commandregistry.NewDependency(traceLogger)
+	outputFormat, err := terminal.ParseOutputFormat(outputFormatArg)
+	if err != nil {
+		ui := terminal.NewUI(os.Stdin, terminal.NewTeePrinter(), traceLogger)
+		ui.Say(terminal.FailureColor(T("FAILED")))
+		ui.Say(T("Incorrect Usage") + "\n\n" + err.Error())
+		os.Exit(1)
+	}
+
+	deps := commandregistry.NewDependencyWithOutputFormat(traceLogger, outputFormat)
 	defer handlePanics(deps.TeePrinter, deps.Logger)
 	defer deps.Config.Close()
 
@@ -81,8 +106,6 @@ func main() {
 
 	warningsCollector := net.NewWarningsCollector(deps.UI, warningProducers...)
 
-	commandsloader.Load()
-
 	//run core command
 	cmdName := os.Args[1]
 	cmd := cmdRegistry.FindCommand(cmdName)
@@ -205,3 +228,58 @@ func handleVerbose(args []string) ([]string, bool) {
 
 	return args, verbose
 }
+
+// handleGlobalOptions takes the global options with the given names out of
+// args. After the name of a command they are only taken out for core commands
+// that do not have an option of the same name of their own, like curl does
+// with --output.
+func handleGlobalOptions(args []string, names ...string) ([]string, map[string]string) {
+	values := map[string]string{}
+	var commandName string
+
+	newArgs := []string{args[0]}
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+
+		if commandName == "" && !strings.HasPrefix(arg, "-") {
+			commandName = arg
+			newArgs = append(newArgs, arg)
+			continue
+		}
+
+		found := false
+		for _, name := range names {
+			if commandName != "" && !hasGlobalOption(commandName, name) {
+				continue
+			}
+
+			switch {
+			case arg == "--"+name && i+1 < len(args):
+				values[name] = args[i+1]
+				i++
+				found = true
+			case strings.HasPrefix(arg, "--"+name+"="):
+				values[name] = strings.TrimPrefix(arg, "--"+name+"=")
+				found = true
+			}
+			if found {
+				break
+			}
+		}
+
+		if !found {
+			newArgs = append(newArgs, arg)
+		}
+	}
+
+	return newArgs, values
+}
+
+func hasGlobalOption(cmdName string, name string) bool {
+	cmd := cmdRegistry.FindCommand(cmdName)
+	if cmd == nil {
+		return false
+	}
+	_, hasOwnOption := cmd.MetaData().Flags[name]
+	return !hasOwnOption
+}
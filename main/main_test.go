@@ -155,6 +155,26 @@ var _ = Describe("main", func() {
 		})
 	})
 
+	Describe("Prints tables as records with --output", func() {
+		It("prints every message other than tables to stderr", func() {
+			dir, err := os.Getwd()
+			Expect(err).ToNot(HaveOccurred())
+			fullDir := filepath.Join(dir, "..", "fixtures") //set home to a config w/o targeted api
+			result := CfWith_CF_HOME(fullDir, "apps", "--output", "json")
+
+			Eventually(result.Err).Should(Say("No API endpoint set."))
+			Eventually(result).Should(Exit(1))
+			Expect(result.Out.Contents()).To(BeEmpty())
+		})
+
+		It("exits non-zero for an unknown output format", func() {
+			result := Cf("--output", "xml", "apps")
+
+			Eventually(result.Out).Should(Say("Invalid output format 'xml'"))
+			Eventually(result).Should(Exit(1))
+		})
+	})
+
 	Describe("exit codes", func() {
 		It("exits non-zero when an unknown command is invoked", func() {
 			result := Cf("some-command-that-should-never-actually-be-a-real-thing-i-can-use")
@@ -12,6 +12,9 @@ type FakeManifestRepository struct {
 		Manifest *manifest.Manifest
 		Error    error
 	}
+	ReadManifestsArgs struct {
+		Paths []string
+	}
 }
 
 func (repo *FakeManifestRepository) ReadManifest(inputPath string) (m *manifest.Manifest, err error) {
@@ -25,3 +28,15 @@ func (repo *FakeManifestRepository) ReadManifest(inputPath string) (m *manifest.
 	err = repo.ReadManifestReturns.Error
 	return
 }
+
+func (repo *FakeManifestRepository) ReadManifests(inputPaths []string) (m *manifest.Manifest, err error) {
+	repo.ReadManifestsArgs.Paths = inputPaths
+	if repo.ReadManifestReturns.Manifest != nil {
+		m = repo.ReadManifestReturns.Manifest
+	} else {
+		m = manifest.NewEmptyManifest()
+	}
+
+	err = repo.ReadManifestReturns.Error
+	return
+}